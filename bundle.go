@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/aiyi/swagger-gin/spec"
+)
+
+// bundleCmd writes a spec split across several files as one self-contained document,
+// the references to the other files point to local copies of what they reference
+func bundleCmd(args []string) int {
+	flags := flag.NewFlagSet("bundle", flag.ExitOnError)
+	opts, err := loadOpts(args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	bindLoaderFlags(flags, &opts)
+	var bundleOpts spec.BundleOptions
+	flags.BoolVar(&bundleOpts.LiftInline, "lift-inline", false, "move the inline object schemas to definitions named after where they are")
+	output := flags.String("output", "", "the file the bundled spec is written to, the standard output by default")
+	flags.Parse(args)
+
+	doc, err := spec.LoadWithOptions(opts.Spec, spec.LoaderOptions{BaseDir: opts.BaseDir, URLMappings: opts.URLMappings})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	bundled, err := doc.Bundled(bundleOpts)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, bundled.Raw(), "", "  "); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	buf.WriteString("\n")
+	if *output == "" {
+		os.Stdout.Write(buf.Bytes())
+		return 0
+	}
+	if err := ioutil.WriteFile(*output, buf.Bytes(), 0644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
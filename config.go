@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/aiyi/swagger-gin/generator"
+	"github.com/aiyi/swagger-gin/swag"
+)
+
+// configFiles are looked up in the current directory when no -config is given
+var configFiles = []string{".swagger-gin.yml", ".swagger-gin.yaml", ".swagger-gin.json"}
+
+// config is the content of the configuration file, the generator options
+// and the settings of the commands built around the generator
+type config struct {
+	generator.GenOpts
+	// LintRules set the severity of the lint rules by name, off disables a rule
+	LintRules map[string]string `json:"lintRules,omitempty"`
+}
+
+// defaultOpts are the options neither the configuration file nor the flags set
+func defaultOpts() generator.GenOpts {
+	return generator.GenOpts{
+		Spec:          "./swagger.json",
+		Target:        "./",
+		APIPackage:    "operations",
+		ModelPackage:  "models",
+		ServerPackage: "restapi",
+	}
+}
+
+// loadOpts returns the generator options of the configuration, see loadConfig
+func loadOpts(args []string) (generator.GenOpts, error) {
+	cfg, err := loadConfig(args)
+	return cfg.GenOpts, err
+}
+
+// loadConfig returns the default options overridden by the configuration file, the
+// file is the one given with -config in args or the first of configFiles found.
+// The flags are bound to the options afterwards, so they take precedence over both.
+func loadConfig(args []string) (config, error) {
+	cfg := config{GenOpts: defaultOpts()}
+	path, explicit := configFlag(args)
+	if !explicit {
+		for _, name := range configFiles {
+			if _, err := os.Stat(name); err == nil {
+				path = name
+				break
+			}
+		}
+	}
+	if path == "" {
+		return cfg, nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+	if swag.IsYAML(path, data) {
+		if data, err = swag.YAMLToJSON(data); err != nil {
+			return cfg, fmt.Errorf("%s: %v", path, err)
+		}
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return cfg, fmt.Errorf("%s: %v", path, err)
+	}
+	return cfg, nil
+}
+
+// configFlag finds the -config flag before the flags are parsed, since
+// the configuration file provides the defaults of the other flags.
+// The value following a flag is skipped, so it's never taken for a flag.
+func configFlag(args []string) (string, bool) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			break
+		}
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		name := strings.TrimLeft(arg, "-")
+		if name == "config" && i+1 < len(args) {
+			return args[i+1], true
+		}
+		if strings.HasPrefix(name, "config=") {
+			return strings.TrimPrefix(name, "config="), true
+		}
+		if !strings.Contains(name, "=") && i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+			i++
+		}
+	}
+	return "", false
+}
+
+// bindLoaderFlags binds the flags telling how the spec is loaded
+func bindLoaderFlags(flags *flag.FlagSet, opts *generator.GenOpts) {
+	flags.String("config", "", "the configuration file, "+strings.Join(configFiles, ", ")+" in the current directory by default")
+	flags.StringVar(&opts.Spec, "spec", opts.Spec, "the spec file to use, json or yaml")
+	flags.StringVar(&opts.BaseDir, "base-dir", opts.BaseDir, "the directory relative $refs are resolved against, defaults to the directory of the spec")
+	flags.Var(&mapFlag{values: &opts.URLMappings}, "map-url", "load the documents under a url prefix from a local copy, as url=path, can be repeated")
+}
+
+// bindGenFlags binds a flag to every generator option
+func bindGenFlags(flags *flag.FlagSet, opts *generator.GenOpts) {
+	bindLoaderFlags(flags, opts)
+	flags.StringVar(&opts.Target, "target", opts.Target, "the directory for generating the files")
+	flags.Var(&listFlag{values: &opts.Tags}, "tag", "only generate the operations with this tag, can be repeated")
+	flags.Var(&listFlag{values: &opts.Operations}, "operation", "only generate the operation with this operationId, can be repeated")
+	flags.StringVar(&opts.APIPackage, "api-package", opts.APIPackage, "the package of the operations")
+	flags.StringVar(&opts.ModelPackage, "model-package", opts.ModelPackage, "the package of the models")
+	flags.StringVar(&opts.ServerPackage, "server-package", opts.ServerPackage, "the package of the server")
+	flags.StringVar(&opts.ClientPackage, "client-package", opts.ClientPackage, "the package of the client")
+	flags.StringVar(&opts.Principal, "principal", opts.Principal, "the type of the authenticated principal")
+	flags.StringVar(&opts.ImportPath, "import-path", opts.ImportPath, "the import path of the target, taken from the go.mod above the target by default")
+	flags.Var(&mapFlag{values: &opts.TypeMapping}, "type-mapping", "map a format, or a type without a format, to a go type, as format=gotype, can be repeated")
+	flags.Var(&mapFlag{values: &opts.Imports}, "import", "import the package of the mapped types, as alias=path, can be repeated")
+	flags.BoolVar(&opts.DumpData, "dump-data", opts.DumpData, "print the data the templates are rendered with")
+}
+
+// listFlag is a repeatable flag, its first value replaces the list of the configuration file
+type listFlag struct {
+	values *[]string
+	set    bool
+}
+
+func (f *listFlag) String() string {
+	if f.values == nil {
+		return ""
+	}
+	return strings.Join(*f.values, ",")
+}
+
+func (f *listFlag) Set(value string) error {
+	if !f.set {
+		*f.values = nil
+		f.set = true
+	}
+	*f.values = append(*f.values, value)
+	return nil
+}
+
+// mapFlag is a repeatable key=value flag, its first value replaces the map of the configuration file
+type mapFlag struct {
+	values *map[string]string
+	set    bool
+}
+
+func (f *mapFlag) String() string {
+	if f.values == nil {
+		return ""
+	}
+	pairs := make([]string, 0, len(*f.values))
+	for k, v := range *f.values {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (f *mapFlag) Set(value string) error {
+	i := strings.LastIndex(value, "=")
+	if i <= 0 || i == len(value)-1 {
+		return fmt.Errorf("invalid value %q, expected key=value", value)
+	}
+	if !f.set || *f.values == nil {
+		*f.values = make(map[string]string)
+		f.set = true
+	}
+	(*f.values)[value[:i]] = value[i+1:]
+	return nil
+}
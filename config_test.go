@@ -0,0 +1,30 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigFlag(t *testing.T) {
+	cases := []struct {
+		args     []string
+		path     string
+		explicit bool
+	}{
+		{[]string{"-config", "gen.json"}, "gen.json", true},
+		{[]string{"--config=gen.yml"}, "gen.yml", true},
+		{[]string{"-spec", "api.yaml", "-config", "gen.json"}, "gen.json", true},
+		{[]string{"-dump-data", "-config", "gen.json"}, "gen.json", true},
+		{[]string{"-spec=api.yaml", "-target", "out", "-config=gen.json"}, "gen.json", true},
+		// the value of a flag isn't a flag
+		{[]string{"-spec", "config", "-target", "out"}, "", false},
+		{[]string{"-spec", "api.yaml", "--", "-config", "gen.json"}, "", false},
+		{nil, "", false},
+	}
+	for _, c := range cases {
+		path, explicit := configFlag(c.args)
+		assert.Equal(t, c.path, path, "%v", c.args)
+		assert.Equal(t, c.explicit, explicit, "%v", c.args)
+	}
+}
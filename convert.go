@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/aiyi/swagger-gin/spec"
+)
+
+// convertCmd converts a spec to another version of the specification and prints it,
+// or writes it to the -output file. OpenAPI 3.0 specs are converted to swagger 2.0
+// on load, so a 3.0 spec can be converted to 2.0 and back.
+func convertCmd(args []string) int {
+	flags := flag.NewFlagSet("convert", flag.ExitOnError)
+	opts, err := loadOpts(args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	bindLoaderFlags(flags, &opts)
+	to := flags.String("to", "openapi3", "the version to convert the spec to, openapi3 or swagger2")
+	output := flags.String("output", "", "the file the converted spec is written to, the standard output by default")
+	flags.Parse(args)
+
+	if *to != "openapi3" && *to != "swagger2" {
+		fmt.Fprintf(os.Stderr, "unknown version %q, expected openapi3 or swagger2\n", *to)
+		flags.Usage()
+		return 2
+	}
+
+	doc, err := spec.LoadWithOptions(opts.Spec, spec.LoaderOptions{BaseDir: opts.BaseDir, URLMappings: opts.URLMappings})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	data := doc.Raw()
+	if *to == "openapi3" {
+		data, err = spec.ConvertToOpenAPI3(doc.Spec())
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, "", "  "); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	buf.WriteString("\n")
+	if *output == "" {
+		os.Stdout.Write(buf.Bytes())
+		return 0
+	}
+	if err := ioutil.WriteFile(*output, buf.Bytes(), 0644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/aiyi/swagger-gin/diff"
+	"github.com/aiyi/swagger-gin/spec"
+)
+
+// diffCmd compares two versions of a spec and prints the changes, it fails when a change
+// breaks the clients so it can gate the releases in CI
+func diffCmd(args []string) int {
+	flags := flag.NewFlagSet("diff", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: swagger-gin diff [flags] old.json new.json")
+		flags.PrintDefaults()
+	}
+	urlMappings := make(map[string]string)
+	flags.Var(&mapFlag{values: &urlMappings}, "map-url", "load the documents under a url prefix from a local copy, as url=path, can be repeated")
+	format := flags.String("format", "text", "the output format, text, json or markdown")
+	failOn := flags.String("fail-on", "breaking", "the changes failing the command, breaking, any or none")
+	flags.Parse(args)
+
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return 2
+	}
+	if *format != "text" && *format != "json" && *format != "markdown" {
+		fmt.Fprintf(os.Stderr, "unknown format %q, expected text, json or markdown\n", *format)
+		return 2
+	}
+	if *failOn != "breaking" && *failOn != "any" && *failOn != "none" {
+		fmt.Fprintf(os.Stderr, "unknown -fail-on %q, expected breaking, any or none\n", *failOn)
+		return 2
+	}
+
+	var docs [2]*spec.Document
+	for i, path := range flags.Args() {
+		doc, err := spec.LoadWithOptions(path, spec.LoaderOptions{URLMappings: urlMappings})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		docs[i] = doc
+	}
+	report := diff.Compare(docs[0], docs[1])
+
+	switch *format {
+	case "json":
+		out := struct {
+			Breaking int           `json:"breaking"`
+			Changes  []diff.Change `json:"changes"`
+		}{len(report.Breaking()), report.Changes}
+		if out.Changes == nil {
+			out.Changes = []diff.Change{}
+		}
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		fmt.Println(string(data))
+	case "markdown":
+		fmt.Print(report.Markdown())
+	default:
+		for _, c := range report.Changes {
+			severity := "change  "
+			if c.Breaking {
+				severity = "BREAKING"
+			}
+			fmt.Println(severity, c)
+		}
+		fmt.Fprintf(os.Stderr, "%d change(s), %d breaking\n", len(report.Changes), len(report.Breaking()))
+	}
+
+	switch {
+	case *failOn == "any" && len(report.Changes) > 0:
+		return 1
+	case *failOn == "breaking" && report.HasBreaking():
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,470 @@
+// Package diff compares two versions of a spec and classifies every change as
+// breaking or not for the clients of the API.
+//
+// What breaks depends on the direction of the data: a request can't become stricter
+// (a parameter that became required, a narrowed enum, a tightened constraint) and a
+// response can't become looser (a removed field, a widened enum, a changed type),
+// since the clients written against the old spec would fail.
+package diff
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aiyi/swagger-gin/jsonpointer"
+	"github.com/aiyi/swagger-gin/spec"
+)
+
+// Kind is the kind of a change
+type Kind string
+
+// The kinds of changes
+const (
+	OperationAdded      Kind = "operation-added"
+	OperationRemoved    Kind = "operation-removed"
+	OperationDeprecated Kind = "operation-deprecated"
+	ParameterAdded      Kind = "parameter-added"
+	ParameterRemoved    Kind = "parameter-removed"
+	ParameterRequired   Kind = "parameter-required"
+	ParameterOptional   Kind = "parameter-optional"
+	PropertyAdded       Kind = "property-added"
+	PropertyRemoved     Kind = "property-removed"
+	PropertyRequired    Kind = "property-required"
+	PropertyOptional    Kind = "property-optional"
+	TypeChanged         Kind = "type-changed"
+	EnumNarrowed        Kind = "enum-narrowed"
+	EnumWidened         Kind = "enum-widened"
+	ConstraintTightened Kind = "constraint-tightened"
+	ConstraintLoosened  Kind = "constraint-loosened"
+	ResponseAdded       Kind = "response-added"
+	ResponseRemoved     Kind = "response-removed"
+	MediaTypeAdded      Kind = "media-type-added"
+	MediaTypeRemoved    Kind = "media-type-removed"
+	SecurityAdded       Kind = "security-added"
+	SecurityRemoved     Kind = "security-removed"
+	SecurityChanged     Kind = "security-changed"
+	LocationChanged     Kind = "location-changed"
+)
+
+// Change is a difference between two versions of a spec
+type Change struct {
+	Kind Kind `json:"kind"`
+	// Operation is the method and path of the operation, empty for the changes of the whole API
+	Operation string `json:"operation,omitempty"`
+	// Location is where the change is in the operation, like query.limit or responses.200.body.name
+	Location string `json:"location,omitempty"`
+	Message  string `json:"message"`
+	Breaking bool   `json:"breaking"`
+}
+
+func (c Change) String() string {
+	var b strings.Builder
+	if c.Operation != "" {
+		b.WriteString(c.Operation)
+		b.WriteString(": ")
+	}
+	b.WriteString(c.Message)
+	return b.String()
+}
+
+// Report lists the changes between two versions of a spec
+type Report struct {
+	Changes []Change `json:"changes"`
+}
+
+// Breaking returns the breaking changes
+func (r *Report) Breaking() []Change {
+	var result []Change
+	for _, c := range r.Changes {
+		if c.Breaking {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// HasBreaking tells whether any change is breaking
+func (r *Report) HasBreaking() bool {
+	return len(r.Breaking()) > 0
+}
+
+var pathParamRx = regexp.MustCompile(`\{[^{}/]*\}`)
+
+// the directions of the data, which tell the changes that break the clients
+type direction int
+
+const (
+	request direction = iota
+	response
+)
+
+type differ struct {
+	old, new *spec.Document
+	changes  []Change
+	// seen are the pairs of schema references compared, for the recursive schemas
+	seen map[string]bool
+}
+
+// Compare compares two versions of a spec.
+//
+// The operations are matched by method and path, the names of the path parameters
+// don't matter. The parameters are matched by location and name, the path
+// parameters by position and the body by location.
+func Compare(old, new *spec.Document) *Report {
+	d := &differ{old: old, new: new, seen: make(map[string]bool)}
+	d.compareAPI()
+	d.compareOperations()
+
+	sort.Slice(d.changes, func(i, j int) bool {
+		a, b := d.changes[i], d.changes[j]
+		if a.Operation != b.Operation {
+			return a.Operation < b.Operation
+		}
+		if a.Location != b.Location {
+			return a.Location < b.Location
+		}
+		return a.Message < b.Message
+	})
+	return &Report{Changes: d.changes}
+}
+
+func (d *differ) add(op, location string, kind Kind, breaking bool, format string, args ...interface{}) {
+	d.changes = append(d.changes, Change{
+		Kind:      kind,
+		Operation: op,
+		Location:  location,
+		Message:   fmt.Sprintf(format, args...),
+		Breaking:  breaking,
+	})
+}
+
+// compareAPI compares what every operation depends on: the host, the base path and the schemes
+func (d *differ) compareAPI() {
+	o, n := d.old.Spec(), d.new.Spec()
+	if o.Host != n.Host {
+		d.add("", "host", LocationChanged, true, "the host changed from %q to %q", o.Host, n.Host)
+	}
+	if d.old.BasePath() != d.new.BasePath() {
+		d.add("", "basePath", LocationChanged, true, "the base path changed from %q to %q", d.old.BasePath(), d.new.BasePath())
+	}
+	removed, added := difference(o.Schemes, n.Schemes)
+	for _, s := range removed {
+		d.add("", "schemes", LocationChanged, true, "the scheme %s was removed", s)
+	}
+	for _, s := range added {
+		d.add("", "schemes", LocationChanged, false, "the scheme %s was added", s)
+	}
+}
+
+// operationKey identifies an operation by its method and path, without the names of the path parameters
+func operationKey(method, path string) string {
+	return method + " " + pathParamRx.ReplaceAllString(path, "{}")
+}
+
+type operation struct {
+	method, path string
+	item         spec.PathItem
+	op           *spec.Operation
+}
+
+func operations(doc *spec.Document) map[string]operation {
+	result := make(map[string]operation)
+	for method, ops := range doc.Operations() {
+		for path, op := range ops {
+			result[operationKey(method, path)] = operation{method, path, doc.AllPaths()[path], op}
+		}
+	}
+	return result
+}
+
+func (d *differ) compareOperations() {
+	oldOps, newOps := operations(d.old), operations(d.new)
+	for key, o := range oldOps {
+		name := o.method + " " + o.path
+		n, ok := newOps[key]
+		if !ok {
+			d.add(name, "", OperationRemoved, true, "the operation was removed")
+			continue
+		}
+		d.compareOperation(n.method+" "+n.path, o, n)
+	}
+	for key, n := range newOps {
+		if _, ok := oldOps[key]; !ok {
+			d.add(n.method+" "+n.path, "", OperationAdded, false, "the operation was added")
+		}
+	}
+}
+
+func (d *differ) compareOperation(name string, o, n operation) {
+	if !o.op.Deprecated && n.op.Deprecated {
+		d.add(name, "", OperationDeprecated, false, "the operation was deprecated")
+	}
+	d.compareParams(name, d.params(d.old, o), d.params(d.new, n))
+	d.compareMediaTypes(name, "consumes", effective(o.op.Consumes, d.old.Spec().Consumes), effective(n.op.Consumes, d.new.Spec().Consumes))
+	d.compareMediaTypes(name, "produces", effective(o.op.Produces, d.old.Spec().Produces), effective(n.op.Produces, d.new.Spec().Produces))
+	d.compareResponses(name, o.op.Responses, n.op.Responses)
+	d.compareSecurity(name, o.op, n.op)
+}
+
+// params returns the parameters of an operation by location and name, with the
+// parameters of the path item and the references to the parameters section resolved
+func (d *differ) params(doc *spec.Document, o operation) map[string]spec.Parameter {
+	result := make(map[string]spec.Parameter)
+	positions := make(map[string]int)
+	for i, p := range pathParamRx.FindAllString(o.path, -1) {
+		positions[strings.Trim(p, "{}")] = i
+	}
+	for _, params := range [][]spec.Parameter{o.item.Parameters, o.op.Parameters} {
+		for _, p := range params {
+			p = resolveParam(doc, p)
+			switch p.In {
+			case "body":
+				result["body"] = p
+			case "path":
+				result[fmt.Sprintf("path.%d", positions[p.Name])] = p
+			default:
+				result[p.In+"."+p.Name] = p
+			}
+		}
+	}
+	return result
+}
+
+// the longest chain of references followed
+const maxRefChain = 32
+
+func resolveParam(doc *spec.Document, param spec.Parameter) spec.Parameter {
+	for i := 0; param.Ref.String() != "" && i < maxRefChain; i++ {
+		frag := param.Ref.GetURL().Fragment
+		if !strings.HasPrefix(frag, "/parameters/") {
+			break
+		}
+		p, ok := doc.Spec().Parameters[jsonpointer.Unescape(strings.TrimPrefix(frag, "/parameters/"))]
+		if !ok {
+			break
+		}
+		param = p
+	}
+	return param
+}
+
+func paramName(p spec.Parameter) string {
+	if p.In == "body" {
+		return "the body"
+	}
+	return fmt.Sprintf("the %s parameter %s", p.In, p.Name)
+}
+
+func (d *differ) compareParams(name string, oldParams, newParams map[string]spec.Parameter) {
+	for key, o := range oldParams {
+		location := o.In + "." + o.Name
+		if o.In == "body" {
+			location = "body"
+		}
+		n, ok := newParams[key]
+		if !ok {
+			d.add(name, location, ParameterRemoved, true, "%s was removed", paramName(o))
+			continue
+		}
+		if o.In == "path" {
+			location = n.In + "." + n.Name
+		}
+		if !o.Required && n.Required {
+			d.add(name, location, ParameterRequired, true, "%s became required", paramName(n))
+		} else if o.Required && !n.Required {
+			d.add(name, location, ParameterOptional, false, "%s became optional", paramName(n))
+		}
+		if o.In == "body" {
+			d.compareSchemas(name, location, o.Schema, n.Schema, request)
+		} else {
+			d.compareSchemas(name, location, paramSchema(o), paramSchema(n), request)
+		}
+	}
+	for key, n := range newParams {
+		if _, ok := oldParams[key]; ok {
+			continue
+		}
+		location := n.In + "." + n.Name
+		if n.In == "body" {
+			location = "body"
+		}
+		if n.Required {
+			d.add(name, location, ParameterAdded, true, "the required %s was added", strings.TrimPrefix(paramName(n), "the "))
+		} else {
+			d.add(name, location, ParameterAdded, false, "the optional %s was added", strings.TrimPrefix(paramName(n), "the "))
+		}
+	}
+}
+
+// paramSchema returns the schema of the simple schema and validations of a parameter
+func paramSchema(p spec.Parameter) *spec.Schema {
+	schema := new(spec.Schema).Typed(p.Type, p.Format)
+	if p.Type == "" {
+		schema.Type = nil
+	}
+	schema.Enum = p.Enum
+	schema.Maximum, schema.ExclusiveMaximum = p.Maximum, p.ExclusiveMaximum
+	schema.Minimum, schema.ExclusiveMinimum = p.Minimum, p.ExclusiveMinimum
+	schema.MaxLength, schema.MinLength, schema.Pattern = p.MaxLength, p.MinLength, p.Pattern
+	schema.MaxItems, schema.MinItems, schema.UniqueItems = p.MaxItems, p.MinItems, p.UniqueItems
+	schema.MultipleOf = p.MultipleOf
+	if p.Items != nil {
+		schema.Items = &spec.SchemaOrArray{Schema: itemsSchema(p.Items)}
+	}
+	return schema
+}
+
+func itemsSchema(items *spec.Items) *spec.Schema {
+	schema := new(spec.Schema).Typed(items.Type, items.Format)
+	schema.Enum = items.Enum
+	schema.Maximum, schema.ExclusiveMaximum = items.Maximum, items.ExclusiveMaximum
+	schema.Minimum, schema.ExclusiveMinimum = items.Minimum, items.ExclusiveMinimum
+	schema.MaxLength, schema.MinLength, schema.Pattern = items.MaxLength, items.MinLength, items.Pattern
+	schema.MaxItems, schema.MinItems, schema.UniqueItems = items.MaxItems, items.MinItems, items.UniqueItems
+	schema.MultipleOf = items.MultipleOf
+	if items.Items != nil {
+		schema.Items = &spec.SchemaOrArray{Schema: itemsSchema(items.Items)}
+	}
+	return schema
+}
+
+func effective(operation, global []string) []string {
+	if len(operation) > 0 {
+		return operation
+	}
+	return global
+}
+
+// compareMediaTypes compares the media types an operation consumes or produces, removing one breaks the clients using it
+func (d *differ) compareMediaTypes(name, location string, old, new []string) {
+	removed, added := difference(old, new)
+	for _, mt := range removed {
+		d.add(name, location, MediaTypeRemoved, true, "the media type %s is no longer in %s", mt, location)
+	}
+	for _, mt := range added {
+		d.add(name, location, MediaTypeAdded, false, "the media type %s was added to %s", mt, location)
+	}
+}
+
+func responseMap(responses *spec.Responses) map[string]*spec.Response {
+	result := make(map[string]*spec.Response)
+	if responses == nil {
+		return result
+	}
+	if responses.Default != nil {
+		result["default"] = responses.Default
+	}
+	for code, resp := range responses.StatusCodeResponses {
+		resp := resp
+		result[fmt.Sprint(code)] = &resp
+	}
+	return result
+}
+
+func (d *differ) compareResponses(name string, old, new *spec.Responses) {
+	oldResps, newResps := responseMap(old), responseMap(new)
+	for code, o := range oldResps {
+		location := "responses." + code
+		n, ok := newResps[code]
+		if !ok {
+			// the clients rely on the success responses, the error responses fall back to default
+			d.add(name, location, ResponseRemoved, strings.HasPrefix(code, "2"), "the %s response was removed", code)
+			continue
+		}
+		o, n = d.resolveResponse(d.old, o), d.resolveResponse(d.new, n)
+		switch {
+		case o.Schema != nil && n.Schema == nil:
+			d.add(name, location, TypeChanged, true, "the %s response no longer has a body", code)
+		case o.Schema == nil && n.Schema != nil:
+			d.add(name, location, PropertyAdded, false, "the %s response has a body", code)
+		case o.Schema != nil:
+			d.compareSchemas(name, location+".body", o.Schema, n.Schema, response)
+		}
+	}
+	for code := range newResps {
+		if _, ok := oldResps[code]; !ok {
+			d.add(name, "responses."+code, ResponseAdded, false, "the %s response was added", code)
+		}
+	}
+}
+
+func (d *differ) resolveResponse(doc *spec.Document, resp *spec.Response) *spec.Response {
+	for i := 0; resp.Ref.String() != "" && i < maxRefChain; i++ {
+		frag := resp.Ref.GetURL().Fragment
+		if !strings.HasPrefix(frag, "/responses/") {
+			break
+		}
+		r, ok := doc.Spec().Responses[jsonpointer.Unescape(strings.TrimPrefix(frag, "/responses/"))]
+		if !ok {
+			break
+		}
+		resp = &r
+	}
+	return resp
+}
+
+// compareSecurity compares the security requirements of an operation and the definitions of
+// the schemes they require, a new requirement or a changed scheme breaks the clients
+func (d *differ) compareSecurity(name string, o, n *spec.Operation) {
+	oldReqs := requirements(d.old.SecurityRequirementsFor(o))
+	newReqs := requirements(d.new.SecurityRequirementsFor(n))
+	oldDefs := d.old.SecurityDefinitionsFor(o)
+	newDefs := d.new.SecurityDefinitionsFor(n)
+
+	for scheme, scopes := range oldReqs {
+		newScopes, ok := newReqs[scheme]
+		if !ok {
+			d.add(name, "security."+scheme, SecurityRemoved, false, "the security scheme %s is no longer required", scheme)
+			continue
+		}
+		removed, added := difference(scopes, newScopes)
+		for _, s := range added {
+			d.add(name, "security."+scheme, SecurityChanged, true, "the scope %s of %s is required", s, scheme)
+		}
+		for _, s := range removed {
+			d.add(name, "security."+scheme, SecurityChanged, false, "the scope %s of %s is no longer required", s, scheme)
+		}
+		if od, nd := oldDefs[scheme], newDefs[scheme]; od.Type != nd.Type || od.In != nd.In || od.Name != nd.Name ||
+			od.Flow != nd.Flow || od.AuthorizationURL != nd.AuthorizationURL || od.TokenURL != nd.TokenURL {
+			d.add(name, "security."+scheme, SecurityChanged, true, "the security scheme %s changed", scheme)
+		}
+	}
+	for scheme := range newReqs {
+		if _, ok := oldReqs[scheme]; !ok {
+			d.add(name, "security."+scheme, SecurityAdded, true, "the security scheme %s is required", scheme)
+		}
+	}
+}
+
+func requirements(reqs []spec.SecurityRequirement) map[string][]string {
+	result := make(map[string][]string, len(reqs))
+	for _, r := range reqs {
+		result[r.Name] = r.Scopes
+	}
+	return result
+}
+
+// difference returns the values only in old and the values only in new, sorted
+func difference(old, new []string) (removed, added []string) {
+	inOld := make(map[string]bool, len(old))
+	for _, v := range old {
+		inOld[v] = true
+	}
+	inNew := make(map[string]bool, len(new))
+	for _, v := range new {
+		inNew[v] = true
+		if !inOld[v] {
+			added = append(added, v)
+		}
+	}
+	for _, v := range old {
+		if !inNew[v] {
+			removed = append(removed, v)
+		}
+	}
+	sort.Strings(removed)
+	sort.Strings(added)
+	return removed, added
+}
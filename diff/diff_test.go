@@ -0,0 +1,194 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aiyi/swagger-gin/spec"
+	"github.com/aiyi/swagger-gin/swag"
+	"github.com/stretchr/testify/assert"
+)
+
+const oldPets = `
+swagger: "2.0"
+info: {title: pets, version: "1.0"}
+basePath: /api
+consumes: [application/json]
+produces: [application/json]
+securityDefinitions:
+  key: {type: apiKey, in: header, name: X-Key}
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      parameters:
+        - {name: limit, in: query, type: integer, maximum: 100}
+        - {name: status, in: query, type: string, enum: [available, pending, sold]}
+        - $ref: "#/parameters/tag"
+      responses:
+        200:
+          description: the pets
+          schema: {type: array, items: {$ref: "#/definitions/Pet"}}
+    post:
+      operationId: addPet
+      parameters:
+        - {name: body, in: body, required: true, schema: {$ref: "#/definitions/Pet"}}
+      responses:
+        201: {description: added}
+  /pets/{id}:
+    get:
+      operationId: getPet
+      parameters:
+        - {name: id, in: path, required: true, type: integer}
+      responses:
+        200: {description: the pet, schema: {$ref: "#/definitions/Pet"}}
+    delete:
+      operationId: deletePet
+      parameters:
+        - {name: id, in: path, required: true, type: integer}
+      responses:
+        204: {description: deleted}
+parameters:
+  tag: {name: tag, in: query, type: string}
+definitions:
+  Pet:
+    required: [name]
+    properties:
+      id: {type: integer, format: int64}
+      name: {type: string, maxLength: 50}
+      status: {type: string, enum: [available, sold]}
+      owner: {$ref: "#/definitions/Owner"}
+  Owner:
+    properties:
+      name: {type: string}
+      pets: {type: array, items: {$ref: "#/definitions/Pet"}}
+`
+
+const newPets = `
+swagger: "2.0"
+info: {title: pets, version: "2.0"}
+basePath: /api
+consumes: [application/json]
+produces: [application/json]
+securityDefinitions:
+  key: {type: apiKey, in: header, name: X-Key}
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      security: [{key: []}]
+      parameters:
+        - {name: limit, in: query, type: integer, maximum: 50}
+        - {name: status, in: query, type: string, enum: [available, sold]}
+        - $ref: "#/parameters/tag"
+        - {name: sort, in: query, type: string}
+      responses:
+        200:
+          description: the pets
+          schema: {type: array, items: {$ref: "#/definitions/Pet"}}
+    post:
+      operationId: addPet
+      parameters:
+        - {name: body, in: body, required: true, schema: {$ref: "#/definitions/Pet"}}
+      responses:
+        201: {description: added}
+  /pets/{petId}:
+    get:
+      operationId: getPet
+      parameters:
+        - {name: petId, in: path, required: true, type: string}
+      responses:
+        200: {description: the pet, schema: {$ref: "#/definitions/Pet"}}
+parameters:
+  tag: {name: tag, in: query, type: string, required: true}
+definitions:
+  Pet:
+    required: [name, status]
+    properties:
+      name: {type: string, maxLength: 50}
+      status: {type: string, enum: [available, pending, sold]}
+      owner: {$ref: "#/definitions/Owner"}
+      nickname: {type: string}
+  Owner:
+    properties:
+      name: {type: string}
+      pets: {type: array, items: {$ref: "#/definitions/Pet"}}
+`
+
+func load(t *testing.T, yaml string) *spec.Document {
+	data, err := swag.YAMLToJSON([]byte(yaml))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	doc, err := spec.New(data, "")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return doc
+}
+
+func find(changes []Change, operation, location string, kind Kind) *Change {
+	for i, c := range changes {
+		if c.Operation == operation && c.Location == location && c.Kind == kind {
+			return &changes[i]
+		}
+	}
+	return nil
+}
+
+func TestCompare(t *testing.T) {
+	report := Compare(load(t, oldPets), load(t, newPets))
+
+	for _, tc := range []struct {
+		operation, location string
+		kind                Kind
+		breaking            bool
+	}{
+		{"DELETE /pets/{id}", "", OperationRemoved, true},
+		{"GET /pets", "query.limit", ConstraintTightened, true},
+		{"GET /pets", "query.status", EnumNarrowed, true},
+		{"GET /pets", "query.tag", ParameterRequired, true},
+		{"GET /pets", "query.sort", ParameterAdded, false},
+		{"GET /pets", "security.key", SecurityAdded, true},
+		// the pets are returned: the responses can't lose properties or allow more values
+		{"GET /pets", "responses.200.body[].id", PropertyRemoved, true},
+		{"GET /pets", "responses.200.body[].status", EnumWidened, true},
+		{"GET /pets", "responses.200.body[].status", PropertyRequired, false},
+		{"GET /pets", "responses.200.body[].nickname", PropertyAdded, false},
+		// and accepted: the requests can't require more
+		{"POST /pets", "body.id", PropertyRemoved, false},
+		{"POST /pets", "body.status", PropertyRequired, true},
+		{"POST /pets", "body.status", EnumWidened, false},
+		// the path parameters are matched by position
+		{"GET /pets/{petId}", "path.petId", TypeChanged, true},
+	} {
+		c := find(report.Changes, tc.operation, tc.location, tc.kind)
+		if assert.NotNil(t, c, "%s %s %s", tc.operation, tc.location, tc.kind) {
+			assert.Equal(t, tc.breaking, c.Breaking, c.String())
+		}
+	}
+
+	// the operations are matched without the names of the path parameters
+	assert.Nil(t, find(report.Changes, "GET /pets/{petId}", "", OperationAdded))
+	// the recursive schemas are compared once
+	assert.Nil(t, find(report.Changes, "GET /pets", "responses.200.body[].owner.pets[].id", PropertyRemoved))
+	assert.True(t, report.HasBreaking())
+}
+
+func TestCompare_Same(t *testing.T) {
+	report := Compare(load(t, oldPets), load(t, oldPets))
+	assert.Empty(t, report.Changes)
+	assert.False(t, report.HasBreaking())
+	assert.Contains(t, report.Markdown(), "No changes.")
+}
+
+func TestReport_Markdown(t *testing.T) {
+	report := Compare(load(t, oldPets), load(t, newPets))
+	md := report.Markdown()
+	breaking := strings.Index(md, "## Breaking changes")
+	other := strings.Index(md, "## Other changes")
+	if assert.True(t, breaking >= 0 && other > breaking, md) {
+		assert.Contains(t, md[breaking:other], "### `DELETE /pets/{id}`\n\n- The operation was removed\n")
+		assert.Contains(t, md[other:], "- The optional query parameter sort was added\n")
+	}
+}
@@ -0,0 +1,53 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Markdown renders the changes as a changelog for the release notes, the breaking changes first
+func (r *Report) Markdown() string {
+	var b strings.Builder
+	b.WriteString("# API changes\n")
+	if len(r.Changes) == 0 {
+		b.WriteString("\nNo changes.\n")
+		return b.String()
+	}
+	var breaking, other []Change
+	for _, c := range r.Changes {
+		if c.Breaking {
+			breaking = append(breaking, c)
+		} else {
+			other = append(other, c)
+		}
+	}
+	writeSection(&b, "Breaking changes", breaking)
+	writeSection(&b, "Other changes", other)
+	return b.String()
+}
+
+// writeSection writes the changes grouped by operation, the changes of the whole API first
+func writeSection(b *strings.Builder, title string, changes []Change) {
+	if len(changes) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n## %s\n", title)
+	operation := "-"
+	for _, c := range changes {
+		if c.Operation != operation {
+			operation = c.Operation
+			if operation != "" {
+				fmt.Fprintf(b, "\n### `%s`\n", operation)
+			}
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(b, "- %s\n", capitalize(c.Message))
+	}
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
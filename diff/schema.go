@@ -0,0 +1,292 @@
+package diff
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/aiyi/swagger-gin/spec"
+)
+
+// compareSchemas compares the schemas of a request or a response value
+func (d *differ) compareSchemas(name, location string, old, new *spec.Schema, dir direction) {
+	if old == nil || new == nil {
+		return
+	}
+	oldRef, newRef := old.Ref.String(), new.Ref.String()
+	old, new = d.resolve(d.old, old), d.resolve(d.new, new)
+	if old == nil || new == nil {
+		return
+	}
+	if oldRef != "" || newRef != "" {
+		// the recursive schemas are compared once by direction
+		key := fmt.Sprintf("%s|%s|%d", oldRef, newRef, dir)
+		if d.seen[key] {
+			return
+		}
+		d.seen[key] = true
+		defer delete(d.seen, key)
+	}
+
+	old, new = d.flatten(d.old, old), d.flatten(d.new, new)
+	if typeOf(old) != typeOf(new) {
+		d.add(name, location, TypeChanged, true, "the type of %s changed from %s to %s", location, typeOf(old), typeOf(new))
+		return
+	}
+
+	d.compareEnums(name, location, old.Enum, new.Enum, dir)
+	d.compareConstraints(name, location, old, new, dir)
+	d.compareProperties(name, location, old, new, dir)
+
+	if old.Items != nil && new.Items != nil && old.Items.Schema != nil && new.Items.Schema != nil {
+		d.compareSchemas(name, location+"[]", old.Items.Schema, new.Items.Schema, dir)
+	}
+	if old.AdditionalProperties != nil && new.AdditionalProperties != nil &&
+		old.AdditionalProperties.Schema != nil && new.AdditionalProperties.Schema != nil {
+		d.compareSchemas(name, location+".*", old.AdditionalProperties.Schema, new.AdditionalProperties.Schema, dir)
+	}
+}
+
+// resolve follows the references of a schema, nil is returned for the unresolvable ones
+// which the validation of the spec reports
+func (d *differ) resolve(doc *spec.Document, schema *spec.Schema) *spec.Schema {
+	for i := 0; schema.Ref.String() != "" && i < maxRefChain; i++ {
+		ref := schema.Ref
+		resolved, err := spec.ResolveRef(doc.Spec(), &ref)
+		if err != nil {
+			return nil
+		}
+		schema = resolved
+	}
+	return schema
+}
+
+// flatten merges the properties and the required properties of the allOf members into the schema,
+// which the clients see as one object
+func (d *differ) flatten(doc *spec.Document, schema *spec.Schema) *spec.Schema {
+	if len(schema.AllOf) == 0 {
+		return schema
+	}
+	result := *schema
+	result.AllOf = nil
+	result.Properties = make(map[string]spec.Schema, len(schema.Properties))
+	for k, v := range schema.Properties {
+		result.Properties[k] = v
+	}
+	result.Required = append([]string(nil), schema.Required...)
+	for _, member := range schema.AllOf {
+		member := member
+		resolved := d.resolve(doc, &member)
+		if resolved == nil {
+			continue
+		}
+		resolved = d.flatten(doc, resolved)
+		for k, v := range resolved.Properties {
+			if _, exists := result.Properties[k]; !exists {
+				result.Properties[k] = v
+			}
+		}
+		result.Required = append(result.Required, resolved.Required...)
+		if len(result.Type) == 0 {
+			result.Type = resolved.Type
+		}
+	}
+	if len(result.Type) == 0 && len(result.Properties) > 0 {
+		result.Type = spec.StringOrArray{"object"}
+	}
+	return &result
+}
+
+// typeOf describes the type and format of a schema
+func typeOf(schema *spec.Schema) string {
+	types := append([]string(nil), schema.Type...)
+	sort.Strings(types)
+	result := strings.Join(types, "|")
+	if result == "" {
+		result = "any"
+	}
+	if schema.Format != "" {
+		result += " (" + schema.Format + ")"
+	}
+	return result
+}
+
+// compareEnums compares the allowed values, a request accepting fewer values or a response
+// returning more values breaks the clients
+func (d *differ) compareEnums(name, location string, old, new []interface{}, dir direction) {
+	var removed, added []string
+	switch {
+	case len(old) == 0 && len(new) == 0:
+		return
+	case len(old) == 0:
+		d.add(name, location, EnumNarrowed, dir == request, "the enum of %s is %s", location, values(new))
+		return
+	case len(new) == 0:
+		d.add(name, location, EnumWidened, dir == response, "the enum %s of %s was removed", values(old), location)
+		return
+	}
+	for _, v := range old {
+		if !containsValue(new, v) {
+			removed = append(removed, fmt.Sprint(v))
+		}
+	}
+	for _, v := range new {
+		if !containsValue(old, v) {
+			added = append(added, fmt.Sprint(v))
+		}
+	}
+	if len(removed) > 0 {
+		d.add(name, location, EnumNarrowed, dir == request, "the enum of %s no longer has %s", location, strings.Join(removed, ", "))
+	}
+	if len(added) > 0 {
+		d.add(name, location, EnumWidened, dir == response, "the enum of %s has %s", location, strings.Join(added, ", "))
+	}
+}
+
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if reflect.DeepEqual(v, value) || fmt.Sprint(v) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func values(vs []interface{}) string {
+	s := make([]string, len(vs))
+	for i, v := range vs {
+		s[i] = fmt.Sprint(v)
+	}
+	return strings.Join(s, ", ")
+}
+
+// a bound of a value, tighter when lower is a greater bound
+type bound struct {
+	name  string
+	lower bool
+	old   *float64
+	new   *float64
+}
+
+func intBound(v *int64) *float64 {
+	if v == nil {
+		return nil
+	}
+	f := float64(*v)
+	return &f
+}
+
+// compareConstraints compares the validations of the values, a request accepting fewer values
+// or a response returning more values breaks the clients
+func (d *differ) compareConstraints(name, location string, old, new *spec.Schema, dir direction) {
+	tightened := func(format string, args ...interface{}) {
+		d.add(name, location, ConstraintTightened, dir == request, format, args...)
+	}
+	loosened := func(format string, args ...interface{}) {
+		d.add(name, location, ConstraintLoosened, dir == response, format, args...)
+	}
+
+	for _, b := range []bound{
+		{"maximum", false, old.Maximum, new.Maximum},
+		{"minimum", true, old.Minimum, new.Minimum},
+		{"maxLength", false, intBound(old.MaxLength), intBound(new.MaxLength)},
+		{"minLength", true, intBound(old.MinLength), intBound(new.MinLength)},
+		{"maxItems", false, intBound(old.MaxItems), intBound(new.MaxItems)},
+		{"minItems", true, intBound(old.MinItems), intBound(new.MinItems)},
+		{"maxProperties", false, intBound(old.MaxProperties), intBound(new.MaxProperties)},
+		{"minProperties", true, intBound(old.MinProperties), intBound(new.MinProperties)},
+	} {
+		switch {
+		case b.old == nil && b.new == nil:
+		case b.old == nil:
+			tightened("the %s of %s is %v", b.name, location, *b.new)
+		case b.new == nil:
+			loosened("the %s %v of %s was removed", b.name, *b.old, location)
+		case *b.old == *b.new:
+		case (*b.new > *b.old) == b.lower:
+			tightened("the %s of %s changed from %v to %v", b.name, location, *b.old, *b.new)
+		default:
+			loosened("the %s of %s changed from %v to %v", b.name, location, *b.old, *b.new)
+		}
+	}
+
+	if !old.ExclusiveMaximum && new.ExclusiveMaximum && new.Maximum != nil {
+		tightened("the maximum of %s became exclusive", location)
+	} else if old.ExclusiveMaximum && !new.ExclusiveMaximum && old.Maximum != nil {
+		loosened("the maximum of %s is no longer exclusive", location)
+	}
+	if !old.ExclusiveMinimum && new.ExclusiveMinimum && new.Minimum != nil {
+		tightened("the minimum of %s became exclusive", location)
+	} else if old.ExclusiveMinimum && !new.ExclusiveMinimum && old.Minimum != nil {
+		loosened("the minimum of %s is no longer exclusive", location)
+	}
+
+	switch {
+	case old.Pattern == new.Pattern:
+	case old.Pattern == "":
+		tightened("the pattern of %s is %s", location, new.Pattern)
+	case new.Pattern == "":
+		loosened("the pattern %s of %s was removed", old.Pattern, location)
+	default:
+		tightened("the pattern of %s changed from %s to %s", location, old.Pattern, new.Pattern)
+	}
+
+	if !old.UniqueItems && new.UniqueItems {
+		tightened("the items of %s must be unique", location)
+	} else if old.UniqueItems && !new.UniqueItems {
+		loosened("the items of %s no longer have to be unique", location)
+	}
+
+	switch {
+	case old.MultipleOf == nil && new.MultipleOf == nil:
+	case new.MultipleOf == nil:
+		loosened("the multipleOf %v of %s was removed", *old.MultipleOf, location)
+	case old.MultipleOf == nil || *old.MultipleOf != *new.MultipleOf:
+		tightened("the multipleOf of %s is %v", location, *new.MultipleOf)
+	}
+}
+
+// compareProperties compares the properties of objects, a request requiring more properties
+// or a response returning fewer properties breaks the clients
+func (d *differ) compareProperties(name, location string, old, new *spec.Schema, dir direction) {
+	oldRequired, newRequired := requiredSet(old), requiredSet(new)
+	for prop, o := range old.Properties {
+		o := o
+		propLocation := location + "." + prop
+		n, ok := new.Properties[prop]
+		if !ok {
+			d.add(name, propLocation, PropertyRemoved, dir == response, "the property %s was removed", propLocation)
+			continue
+		}
+		if !oldRequired[prop] && newRequired[prop] {
+			d.add(name, propLocation, PropertyRequired, dir == request, "the property %s became required", propLocation)
+		} else if oldRequired[prop] && !newRequired[prop] {
+			d.add(name, propLocation, PropertyOptional, dir == response, "the property %s became optional", propLocation)
+		}
+		d.compareSchemas(name, propLocation, &o, &n, dir)
+	}
+	var added []string
+	for prop := range new.Properties {
+		if _, ok := old.Properties[prop]; !ok {
+			added = append(added, prop)
+		}
+	}
+	sort.Strings(added)
+	for _, prop := range added {
+		propLocation := location + "." + prop
+		if newRequired[prop] {
+			d.add(name, propLocation, PropertyAdded, dir == request, "the required property %s was added", propLocation)
+		} else {
+			d.add(name, propLocation, PropertyAdded, false, "the property %s was added", propLocation)
+		}
+	}
+}
+
+func requiredSet(schema *spec.Schema) map[string]bool {
+	result := make(map[string]bool, len(schema.Required))
+	for _, name := range schema.Required {
+		result[name] = true
+	}
+	return result
+}
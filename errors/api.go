@@ -0,0 +1,144 @@
+package errors
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorBody is the JSON representation of a single error sent back to a client
+type ErrorBody struct {
+	Code    int32         `json:"code"`
+	Message string        `json:"message"`
+	Name    string        `json:"name,omitempty"`
+	In      string        `json:"in,omitempty"`
+	Value   interface{}   `json:"value,omitempty"`
+	Values  []interface{} `json:"values,omitempty"`
+}
+
+// ErrorEnvelope is the default payload written by ServeError
+type ErrorEnvelope struct {
+	Code    int32       `json:"code"`
+	Message string      `json:"message"`
+	Errors  []ErrorBody `json:"errors,omitempty"`
+}
+
+// EnvelopeFunc builds the payload for the given status code and flattened list of errors
+type EnvelopeFunc func(code int32, message string, errs []ErrorBody) interface{}
+
+// envelopeKey is the key the envelope of a router is kept under in the gin context
+const envelopeKey = "swagger-gin/errors.envelope"
+
+// WithEnvelope returns a middleware making ServeError build the payloads of the routes
+// it's used on with fn, so a service can render errors in its own format
+func WithEnvelope(fn EnvelopeFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(envelopeKey, fn)
+		c.Next()
+	}
+}
+
+// envelope returns the envelope set on the routes of the request, DefaultEnvelope otherwise
+func envelope(c *gin.Context) EnvelopeFunc {
+	if fn, ok := c.Get(envelopeKey); ok {
+		if envelope, ok := fn.(EnvelopeFunc); ok && envelope != nil {
+			return envelope
+		}
+	}
+	return DefaultEnvelope
+}
+
+// DefaultEnvelope wraps the errors in an ErrorEnvelope
+func DefaultEnvelope(code int32, message string, errs []ErrorBody) interface{} {
+	return &ErrorEnvelope{
+		Code:    code,
+		Message: message,
+		Errors:  errs,
+	}
+}
+
+// ServeError writes the error to the response using the envelope set with WithEnvelope
+// and aborts the remaining handlers in the chain. The text of the errors that aren't
+// validation failures stays on the server, the client is only told the request failed.
+func ServeError(c *gin.Context, err error) {
+	code := StatusCode(err)
+	var message string
+	switch e := err.(type) {
+	case *Validation:
+		message = e.Error()
+	case *CompositeError:
+		message = e.Message
+	default:
+		c.Error(err)
+		message = http.StatusText(int(code))
+	}
+	c.AbortWithStatusJSON(int(code), envelope(c)(code, message, flatten(err, false)))
+}
+
+// StatusCode returns the http status code that should be used to report the error
+func StatusCode(err error) int32 {
+	switch e := err.(type) {
+	case *Validation:
+		if e.Code > 0 {
+			return e.Code
+		}
+		return http.StatusUnprocessableEntity
+	case *CompositeError:
+		if e.Code > 0 {
+			return e.Code
+		}
+		// the nested errors telling a status explain the failure, a plain error only
+		// tells that something went wrong on the server
+		code := int32(http.StatusUnprocessableEntity)
+		for _, er := range e.Errors {
+			switch er.(type) {
+			case *Validation, *CompositeError:
+				return StatusCode(er)
+			}
+			code = http.StatusInternalServerError
+		}
+		return code
+	}
+	return http.StatusInternalServerError
+}
+
+// Flatten unwraps composite errors into a flat list of error bodies
+func Flatten(err error) []ErrorBody {
+	return flatten(err, true)
+}
+
+// flatten unwraps composite errors, the text of the plain errors is only kept
+// when they're exposed
+func flatten(err error, exposed bool) []ErrorBody {
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case *Validation:
+		value := e.Value
+		if ve, ok := value.(error); ok {
+			value = ve.Error()
+		}
+		return []ErrorBody{{
+			Code:    StatusCode(e),
+			Message: e.Message,
+			Name:    e.Name,
+			In:      e.In,
+			Value:   value,
+			Values:  e.Values,
+		}}
+	case *CompositeError:
+		var result []ErrorBody
+		for _, er := range e.Errors {
+			result = append(result, flatten(er, exposed)...)
+		}
+		return result
+	}
+	message := http.StatusText(http.StatusInternalServerError)
+	if exposed {
+		message = err.Error()
+	}
+	return []ErrorBody{{
+		Code:    http.StatusInternalServerError,
+		Message: message,
+	}}
+}
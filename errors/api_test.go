@@ -0,0 +1,112 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func serve(err error) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	ServeError(c, err)
+	return rec
+}
+
+func TestServeError_Validation(t *testing.T) {
+	rec := serve(InvalidContentType("text/plain", []string{"application/json"}))
+	assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+
+	var body ErrorEnvelope
+	if assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body)) {
+		assert.EqualValues(t, http.StatusUnsupportedMediaType, body.Code)
+		if assert.Len(t, body.Errors, 1) {
+			assert.Equal(t, "Content-Type", body.Errors[0].Name)
+			assert.Equal(t, "header", body.Errors[0].In)
+			assert.Equal(t, "text/plain", body.Errors[0].Value)
+			assert.Equal(t, []interface{}{"application/json"}, body.Errors[0].Values)
+		}
+	}
+}
+
+func TestServeError_Composite(t *testing.T) {
+	err := CompositeValidationError(
+		Required("name", "body"),
+		CompositeValidationError(TooLong("tag", "query", 5), EnumFail("status", "query", "x", []interface{}{"a", "b"})),
+	)
+	rec := serve(err)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	var body ErrorEnvelope
+	if assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body)) {
+		assert.Equal(t, "validation failure list", body.Message)
+		if assert.Len(t, body.Errors, 3) {
+			assert.Equal(t, "name", body.Errors[0].Name)
+			assert.Equal(t, "tag", body.Errors[1].Name)
+			assert.Equal(t, "status", body.Errors[2].Name)
+		}
+	}
+}
+
+func TestServeError_Plain(t *testing.T) {
+	rec := serve(assert.AnError)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.NotContains(t, rec.Body.String(), assert.AnError.Error())
+
+	var body ErrorEnvelope
+	if assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body)) {
+		assert.Equal(t, "Internal Server Error", body.Message)
+		if assert.Len(t, body.Errors, 1) {
+			assert.Equal(t, "Internal Server Error", body.Errors[0].Message)
+		}
+	}
+}
+
+func TestStatusCode_Composite(t *testing.T) {
+	mixed := &CompositeError{Errors: []error{assert.AnError, Required("name", "body")}}
+	assert.EqualValues(t, http.StatusUnprocessableEntity, StatusCode(mixed))
+
+	plain := &CompositeError{Errors: []error{assert.AnError}}
+	assert.EqualValues(t, http.StatusInternalServerError, StatusCode(plain))
+
+	assert.EqualValues(t, http.StatusUnprocessableEntity, StatusCode(&CompositeError{}))
+
+	// the code of the first error telling one is used unless the composite sets its own
+	media := CompositeValidationError(InvalidContentType("text/plain", []string{"application/json"}), Required("name", "body"))
+	assert.EqualValues(t, http.StatusUnsupportedMediaType, StatusCode(media))
+	media.Code = http.StatusBadRequest
+	assert.EqualValues(t, http.StatusBadRequest, StatusCode(media))
+	assert.EqualValues(t, http.StatusUnprocessableEntity, StatusCode(CompositeValidationError(Required("name", "body"))))
+}
+
+func TestServeError_CustomEnvelope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	custom := func(code int32, message string, errs []ErrorBody) interface{} {
+		return map[string]interface{}{"error": message, "count": len(errs)}
+	}
+	router := gin.New()
+	router.GET("/custom", WithEnvelope(custom), func(c *gin.Context) {
+		ServeError(c, ParseError("petId", "path", "abc", assert.AnError))
+	})
+	router.GET("/default", func(c *gin.Context) {
+		ServeError(c, Required("petId", "path"))
+	})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("GET", "/custom", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.JSONEq(t, `{"error":"parsing petId path from \"abc\" failed, because assert.AnError general error for testing","count":1}`, rec.Body.String())
+
+	// the envelope is set for the routes using it, the others keep the default one
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("GET", "/default", nil))
+	var body ErrorEnvelope
+	if assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body)) {
+		assert.Equal(t, "petId in path is required", body.Message)
+	}
+}
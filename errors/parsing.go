@@ -0,0 +1,28 @@
+package errors
+
+import (
+	"fmt"
+	"net/http"
+)
+
+const (
+	parseErrorTemplContent     = `parsing %s %s from %q failed, because %s`
+	parseErrorTemplContentNoIn = `parsing %s from %q failed, because %s`
+)
+
+// ParseError creates a new parse error for a value that could not be read from the request
+func ParseError(name, in, value string, reason error) *Validation {
+	var msg string
+	if in == "" {
+		msg = fmt.Sprintf(parseErrorTemplContentNoIn, name, value, reason)
+	} else {
+		msg = fmt.Sprintf(parseErrorTemplContent, name, in, value, reason)
+	}
+	return &Validation{
+		Code:    http.StatusBadRequest,
+		Name:    name,
+		In:      in,
+		Value:   value,
+		Message: msg,
+	}
+}
@@ -0,0 +1,33 @@
+package errors
+
+import (
+	"fmt"
+	"net/http"
+)
+
+const (
+	undocumentedStatus    = `operation %q does not document the response status %d`
+	responseValidationMsg = `response validation failure list`
+)
+
+// UndocumentedStatus error for when a handler replies with a status code
+// that is not declared in the responses of the operation
+func UndocumentedStatus(operation string, status int) *Validation {
+	return &Validation{
+		Code:    http.StatusInternalServerError,
+		Name:    "status",
+		In:      "response",
+		Value:   status,
+		Message: fmt.Sprintf(undocumentedStatus, operation, status),
+	}
+}
+
+// CompositeResponseError wraps the failures found while validating a response,
+// those are server faults so they are reported with a 500 status
+func CompositeResponseError(errors ...error) *CompositeError {
+	return &CompositeError{
+		Code:    http.StatusInternalServerError,
+		Errors:  append([]error{}, errors...),
+		Message: responseValidationMsg,
+	}
+}
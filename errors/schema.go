@@ -47,8 +47,16 @@ const (
 	tooManyPropertiesNoIn     = "%s should have at most %d properties"
 	unallowedProperty         = "%s.%s in %s is a forbidden property"
 	unallowedPropertyNoIn     = "%s.%s is a forbidden property"
+	unallowedOwnProperty      = "%s in %s is a forbidden property"
+	unallowedOwnPropertyNoIn  = "%s is a forbidden property"
 	failedAllPatternProps     = "%s.%s in %s failed all pattern properties"
 	failedAllPatternPropsNoIn = "%s.%s failed all pattern properties"
+	anyOfFail                 = "%s in %s must validate at least one schema (anyOf)"
+	anyOfFailNoIn             = "%s must validate at least one schema (anyOf)"
+	oneOfFail                 = "%s in %s must validate one and only one schema (oneOf)"
+	oneOfFailNoIn             = "%s must validate one and only one schema (oneOf)"
+	notFail                   = "%s in %s must not validate the schema (not)"
+	notFailNoIn               = "%s must not validate the schema (not)"
 )
 
 // CompositeError is an error that groups several errors together
@@ -69,15 +77,94 @@ func (c *CompositeError) Error() string {
 	return c.Message
 }
 
-// CompositeValidationError an error to wrap a bunch of other errors
+// CompositeValidationError an error to wrap a bunch of other errors, the status code is
+// left to the errors it wraps unless the Code of the result is set
 func CompositeValidationError(errors ...error) *CompositeError {
 	return &CompositeError{
-		Code:    422,
 		Errors:  append([]error{}, errors...),
 		Message: "validation failure list",
 	}
 }
 
+// ValidateName prefixes the name of the value that failed with the path of the value
+// that holds it, a failure of "name" at "category" becomes a failure of "category.name"
+func (e *Validation) ValidateName(path string) *Validation {
+	if path == "" {
+		return e
+	}
+	if e.Name == "" {
+		// a failure of the whole value, its message starts with where the value is
+		if e.In != "" && strings.HasPrefix(e.Message, e.In+" ") {
+			e.Message = path + " in " + e.Message
+		}
+		e.Name = path
+		return e
+	}
+	name := path + "." + e.Name
+	if strings.HasPrefix(e.Message, e.Name) {
+		e.Message = name + e.Message[len(e.Name):]
+	}
+	e.Name = name
+	return e
+}
+
+// Nested names the failures of a nested value after the path of the value, the
+// failures grouped in a composite error are all renamed
+func Nested(path string, err error) error {
+	switch e := err.(type) {
+	case *Validation:
+		return e.ValidateName(path)
+	case *CompositeError:
+		for i, er := range e.Errors {
+			e.Errors[i] = Nested(path, er)
+		}
+		return e
+	}
+	return err
+}
+
+// AnyOfFailed an error for when a value doesn't match any of the anyOf schemas
+func AnyOfFailed(name, in string) *Validation {
+	msg := fmt.Sprintf(anyOfFail, name, in)
+	if in == "" {
+		msg = fmt.Sprintf(anyOfFailNoIn, name)
+	}
+	return &Validation{
+		Code:    422,
+		Name:    name,
+		In:      in,
+		Message: msg,
+	}
+}
+
+// OneOfFailed an error for when a value matches none or more than one of the oneOf schemas
+func OneOfFailed(name, in string) *Validation {
+	msg := fmt.Sprintf(oneOfFail, name, in)
+	if in == "" {
+		msg = fmt.Sprintf(oneOfFailNoIn, name)
+	}
+	return &Validation{
+		Code:    422,
+		Name:    name,
+		In:      in,
+		Message: msg,
+	}
+}
+
+// NotFailed an error for when a value matches the schema of a not clause
+func NotFailed(name, in string) *Validation {
+	msg := fmt.Sprintf(notFail, name, in)
+	if in == "" {
+		msg = fmt.Sprintf(notFailNoIn, name)
+	}
+	return &Validation{
+		Code:    422,
+		Name:    name,
+		In:      in,
+		Message: msg,
+	}
+}
+
 // FailedAllPatternProperties an error for when the property doesn't match a pattern
 func FailedAllPatternProperties(name, in, key string) *Validation {
 	msg := fmt.Sprintf(failedAllPatternProps, name, key, in)
@@ -93,12 +180,20 @@ func FailedAllPatternProperties(name, in, key string) *Validation {
 	}
 }
 
-// PropertyNotAllowed an error for when the property doesn't match a pattern
+// PropertyNotAllowed an error for when the property doesn't match a pattern,
+// without a name the property is one of the value itself and is named after its key
 func PropertyNotAllowed(name, in, key string) *Validation {
 	msg := fmt.Sprintf(unallowedProperty, name, key, in)
 	if in == "" {
 		msg = fmt.Sprintf(unallowedPropertyNoIn, name, key)
 	}
+	if name == "" {
+		name = key
+		msg = fmt.Sprintf(unallowedOwnProperty, key, in)
+		if in == "" {
+			msg = fmt.Sprintf(unallowedOwnPropertyNoIn, key)
+		}
+	}
 	return &Validation{
 		Code:    422,
 		Name:    name,
@@ -108,11 +203,14 @@ func PropertyNotAllowed(name, in, key string) *Validation {
 	}
 }
 
-// TooFewProperties an error for an object with too few properties
+// TooFewProperties an error for an object with too few properties,
+// without a name the object is the whole value and is named after where it is
 func TooFewProperties(name, in string, n int64) *Validation {
 	msg := fmt.Sprintf(tooFewProperties, name, in, n)
 	if in == "" {
 		msg = fmt.Sprintf(tooFewPropertiesNoIn, name, n)
+	} else if name == "" {
+		msg = fmt.Sprintf(tooFewPropertiesNoIn, in, n)
 	}
 	return &Validation{
 		Code:    422,
@@ -123,11 +221,14 @@ func TooFewProperties(name, in string, n int64) *Validation {
 	}
 }
 
-// TooManyProperties an error for an object with too many properties
+// TooManyProperties an error for an object with too many properties,
+// without a name the object is the whole value and is named after where it is
 func TooManyProperties(name, in string, n int64) *Validation {
 	msg := fmt.Sprintf(tooManyProperties, name, in, n)
 	if in == "" {
 		msg = fmt.Sprintf(tooManyPropertiesNoIn, name, n)
+	} else if name == "" {
+		msg = fmt.Sprintf(tooManyPropertiesNoIn, in, n)
 	}
 	return &Validation{
 		Code:    422,
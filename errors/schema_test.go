@@ -0,0 +1,35 @@
+package errors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNested(t *testing.T) {
+	err := Nested("category", TooShort("name", "body", 6))
+	if ve, ok := err.(*Validation); assert.True(t, ok) {
+		assert.Equal(t, "category.name", ve.Name)
+		assert.Equal(t, "category.name in body should be at least 6 chars long", ve.Message)
+	}
+
+	err = Nested("owner", Nested("address", Required("city", "")))
+	assert.Equal(t, "owner.address.city is required", err.Error())
+
+	err = Nested("tags.3", CompositeValidationError(Required("name", "body"), InvalidTypeName("x")))
+	if ce, ok := err.(*CompositeError); assert.True(t, ok) && assert.Len(t, ce.Errors, 2) {
+		assert.Equal(t, "tags.3.name", ce.Errors[0].(*Validation).Name)
+		assert.Equal(t, "tags.3", ce.Errors[1].(*Validation).Name)
+		assert.Equal(t, "x is an invalid type name", ce.Errors[1].Error())
+	}
+
+	// the failures of a whole nested object
+	err = TooFewProperties("", "body", 2)
+	assert.Equal(t, "body should have at least 2 properties", err.Error())
+	err = Nested("labels", err)
+	assert.Equal(t, "labels in body should have at least 2 properties", err.Error())
+	err = Nested("owner", PropertyNotAllowed("", "body", "nick"))
+	assert.Equal(t, "owner.nick in body is a forbidden property", err.Error())
+
+	assert.Nil(t, Nested("category", nil))
+}
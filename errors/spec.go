@@ -0,0 +1,62 @@
+package errors
+
+import "fmt"
+
+const (
+	duplicateOperationID = "%s duplicates the operationId %q of %s"
+	undeclaredPathParam  = "%s doesn't declare the path parameter %q"
+	unusedPathParam      = "%s declares the path parameter %q which isn't in the path"
+	duplicateParam       = "%s duplicates the parameter %q in %s"
+	unresolvedRef        = "%s references %q which can't be resolved: %v"
+)
+
+// DuplicateOperationID error for an operationId already used by another operation
+func DuplicateOperationID(pointer, id, first string) *Validation {
+	return &Validation{
+		Code:    422,
+		Name:    pointer,
+		Value:   id,
+		Message: fmt.Sprintf(duplicateOperationID, pointer, id, first),
+	}
+}
+
+// UndeclaredPathParam error for a path template parameter that has no parameter definition
+func UndeclaredPathParam(pointer, param string) *Validation {
+	return &Validation{
+		Code:    422,
+		Name:    pointer,
+		Value:   param,
+		Message: fmt.Sprintf(undeclaredPathParam, pointer, param),
+	}
+}
+
+// UnusedPathParam error for a path parameter that doesn't appear in the path template
+func UnusedPathParam(pointer, param string) *Validation {
+	return &Validation{
+		Code:    422,
+		Name:    pointer,
+		Value:   param,
+		Message: fmt.Sprintf(unusedPathParam, pointer, param),
+	}
+}
+
+// DuplicateParam error for a parameter declared twice with the same name and location
+func DuplicateParam(pointer, name, in string) *Validation {
+	return &Validation{
+		Code:    422,
+		Name:    pointer,
+		In:      in,
+		Value:   name,
+		Message: fmt.Sprintf(duplicateParam, pointer, name, in),
+	}
+}
+
+// UnresolvedRef error for a $ref that doesn't point to anything
+func UnresolvedRef(pointer, ref string, reason error) *Validation {
+	return &Validation{
+		Code:    422,
+		Name:    pointer,
+		Value:   ref,
+		Message: fmt.Sprintf(unresolvedRef, pointer, ref, reason),
+	}
+}
@@ -20,6 +20,10 @@ type Order struct {
 }
 
 func (m *Order) Validate() error {
+	if m.Id == 0 {
+		return errors.Required("id", "body")
+	}
+
 	if err := m.validateContact(); err != nil {
 		return err
 	}
@@ -1,16 +1,31 @@
 package models
 
-import "github.com/aiyi/swagger-gin/validate"
+import (
+	"github.com/aiyi/swagger-gin/errors"
+	"github.com/aiyi/swagger-gin/validate"
+)
 
 type Pet struct {
-	Category  Category `json:"category,omitempty"`
-	Id        int64    `json:"id,omitempty"`
-	Name      string   `json:"name" binding:"required"`
-	PhotoUrls []string `json:"photoUrls" binding:"required"`
-	Status    string   `json:"status,omitempty"`
+	Category  *Category `json:"category,omitempty"`
+	Id        int64     `json:"id,omitempty"`
+	Name      string    `json:"name" binding:"required"`
+	PhotoUrls []string  `json:"photoUrls" binding:"required"`
+	Status    string    `json:"status,omitempty"`
 }
 
 func (m *Pet) Validate() error {
+	if m.Name == "" {
+		return errors.Required("name", "body")
+	}
+
+	if m.PhotoUrls == nil {
+		return errors.Required("photoUrls", "body")
+	}
+
+	if err := m.validateCategory(); err != nil {
+		return err
+	}
+
 	if err := m.validateName(); err != nil {
 		return err
 	}
@@ -22,6 +37,18 @@ func (m *Pet) Validate() error {
 	return nil
 }
 
+func (m *Pet) validateCategory() error {
+	if m.Category == nil {
+		return nil
+	}
+
+	if err := m.Category.Validate(); err != nil {
+		return errors.Nested("category", err)
+	}
+
+	return nil
+}
+
 func (m *Pet) validateName() error {
 	if err := validate.MaxLength("name", "body", string(m.Name), 30); err != nil {
 		return err
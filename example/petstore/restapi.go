@@ -4,15 +4,16 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/aiyi/swagger-gin/errors"
 	"github.com/aiyi/swagger-gin/example/petstore/models"
 	"github.com/aiyi/swagger-gin/example/petstore/operations"
 	"github.com/gin-gonic/gin"
 )
 
 var (
-	Users *gin.RouterGroup
 	Pets  *gin.RouterGroup
 	Store *gin.RouterGroup
+	Users *gin.RouterGroup
 )
 
 func AddRoutes() {
@@ -38,38 +39,50 @@ func AddRoutes() {
 func AddPetHandler(c *gin.Context) {
 	var body models.Pet
 
-	if err := c.BindJSON(&body); err != nil {
+	if err := c.ShouldBindJSON(&body); err != nil {
+		switch err.(type) {
+		case *errors.Validation, *errors.CompositeError:
+			errors.ServeError(c, err)
+		default:
+			errors.ServeError(c, errors.ParseError("body", "body", "", err))
+		}
 		return
 	}
 
 	if err := body.Validate(); err != nil {
-		c.JSON(http.StatusBadRequest, err)
+		errors.ServeError(c, err)
 		return
 	}
 
 	if err := operations.AddPet(&body); err == nil {
 		c.String(http.StatusOK, "Success")
 	} else {
-		c.JSON(http.StatusBadRequest, err)
+		errors.ServeError(c, err)
 	}
 }
 
 func UpdatePetHandler(c *gin.Context) {
 	var body models.Pet
 
-	if err := c.BindJSON(&body); err != nil {
+	if err := c.ShouldBindJSON(&body); err != nil {
+		switch err.(type) {
+		case *errors.Validation, *errors.CompositeError:
+			errors.ServeError(c, err)
+		default:
+			errors.ServeError(c, errors.ParseError("body", "body", "", err))
+		}
 		return
 	}
 
 	if err := body.Validate(); err != nil {
-		c.JSON(http.StatusBadRequest, err)
+		errors.ServeError(c, err)
 		return
 	}
 
 	if err := operations.UpdatePet(&body); err == nil {
 		c.String(http.StatusOK, "Success")
 	} else {
-		c.JSON(http.StatusBadRequest, err)
+		errors.ServeError(c, err)
 	}
 }
 
@@ -78,26 +91,26 @@ func UpdatePetWithFormHandler(c *gin.Context) {
 
 	petId := queryValues.Get("petId")
 	if petId == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"missing": "petId"})
+		errors.ServeError(c, errors.Required("petId", "query"))
 		return
 	}
 
 	name := c.Request.PostFormValue("name")
 	if name == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"missing": "name"})
+		errors.ServeError(c, errors.Required("name", "formData"))
 		return
 	}
 
 	status := c.Request.PostFormValue("status")
 	if status == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"missing": "status"})
+		errors.ServeError(c, errors.Required("status", "formData"))
 		return
 	}
 
 	if err := operations.UpdatePetWithForm(petId, name, status); err == nil {
 		c.String(http.StatusOK, "Success")
 	} else {
-		c.JSON(http.StatusBadRequest, err)
+		errors.ServeError(c, err)
 	}
 }
 
@@ -106,13 +119,13 @@ func GetPetByIdHandler(c *gin.Context) {
 
 	strPetId := queryValues.Get("petId")
 	if strPetId == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"missing": "petId"})
+		errors.ServeError(c, errors.Required("petId", "query"))
 		return
 	}
 
 	var petId int64
 	if i, err := strconv.ParseInt(strPetId, 10, 64); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"invalid": "petId"})
+		errors.ServeError(c, errors.ParseError("petId", "query", strPetId, err))
 		return
 	} else {
 		petId = int64(i)
@@ -121,7 +134,7 @@ func GetPetByIdHandler(c *gin.Context) {
 	if resp, err := operations.GetPetById(petId); err == nil {
 		c.JSON(http.StatusOK, resp)
 	} else {
-		c.JSON(http.StatusBadRequest, err)
+		errors.ServeError(c, err)
 	}
 }
 
@@ -130,13 +143,13 @@ func DeletePetHandler(c *gin.Context) {
 
 	strPetId := queryValues.Get("petId")
 	if strPetId == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"missing": "petId"})
+		errors.ServeError(c, errors.Required("petId", "query"))
 		return
 	}
 
 	var petId int64
 	if i, err := strconv.ParseInt(strPetId, 10, 64); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"invalid": "petId"})
+		errors.ServeError(c, errors.ParseError("petId", "query", strPetId, err))
 		return
 	} else {
 		petId = int64(i)
@@ -145,7 +158,32 @@ func DeletePetHandler(c *gin.Context) {
 	if err := operations.DeletePet(petId); err == nil {
 		c.String(http.StatusOK, "Success")
 	} else {
-		c.JSON(http.StatusBadRequest, err)
+		errors.ServeError(c, err)
+	}
+}
+
+func PlaceOrderHandler(c *gin.Context) {
+	var body models.Order
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		switch err.(type) {
+		case *errors.Validation, *errors.CompositeError:
+			errors.ServeError(c, err)
+		default:
+			errors.ServeError(c, errors.ParseError("body", "body", "", err))
+		}
+		return
+	}
+
+	if err := body.Validate(); err != nil {
+		errors.ServeError(c, err)
+		return
+	}
+
+	if resp, err := operations.PlaceOrder(&body); err == nil {
+		c.JSON(http.StatusOK, resp)
+	} else {
+		errors.ServeError(c, err)
 	}
 }
 
@@ -154,14 +192,14 @@ func GetOrderByIdHandler(c *gin.Context) {
 
 	orderId := queryValues.Get("orderId")
 	if orderId == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"missing": "orderId"})
+		errors.ServeError(c, errors.Required("orderId", "query"))
 		return
 	}
 
 	if resp, err := operations.GetOrderById(orderId); err == nil {
 		c.JSON(http.StatusOK, resp)
 	} else {
-		c.JSON(http.StatusBadRequest, err)
+		errors.ServeError(c, err)
 	}
 }
 
@@ -170,33 +208,39 @@ func DeleteOrderHandler(c *gin.Context) {
 
 	orderId := queryValues.Get("orderId")
 	if orderId == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"missing": "orderId"})
+		errors.ServeError(c, errors.Required("orderId", "query"))
 		return
 	}
 
 	if err := operations.DeleteOrder(orderId); err == nil {
 		c.String(http.StatusOK, "Success")
 	} else {
-		c.JSON(http.StatusBadRequest, err)
+		errors.ServeError(c, err)
 	}
 }
 
-func PlaceOrderHandler(c *gin.Context) {
-	var body models.Order
+func CreateUserHandler(c *gin.Context) {
+	var body models.User
 
-	if err := c.BindJSON(&body); err != nil {
+	if err := c.ShouldBindJSON(&body); err != nil {
+		switch err.(type) {
+		case *errors.Validation, *errors.CompositeError:
+			errors.ServeError(c, err)
+		default:
+			errors.ServeError(c, errors.ParseError("body", "body", "", err))
+		}
 		return
 	}
 
 	if err := body.Validate(); err != nil {
-		c.JSON(http.StatusBadRequest, err)
+		errors.ServeError(c, err)
 		return
 	}
 
-	if resp, err := operations.PlaceOrder(&body); err == nil {
-		c.JSON(http.StatusOK, resp)
+	if err := operations.CreateUser(&body); err == nil {
+		c.String(http.StatusOK, "Success")
 	} else {
-		c.JSON(http.StatusBadRequest, err)
+		errors.ServeError(c, err)
 	}
 }
 
@@ -210,7 +254,7 @@ func LoginUserHandler(c *gin.Context) {
 	if err := operations.LoginUser(username, password); err == nil {
 		c.String(http.StatusOK, "Success")
 	} else {
-		c.JSON(http.StatusBadRequest, err)
+		errors.ServeError(c, err)
 	}
 }
 
@@ -218,7 +262,7 @@ func LogoutUserHandler(c *gin.Context) {
 	if err := operations.LogoutUser(); err == nil {
 		c.String(http.StatusOK, "Success")
 	} else {
-		c.JSON(http.StatusBadRequest, err)
+		errors.ServeError(c, err)
 	}
 }
 
@@ -227,14 +271,14 @@ func GetUserByNameHandler(c *gin.Context) {
 
 	username := queryValues.Get("username")
 	if username == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"missing": "username"})
+		errors.ServeError(c, errors.Required("username", "query"))
 		return
 	}
 
 	if resp, err := operations.GetUserByName(username); err == nil {
 		c.JSON(http.StatusOK, resp)
 	} else {
-		c.JSON(http.StatusBadRequest, err)
+		errors.ServeError(c, err)
 	}
 }
 
@@ -243,25 +287,31 @@ func UpdateUserHandler(c *gin.Context) {
 
 	username := queryValues.Get("username")
 	if username == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"missing": "username"})
+		errors.ServeError(c, errors.Required("username", "query"))
 		return
 	}
 
 	var body models.User
 
-	if err := c.BindJSON(&body); err != nil {
+	if err := c.ShouldBindJSON(&body); err != nil {
+		switch err.(type) {
+		case *errors.Validation, *errors.CompositeError:
+			errors.ServeError(c, err)
+		default:
+			errors.ServeError(c, errors.ParseError("body", "body", "", err))
+		}
 		return
 	}
 
 	if err := body.Validate(); err != nil {
-		c.JSON(http.StatusBadRequest, err)
+		errors.ServeError(c, err)
 		return
 	}
 
 	if err := operations.UpdateUser(username, &body); err == nil {
 		c.String(http.StatusOK, "Success")
 	} else {
-		c.JSON(http.StatusBadRequest, err)
+		errors.ServeError(c, err)
 	}
 }
 
@@ -270,32 +320,13 @@ func DeleteUserHandler(c *gin.Context) {
 
 	username := queryValues.Get("username")
 	if username == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"missing": "username"})
+		errors.ServeError(c, errors.Required("username", "query"))
 		return
 	}
 
 	if err := operations.DeleteUser(username); err == nil {
 		c.String(http.StatusOK, "Success")
 	} else {
-		c.JSON(http.StatusBadRequest, err)
-	}
-}
-
-func CreateUserHandler(c *gin.Context) {
-	var body models.User
-
-	if err := c.BindJSON(&body); err != nil {
-		return
-	}
-
-	if err := body.Validate(); err != nil {
-		c.JSON(http.StatusBadRequest, err)
-		return
-	}
-
-	if err := operations.CreateUser(&body); err == nil {
-		c.String(http.StatusOK, "Success")
-	} else {
-		c.JSON(http.StatusBadRequest, err)
+		errors.ServeError(c, err)
 	}
 }
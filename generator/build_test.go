@@ -0,0 +1,176 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const buildSpec = `
+swagger: "2.0"
+info: {title: restapi, version: "1.0"}
+paths:
+  /pets:
+    post:
+      operationId: addPet
+      tags: [pets]
+      parameters:
+        - {name: body, in: body, required: true, schema: {$ref: "#/definitions/Pet"}}
+      responses:
+        default: {description: added}
+  /events:
+    post:
+      operationId: addEvent
+      tags: [events]
+      parameters:
+        - {name: body, in: body, required: true, schema: {$ref: "#/definitions/Event"}}
+      responses:
+        default: {description: added}
+definitions:
+  Pet:
+    required: [name]
+    properties:
+      name: {type: string, minLength: 2, pattern: "^[a-z]+$"}
+      status: {type: string, enum: [available, sold]}
+      tags: {type: array, items: {$ref: "#/definitions/Tag"}}
+      tagMap: {type: object, additionalProperties: {$ref: "#/definitions/Tag"}}
+      toys:
+        type: array
+        items:
+          required: [label]
+          properties:
+            label: {type: string}
+  Tag:
+    required: [name]
+    properties:
+      name: {type: string}
+  Event:
+    type: object
+    discriminator: kind
+    required: [kind]
+    properties:
+      kind: {type: string}
+  Click:
+    allOf:
+      - $ref: "#/definitions/Event"
+      - required: [x]
+        properties: {x: {type: integer, minimum: 1}}
+`
+
+// buildMain serves the generated routes, it posts the body of every line of its input
+// to the path before it and prints the status and the name of the first error
+const buildMain = `package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"strings"
+
+	api %q
+	"github.com/gin-gonic/gin"
+)
+
+func main() {
+	gin.SetMode(gin.ReleaseMode)
+	r := gin.New()
+	api.Pets = r.Group("/pets")
+	api.Events = r.Group("/events")
+	api.AddRoutes()
+
+	lines := bufio.NewScanner(os.Stdin)
+	for lines.Scan() {
+		parts := strings.SplitN(lines.Text(), " ", 2)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("POST", parts[0], strings.NewReader(parts[1])))
+		var res struct {
+			Errors []struct{ Name string }
+		}
+		json.Unmarshal(w.Body.Bytes(), &res)
+		name := ""
+		if len(res.Errors) > 0 {
+			name = res.Errors[0].Name
+		}
+		fmt.Println(w.Code, name)
+	}
+}
+`
+
+func TestGenerate_Build(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds the generated code")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("the go tool builds the generated code")
+	}
+	// the generated code imports the packages of this module, so it's generated in it,
+	// the go tool leaves out the directories starting with _ from the patterns
+	root, err := filepath.Abs("..")
+	if !assert.NoError(t, err) {
+		return
+	}
+	if modulePath(filepath.Join(root, "go.mod")) == "" {
+		t.Skip("the go.mod of the module isn't there")
+	}
+	dir, err := ioutil.TempDir(root, "_build")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	writeSpecFiles(t, dir, map[string]string{"swagger.yml": buildSpec})
+	opts := GenOpts{Spec: filepath.Join(dir, "swagger.yml"), Target: dir}
+	if !assert.NoError(t, GenerateDefinition(true, true, opts)) {
+		return
+	}
+	if !assert.NoError(t, GenerateServerOperation(true, true, opts)) {
+		return
+	}
+	writeSpecFiles(t, dir, map[string]string{"cmd/main.go": fmt.Sprintf(buildMain, opts.importPath())})
+
+	cases := []struct {
+		path, body, result string
+	}{
+		{"/pets", `{"name": "rex", "status": "sold", "tags": [{"name": "a"}], "tagMap": {"a": {"name": "b"}}, "toys": [{"label": "c"}]}`, "200 "},
+		// the required properties of the models held by the slices and the maps
+		{"/pets", `{"name": "rex", "tags": [{}]}`, "422 tags.0.name"},
+		{"/pets", `{"name": "rex", "tagMap": {"a": {}}}`, "422 tagMap.a.name"},
+		{"/pets", `{"name": "rex", "toys": [{}]}`, "422 toys.0.label"},
+		{"/pets", `{"name": "r"}`, "422 name"},
+		{"/pets", `{"name": "Rex"}`, "422 name"},
+		{"/pets", `{"name": "rex", "status": "lost"}`, "422 status"},
+		{"/pets", `{"name": `, "400 body"},
+		// the subtypes check their required properties and the discriminator tells them
+		{"/events", `{"kind": "Click", "x": 1}`, "200 "},
+		{"/events", `{"kind": "Click"}`, "422 x"},
+		{"/events", `{"kind": "Click", "x": -1}`, "422 x"},
+		{"/events", `{"kind": "Event"}`, "422 kind"},
+		{"/events", `{}`, "422 kind"},
+	}
+	var input bytes.Buffer
+	for _, c := range cases {
+		fmt.Fprintln(&input, c.path, c.body)
+	}
+	cmd := exec.Command("go", "run", "./cmd")
+	cmd.Dir = dir
+	cmd.Stdin = &input
+	out, err := cmd.CombinedOutput()
+	if !assert.NoError(t, err, string(out)) {
+		return
+	}
+	results := strings.Split(strings.TrimSuffix(string(out), "\n"), "\n")
+	if assert.Len(t, results, len(cases), string(out)) {
+		for i, c := range cases {
+			assert.Equal(t, c.result, results[i], "%s %s", c.path, c.body)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aiyi/swagger-gin/jsonpointer"
+)
+
+// Diagnostic is a problem found in the spec while generating code,
+// the pointer locates the offending node, e.g. /paths/~1pets/post/parameters/0
+type Diagnostic struct {
+	Pointer string
+	Message string
+}
+
+func (d *Diagnostic) Error() string {
+	if d.Pointer == "" {
+		return d.Message
+	}
+	return d.Pointer + ": " + d.Message
+}
+
+// Diagnostics is the list of all the problems found in a generation run
+type Diagnostics []*Diagnostic
+
+func (d Diagnostics) Error() string {
+	msgs := make([]string, 0, len(d)+1)
+	msgs = append(msgs, fmt.Sprintf("%d problem(s) found in the spec:", len(d)))
+	for _, diag := range d {
+		msgs = append(msgs, "  "+diag.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// ErrorOrNil returns the diagnostics as an error, or nil when there are none
+func (d Diagnostics) ErrorOrNil() error {
+	if len(d) == 0 {
+		return nil
+	}
+	return d
+}
+
+func (d *Diagnostics) addf(pointer, format string, args ...interface{}) {
+	*d = append(*d, &Diagnostic{Pointer: pointer, Message: fmt.Sprintf(format, args...)})
+}
+
+// add appends an error, errors which don't carry a pointer yet are attached to the given one
+func (d *Diagnostics) add(pointer string, err error) {
+	switch e := err.(type) {
+	case nil:
+	case *Diagnostic:
+		*d = append(*d, e)
+	case Diagnostics:
+		*d = append(*d, e...)
+	default:
+		d.addf(pointer, "%v", err)
+	}
+}
+
+// sort orders the diagnostics by pointer so a run always reports them the same way
+func (d Diagnostics) sort() {
+	sort.SliceStable(d, func(i, j int) bool { return d[i].Pointer < d[j].Pointer })
+}
+
+// pointerTo builds a json pointer from unescaped reference tokens
+func pointerTo(tokens ...string) string {
+	var ptr string
+	for _, t := range tokens {
+		ptr += "/" + jsonpointer.Escape(t)
+	}
+	return ptr
+}
@@ -0,0 +1,151 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/aiyi/swagger-gin/swag"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// FileStatus tells how a generated file compares to the one on disk
+type FileStatus int
+
+const (
+	// FileUnchanged the file on disk has the same content
+	FileUnchanged FileStatus = iota
+	// FileChanged the file on disk has a different content
+	FileChanged
+	// FileCreated there is no such file on disk yet
+	FileCreated
+)
+
+func (s FileStatus) String() string {
+	switch s {
+	case FileChanged:
+		return "changed"
+	case FileCreated:
+		return "created"
+	}
+	return "unchanged"
+}
+
+// FileSet collects the generated files in memory, so they can be compared
+// with the files on disk before anything gets written
+type FileSet struct {
+	files map[string][]byte
+}
+
+// NewFileSet creates an empty file set
+func NewFileSet() *FileSet {
+	return &FileSet{files: make(map[string][]byte)}
+}
+
+// Add puts a file in the set, replacing the content it had
+func (f *FileSet) Add(path string, content []byte) {
+	f.files[filepath.Clean(path)] = content
+}
+
+// AddGoFile formats the go source and adds it to the set,
+// when it can't be formatted the source is kept as is and an error is returned
+func (f *FileSet) AddGoFile(target, name string, content []byte) error {
+	ffn := swag.ToFileName(name) + ".go"
+	path := filepath.Join(target, ffn)
+	res, err := formatGoFile(ffn, content)
+	if err != nil {
+		f.Add(path, content)
+		return fmt.Errorf("formatting %s: %v", path, err)
+	}
+	f.Add(path, res)
+	return nil
+}
+
+// Paths returns the paths of the files in the set, sorted
+func (f *FileSet) Paths() []string {
+	paths := make([]string, 0, len(f.files))
+	for path := range f.files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Content returns the content of a file in the set
+func (f *FileSet) Content(path string) ([]byte, bool) {
+	content, ok := f.files[filepath.Clean(path)]
+	return content, ok
+}
+
+// Status compares a file of the set with the file on disk
+func (f *FileSet) Status(path string) (FileStatus, error) {
+	current, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return FileCreated, nil
+	}
+	if err != nil {
+		return FileUnchanged, err
+	}
+	content, _ := f.Content(path)
+	if bytes.Equal(current, content) {
+		return FileUnchanged, nil
+	}
+	return FileChanged, nil
+}
+
+// Diff returns the unified diff between the file on disk and the file of the set,
+// the diff is empty when they are identical
+func (f *FileSet) Diff(path string) (string, error) {
+	var current []byte
+	from := path
+	if b, err := ioutil.ReadFile(path); err == nil {
+		current = b
+	} else if os.IsNotExist(err) {
+		from = os.DevNull
+	} else {
+		return "", err
+	}
+
+	content, _ := f.Content(path)
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(current),
+		B:        splitLines(content),
+		FromFile: from,
+		ToFile:   path,
+		Context:  3,
+	})
+}
+
+// splitLines splits the content after each newline, unlike difflib.SplitLines
+// it doesn't add an empty line after the last one
+func splitLines(content []byte) []string {
+	lines := strings.SplitAfter(string(content), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// Write writes the files of the set to disk, skipping the ones that are unchanged,
+// and returns the paths of the files it has written
+func (f *FileSet) Write() ([]string, error) {
+	var written []string
+	for _, path := range f.Paths() {
+		status, err := f.Status(path)
+		if err != nil {
+			return written, err
+		}
+		if status == FileUnchanged {
+			continue
+		}
+		if err := writeFile(filepath.Dir(path), filepath.Base(path), f.files[path]); err != nil {
+			return written, err
+		}
+		written = append(written, path)
+	}
+	return written, nil
+}
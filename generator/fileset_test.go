@@ -0,0 +1,142 @@
+package generator
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileSet_Status(t *testing.T) {
+	dir := tempGenDir(t)
+	defer os.RemoveAll(dir)
+	writeSpecFiles(t, dir, map[string]string{
+		"same.txt":    "same\n",
+		"changed.txt": "before\n",
+	})
+
+	files := NewFileSet()
+	files.Add(filepath.Join(dir, "same.txt"), []byte("same\n"))
+	files.Add(filepath.Join(dir, "changed.txt"), []byte("after\n"))
+	files.Add(filepath.Join(dir, "sub", "..", "created.txt"), []byte("new\n"))
+
+	assert.Equal(t, []string{
+		filepath.Join(dir, "changed.txt"),
+		filepath.Join(dir, "created.txt"),
+		filepath.Join(dir, "same.txt"),
+	}, files.Paths())
+
+	for name, expected := range map[string]FileStatus{
+		"same.txt":    FileUnchanged,
+		"changed.txt": FileChanged,
+		"created.txt": FileCreated,
+	} {
+		status, err := files.Status(filepath.Join(dir, name))
+		if assert.NoError(t, err) {
+			assert.Equal(t, expected, status, name)
+		}
+	}
+	assert.Equal(t, "created", FileCreated.String())
+}
+
+func TestFileSet_Diff(t *testing.T) {
+	dir := tempGenDir(t)
+	defer os.RemoveAll(dir)
+	writeSpecFiles(t, dir, map[string]string{"changed.txt": "one\ntwo\n"})
+
+	files := NewFileSet()
+	changed := filepath.Join(dir, "changed.txt")
+	files.Add(changed, []byte("one\nthree\n"))
+	diff, err := files.Diff(changed)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "--- "+changed+"\n+++ "+changed+"\n@@ -1,2 +1,2 @@\n one\n-two\n+three\n", diff)
+	}
+
+	created := filepath.Join(dir, "created.txt")
+	files.Add(created, []byte("new\n"))
+	diff, err = files.Diff(created)
+	if assert.NoError(t, err) {
+		assert.Contains(t, diff, "--- "+os.DevNull+"\n")
+		assert.Contains(t, diff, "+new\n")
+	}
+
+	files.Add(changed, []byte("one\ntwo\n"))
+	diff, err = files.Diff(changed)
+	if assert.NoError(t, err) {
+		assert.Empty(t, diff)
+	}
+}
+
+func TestFileSet_Write(t *testing.T) {
+	dir := tempGenDir(t)
+	defer os.RemoveAll(dir)
+	writeSpecFiles(t, dir, map[string]string{"same.txt": "same\n"})
+	same := filepath.Join(dir, "same.txt")
+	created := filepath.Join(dir, "pkg", "created.txt")
+
+	files := NewFileSet()
+	files.Add(same, []byte("same\n"))
+	files.Add(created, []byte("new\n"))
+	written, err := files.Write()
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{created}, written)
+	}
+	content, err := ioutil.ReadFile(created)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "new\n", string(content))
+	}
+
+	// a second write finds everything up to date
+	written, err = files.Write()
+	if assert.NoError(t, err) {
+		assert.Empty(t, written)
+	}
+}
+
+func TestFileSet_AddGoFile(t *testing.T) {
+	files := NewFileSet()
+	assert.NoError(t, files.AddGoFile("models", "PetTag", []byte("package models\nfunc f() { fmt.Println(\"x\") }\n")))
+	content, ok := files.Content(filepath.Join("models", "pet_tag.go"))
+	if assert.True(t, ok) {
+		assert.Equal(t, "package models\n\nimport \"fmt\"\n\nfunc f() { fmt.Println(\"x\") }\n", string(content))
+	}
+
+	// the source which doesn't format is kept as is
+	broken := []byte("package models\nfunc f( {\n")
+	err := files.AddGoFile("models", "broken", broken)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "formatting "+filepath.Join("models", "broken.go"))
+	}
+	content, _ = files.Content(filepath.Join("models", "broken.go"))
+	assert.Equal(t, broken, content)
+}
+
+func TestGenerateServerOperation_DryRun(t *testing.T) {
+	dir := tempGenDir(t)
+	defer os.RemoveAll(dir)
+	opts := genOpts(t, dir, taggedPets)
+
+	if !assert.NoError(t, GenerateServerOperation(true, true, opts)) {
+		return
+	}
+	assert.Equal(t, []string{
+		filepath.Join(opts.Target, "operations", "operations.go"),
+		filepath.Join(opts.Target, "restapi.go"),
+	}, opts.Files.Paths())
+	// nothing is written while the files go to the set
+	_, err := os.Stat(opts.Target)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestGenerateServerOperation_ServerPackage(t *testing.T) {
+	dir := tempGenDir(t)
+	defer os.RemoveAll(dir)
+	opts := genOpts(t, dir, taggedPets)
+	opts.ServerPackage = "api"
+
+	if assert.NoError(t, GenerateServerOperation(true, true, opts)) {
+		assert.Contains(t, opts.Files.Paths(), filepath.Join(opts.Target, "api.go"))
+	}
+}
@@ -0,0 +1,231 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aiyi/swagger-gin/jsonpointer"
+	"github.com/aiyi/swagger-gin/spec"
+)
+
+// filterSpec narrows the spec down to the operations carrying one of the tags or
+// operation ids, and to the definitions those operations reach through their
+// parameters, responses and nested $refs. Without filters the spec is returned as is.
+func filterSpec(specDoc *spec.Document, tags, operationIDs []string) (*spec.Document, error) {
+	if len(tags) == 0 && len(operationIDs) == 0 {
+		return specDoc, nil
+	}
+
+	var diags Diagnostics
+	known := make(map[string]bool)
+	for _, id := range specDoc.OperationIDs() {
+		known[id] = true
+	}
+	for _, id := range operationIDs {
+		if !known[id] {
+			diags.addf("", "operation %q not found in the spec", id)
+		}
+	}
+	if len(diags) > 0 {
+		return nil, diags
+	}
+
+	// work on a copy, the filtered operations are removed from it
+	raw, err := json.Marshal(specDoc.Spec())
+	if err != nil {
+		return nil, err
+	}
+	var sw spec.Swagger
+	if err := json.Unmarshal(raw, &sw); err != nil {
+		return nil, err
+	}
+
+	reach := &reachability{spec: &sw, models: make(map[string]bool)}
+	if sw.Paths != nil {
+		for name, item := range sw.Paths.Paths {
+			for _, op := range []**spec.Operation{&item.Get, &item.Put, &item.Post, &item.Delete, &item.Patch, &item.Head, &item.Options} {
+				if *op == nil {
+					continue
+				}
+				if !matchesFilter(*op, tags, operationIDs) {
+					*op = nil
+					continue
+				}
+				reach.operation(&item, *op)
+			}
+			if item.Get == nil && item.Put == nil && item.Post == nil && item.Delete == nil &&
+				item.Patch == nil && item.Head == nil && item.Options == nil {
+				delete(sw.Paths.Paths, name)
+				continue
+			}
+			sw.Paths.Paths[name] = item
+		}
+	}
+	if sw.Paths == nil || len(sw.Paths.Paths) == 0 {
+		return nil, fmt.Errorf("no operation matches the tags [%s] or the operations [%s]",
+			strings.Join(tags, ", "), strings.Join(operationIDs, ", "))
+	}
+
+	reach.subtypes()
+	for name := range sw.Definitions {
+		if !reach.models[name] {
+			delete(sw.Definitions, name)
+		}
+	}
+
+	raw, err = json.Marshal(&sw)
+	if err != nil {
+		return nil, err
+	}
+	return specDoc.Derive(raw)
+}
+
+func matchesFilter(op *spec.Operation, tags, operationIDs []string) bool {
+	for _, id := range operationIDs {
+		if op.ID == id {
+			return true
+		}
+	}
+	for _, tag := range tags {
+		for _, t := range op.Tags {
+			if t == tag {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// reachability collects the definitions used by a set of operations
+type reachability struct {
+	spec   *spec.Swagger
+	models map[string]bool
+}
+
+func (r *reachability) operation(item *spec.PathItem, op *spec.Operation) {
+	for _, params := range [][]spec.Parameter{item.Parameters, op.Parameters} {
+		for _, param := range params {
+			r.parameter(param, 0)
+		}
+	}
+	if op.Responses == nil {
+		return
+	}
+	if op.Responses.Default != nil {
+		r.response(*op.Responses.Default, 0)
+	}
+	codes := make([]int, 0, len(op.Responses.StatusCodeResponses))
+	for code := range op.Responses.StatusCodeResponses {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	for _, code := range codes {
+		r.response(op.Responses.StatusCodeResponses[code], 0)
+	}
+}
+
+func (r *reachability) parameter(param spec.Parameter, depth int) {
+	if name, ok := localName(&param.Ref, "parameters"); ok {
+		if p, found := r.spec.Parameters[name]; found && depth < maxRefDepth {
+			r.parameter(p, depth+1)
+		}
+		return
+	}
+	r.schema(param.Schema)
+}
+
+func (r *reachability) response(resp spec.Response, depth int) {
+	if name, ok := localName(&resp.Ref, "responses"); ok {
+		if rs, found := r.spec.Responses[name]; found && depth < maxRefDepth {
+			r.response(rs, depth+1)
+		}
+		return
+	}
+	r.schema(resp.Schema)
+}
+
+func (r *reachability) schema(schema *spec.Schema) {
+	if schema == nil {
+		return
+	}
+	if name, ok := localName(&schema.Ref, "definitions"); ok {
+		if r.models[name] {
+			return
+		}
+		r.models[name] = true
+		if def, found := r.spec.Definitions[name]; found {
+			r.schema(&def)
+		}
+		return
+	}
+
+	for k := range schema.Properties {
+		prop := schema.Properties[k]
+		r.schema(&prop)
+	}
+	for k := range schema.PatternProperties {
+		prop := schema.PatternProperties[k]
+		r.schema(&prop)
+	}
+	for _, dep := range schema.Dependencies {
+		r.schema(dep.Schema)
+	}
+	for _, list := range [][]spec.Schema{schema.AllOf, schema.AnyOf, schema.OneOf} {
+		for i := range list {
+			r.schema(&list[i])
+		}
+	}
+	r.schema(schema.Not)
+	if schema.Items != nil {
+		r.schema(schema.Items.Schema)
+		for i := range schema.Items.Schemas {
+			r.schema(&schema.Items.Schemas[i])
+		}
+	}
+	if schema.AdditionalProperties != nil {
+		r.schema(schema.AdditionalProperties.Schema)
+	}
+	if schema.AdditionalItems != nil {
+		r.schema(schema.AdditionalItems.Schema)
+	}
+}
+
+// subtypes adds the models extending the base types reached, a base type is read
+// into its subtypes, until no new model is reached
+func (r *reachability) subtypes() {
+	for {
+		added := false
+		for base, subtypes := range discriminatedSubtypes(r.spec) {
+			if !r.models[base] {
+				continue
+			}
+			for _, sub := range subtypes {
+				if !r.models[sub.Name] {
+					r.schema(spec.RefProperty("#/definitions/" + jsonpointer.Escape(sub.Name)))
+					added = true
+				}
+			}
+		}
+		if !added {
+			return
+		}
+	}
+}
+
+// the maximum number of hops followed through parameter and response references
+const maxRefDepth = 32
+
+// localName returns the name of the entry a reference like #/definitions/Pet points to
+func localName(ref *spec.Ref, section string) (string, bool) {
+	u := ref.GetURL()
+	if u == nil || u.Host != "" || u.Path != "" {
+		return "", false
+	}
+	prefix := "/" + section + "/"
+	if !strings.HasPrefix(u.Fragment, prefix) {
+		return "", false
+	}
+	return jsonpointer.Unescape(strings.TrimPrefix(u.Fragment, prefix)), true
+}
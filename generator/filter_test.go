@@ -0,0 +1,159 @@
+package generator
+
+import (
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/aiyi/swagger-gin/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+const taggedPets = `
+swagger: "2.0"
+info: {title: restapi, version: "1.0"}
+paths:
+  /pets:
+    parameters:
+      - {$ref: "#/parameters/owner"}
+    get:
+      operationId: listPets
+      tags: [pets]
+      responses:
+        200: {$ref: "#/responses/pets"}
+    post:
+      operationId: addPet
+      tags: [pets, admin]
+      parameters:
+        - {name: body, in: body, schema: {$ref: "#/definitions/Pet"}}
+      responses:
+        default: {description: added}
+  /orders:
+    get:
+      operationId: listOrders
+      tags: [store]
+      responses:
+        200: {description: the orders, schema: {type: array, items: {$ref: "#/definitions/Order"}}}
+parameters:
+  owner: {name: owner, in: body, schema: {$ref: "#/definitions/Owner"}}
+responses:
+  pets: {description: the pets, schema: {type: array, items: {$ref: "#/definitions/Pet"}}}
+definitions:
+  Pet:
+    type: object
+    discriminator: kind
+    required: [kind]
+    properties:
+      kind: {type: string}
+      tags: {type: array, items: {$ref: "#/definitions/Tag"}}
+  Dog:
+    allOf:
+      - {$ref: "#/definitions/Pet"}
+      - properties: {collar: {$ref: "#/definitions/Collar"}}
+  Collar:
+    properties: {size: {type: integer, format: int32}}
+  Tag:
+    properties: {label: {type: string}}
+  Owner:
+    properties: {name: {type: string}}
+  Order:
+    properties: {pet: {$ref: "#/definitions/Pet"}}
+`
+
+func loadTaggedPets(t *testing.T) *spec.Document {
+	dir := tempGenDir(t)
+	defer os.RemoveAll(dir)
+	opts := genOpts(t, dir, taggedPets)
+	doc, err := spec.Load(opts.Spec)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return doc
+}
+
+func operationIDs(doc *spec.Document) []string {
+	ids := doc.OperationIDs()
+	sort.Strings(ids)
+	return ids
+}
+
+func definitionNames(doc *spec.Document) []string {
+	var names []string
+	for name := range doc.Spec().Definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestFilterSpec_Tags(t *testing.T) {
+	doc := loadTaggedPets(t)
+
+	filtered, err := filterSpec(doc, []string{"pets"}, nil)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"addPet", "listPets"}, operationIDs(filtered))
+		// the owner comes from the path parameters, the dog extends a reached base type
+		// and brings its collar along
+		assert.Equal(t, []string{"Collar", "Dog", "Owner", "Pet", "Tag"}, definitionNames(filtered))
+	}
+	// the original document is left untouched
+	assert.Len(t, doc.OperationIDs(), 3)
+	assert.Len(t, doc.Spec().Definitions, 6)
+}
+
+func TestFilterSpec_Operations(t *testing.T) {
+	doc := loadTaggedPets(t)
+
+	filtered, err := filterSpec(doc, []string{"admin"}, []string{"listOrders"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"addPet", "listOrders"}, operationIDs(filtered))
+		assert.Equal(t, []string{"Collar", "Dog", "Order", "Owner", "Pet", "Tag"}, definitionNames(filtered))
+		_, ok := filtered.Spec().Paths.Paths["/pets"]
+		assert.True(t, ok)
+	}
+
+	filtered, err = filterSpec(doc, nil, []string{"listOrders"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"listOrders"}, operationIDs(filtered))
+		_, ok := filtered.Spec().Paths.Paths["/pets"]
+		assert.False(t, ok, "a path without operations left is removed")
+	}
+}
+
+func TestFilterSpec_NoFilter(t *testing.T) {
+	doc := loadTaggedPets(t)
+
+	filtered, err := filterSpec(doc, nil, nil)
+	assert.NoError(t, err)
+	assert.True(t, filtered == doc)
+}
+
+func TestFilterSpec_Errors(t *testing.T) {
+	doc := loadTaggedPets(t)
+
+	_, err := filterSpec(doc, nil, []string{"listPets", "feedPets", "walkPets"})
+	if diags, ok := err.(Diagnostics); assert.True(t, ok, "expected diagnostics, got %v", err) && assert.Len(t, diags, 2) {
+		assert.Equal(t, `operation "feedPets" not found in the spec`, diags[0].Message)
+		assert.Equal(t, `operation "walkPets" not found in the spec`, diags[1].Message)
+	}
+
+	_, err = filterSpec(doc, []string{"users"}, nil)
+	assert.EqualError(t, err, "no operation matches the tags [users] or the operations []")
+}
+
+func TestGenerateDefinition_Filtered(t *testing.T) {
+	dir := tempGenDir(t)
+	defer os.RemoveAll(dir)
+	opts := genOpts(t, dir, taggedPets)
+	opts.Operations = []string{"listOrders"}
+	opts.Target = dir
+
+	if !assert.NoError(t, GenerateDefinition(true, true, opts)) {
+		return
+	}
+	var models []string
+	for _, path := range opts.Files.Paths() {
+		models = append(models, path[len(dir)+1:])
+	}
+	assert.Equal(t, []string{"models/collar.go", "models/dog.go", "models/order.go", "models/pet.go", "models/tag.go"}, models)
+}
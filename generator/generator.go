@@ -4,10 +4,11 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
-	"log"
-	"os"
+	"path"
 	"reflect"
 	"runtime"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/aiyi/swagger-gin/spec"
@@ -21,6 +22,23 @@ var (
 // Generator is the type whose methods generate the output, stored in the associated response structure.
 type Generator struct {
 	*bytes.Buffer
+	diags Diagnostics
+	pkgs  genPackages
+	// nested are the names of the structs rendered for the inline objects of a model
+	nested map[string]bool
+	// bases are the names of the definitions with a discriminator, they're read with the
+	// functions picking their models
+	bases map[string]bool
+	// pointer locates the node of the spec being rendered, the failures are reported there
+	pointer string
+}
+
+// genPackages names the generated packages and tells where they are imported from
+type genPackages struct {
+	// importPath is the import path of the target, empty when goimports has to guess it
+	importPath string
+	models     string
+	api        string
 }
 
 // New creates a new generator and allocates the request and response protobufs.
@@ -39,11 +57,16 @@ func (g *Generator) lowerFirst(word string) string {
 	return strings.ToLower(word[:1]) + word[1:]
 }
 
-// reports a problem and exits the program.
+// directs the output to buf and forgets the problems of a previous run
+func (g *Generator) reset(buf *bytes.Buffer) {
+	g.Buffer = buf
+	g.diags = nil
+	g.pointer = ""
+}
+
+// records a problem, the generation carries on and the problems are returned once it's done.
 func (g *Generator) fail(msgs ...string) {
-	s := strings.Join(msgs, " ")
-	log.Print("error:", s)
-	os.Exit(1)
+	g.diags.addf(g.pointer, "%s", strings.Join(msgs, " "))
 }
 
 func (g *Generator) hasExtendFormat(prop *GenSchema) bool {
@@ -58,91 +81,361 @@ func (g *Generator) hasExtendFormat(prop *GenSchema) bool {
 
 // Fill the response protocol buffer with the generated output for all the files we're
 // supposed to generate.
-func (g *Generator) generateModel(buf *bytes.Buffer, def *GenDefinition) {
-	g.Buffer = buf
+func (g *Generator) generateModel(buf *bytes.Buffer, def *GenDefinition) error {
+	g.reset(buf)
 
 	g.p("package ", def.Package)
 	g.p()
 	g.p("import (")
 	g.p("	\"encoding/json\"")
 	g.p("	\"fmt\"")
+	g.p("	\"sort\"")
+	g.p("	\"strconv\"")
 	g.p("	\"time\"")
 	g.p("	\"github.com/asaskevich/govalidator\"")
+	g.p("	\"github.com/aiyi/swagger-gin/errors\"")
+	g.p("	\"github.com/aiyi/swagger-gin/swag\"")
 	g.p("	\"github.com/aiyi/swagger-gin/validate\"")
+	// the packages of the mapped types, goimports drops the ones the model doesn't use
+	aliases := make([]string, 0, len(def.Imports))
+	for alias := range def.Imports {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	for _, alias := range aliases {
+		g.p("	", alias, " ", strconv.Quote(def.Imports[alias]))
+	}
 	g.p(")")
 	g.p()
 
-	g.generateStruct(def)
+	// the structs built for the inline objects of the definition are rendered along with it
+	extras := GenSchemaList(def.ExtraSchemas)
+	sort.Sort(extras)
+	g.nested = make(map[string]bool, len(extras))
+	for _, extra := range extras {
+		g.nested[extra.Name] = true
+	}
 
-	for _, prop := range def.Properties {
+	if def.IsBaseType {
+		g.generateBaseType(def)
+	} else {
+		g.generateSchema(&def.GenSchema)
+	}
+	for i := range extras {
+		g.generateSchema(&extras[i])
+	}
+	return g.diags.ErrorOrNil()
+}
+
+// generateSchema renders the struct of a model with its validator
+func (g *Generator) generateSchema(schema *GenSchema) {
+	g.pointer = schema.Pointer
+	if g.isMapType(schema) {
+		g.generateMapType(schema)
+		return
+	}
+	g.generateStruct(schema)
+	if members := g.embedded(schema); len(members) > 0 {
+		g.generateCompositionJSON(schema, members)
+	} else if g.hasCatchAll(schema) || g.isClosed(schema) {
+		g.generateAdditionalPropertiesJSON(schema)
+	} else if g.hasPolymorphicProps(schema.Properties) {
+		g.p("// UnmarshalJSON reads the properties holding base types into the models their discriminator tells")
+		g.p("func (m *", schema.Name, ") UnmarshalJSON(raw []byte) error {")
+		g.generatePropsUnmarshal(schema.Properties)
+		g.p("return nil")
+		g.p("}")
+		g.p()
+	}
+
+	for _, prop := range schema.Properties {
 		if g.hasExtendFormat(&prop) {
-			def.GenSchema.sharedValidations.HasValidations = true
+			schema.sharedValidations.HasValidations = true
+		}
+	}
+
+	g.generateValidator(schema)
+
+	for _, prop := range schema.Properties {
+		if g.hasPropValidator(&prop) {
+			g.pointer = prop.Pointer
+			g.generatePropValidator(schema.Name, &prop)
+		}
+	}
+	g.pointer = schema.Pointer
+	if g.hasAdditionalPropertiesValidator(schema) {
+		g.generateAdditionalPropertiesValidator(schema)
+	}
+}
+
+// isMapType tells whether a model is an object with additional properties only, it's
+// rendered as a map of its values
+func (g *Generator) isMapType(schema *GenSchema) bool {
+	return schema.IsMap && len(schema.Properties) == 0 && len(schema.AllOf) == 0 &&
+		strings.HasPrefix(schema.GoType, "map[") && (schema.AdditionalProperties != nil || schema.HasAdditionalProperties)
+}
+
+// hasCatchAll tells whether a struct keeps the properties it doesn't declare in a map
+func (g *Generator) hasCatchAll(schema *GenSchema) bool {
+	return schema.IsAdditionalProperties && schema.AdditionalProperties != nil && len(g.embedded(schema)) == 0
+}
+
+// isClosed tells whether a struct rejects the properties it doesn't declare, a composition
+// does it in place of its closed members as they would reject the properties of the others
+func (g *Generator) isClosed(schema *GenSchema) bool {
+	if schema.IsAdditionalProperties && !schema.HasAdditionalProperties {
+		return true
+	}
+	for _, member := range g.embedded(schema) {
+		if member.IsClosedMember {
+			return true
 		}
 	}
+	return false
+}
+
+// generateMapType renders a model holding additional properties only as a map, it
+// validates the number of its properties and every value
+func (g *Generator) generateMapType(schema *GenSchema) {
+	g.p("type ", schema.Name, " ", g.goType(schema))
+	g.p()
+	if fn := g.polymorphic(schema); fn != "" {
+		g.p("// UnmarshalJSON reads every value into the model its discriminator tells")
+		g.p("func (m *", schema.Name, ") UnmarshalJSON(raw []byte) error {")
+		g.p("	values, err := ", fn, "(raw)")
+		g.p("	if err != nil {")
+		g.p("		return err")
+		g.p("	}")
+		g.p("	*m = values")
+		g.p("	return nil")
+		g.p("}")
+		g.p()
+	}
+
+	g.generateEnums(schema.Name, "", schema)
+	g.p("func (m *", schema.Name, ") Validate() error {")
+	g.generateMapValidation(schema.Name, "", schema, "*m", `""`, true)
+	g.p("	return nil")
+	g.p("}")
+	g.p()
+}
+
+// generateAdditionalPropertiesJSON reads the properties a struct doesn't declare, they
+// go to its AdditionalProperties or, when it has none, are kept for the validator to
+// reject. The additional properties are written along with the declared ones.
+func (g *Generator) generateAdditionalPropertiesJSON(schema *GenSchema) {
+	g.p("// UnmarshalJSON reads the declared properties and the additional ones apart")
+	g.p("func (m *", schema.Name, ") UnmarshalJSON(raw []byte) error {")
+	if len(schema.Properties) > 0 {
+		g.generatePropsUnmarshal(schema.Properties)
+	}
+	g.p("var all map[string]json.RawMessage")
+	g.p("if err := json.Unmarshal(raw, &all); err != nil {")
+	g.p("	return err")
+	g.p("}")
+	for _, prop := range schema.Properties {
+		g.p("delete(all, \"", prop.Name, "\")")
+	}
+	if g.isClosed(schema) {
+		g.p("m.unknownProperties = nil")
+		g.p("for k := range all {")
+		g.p("	m.unknownProperties = append(m.unknownProperties, k)")
+		g.p("}")
+		g.p("sort.Strings(m.unknownProperties)")
+		g.p("return nil")
+		g.p("}")
+		g.p()
+		return
+	}
+	value := schema.AdditionalProperties
+	valueType := g.goType(value)
+	g.p("if len(all) == 0 {")
+	g.p("	m.AdditionalProperties = nil")
+	g.p("	return nil")
+	g.p("}")
+	g.p("m.AdditionalProperties = make(map[string]", valueType, ", len(all))")
+	g.p("for k, value := range all {")
+	if value.IsBaseType {
+		g.p("	v, err := Unmarshal", value.GoType, "(value)")
+		g.p("	if err != nil {")
+		g.p("		return errors.Nested(k, err)")
+		g.p("	}")
+	} else {
+		g.p("	var v ", valueType)
+		g.p("	if err := json.Unmarshal(value, &v); err != nil {")
+		g.p("		return err")
+		g.p("	}")
+	}
+	g.p("	m.AdditionalProperties[k] = v")
+	g.p("}")
+	g.p("return nil")
+	g.p("}")
+	g.p()
+
+	g.p("// MarshalJSON writes the additional properties along with the declared ones")
+	g.p("func (m ", schema.Name, ") MarshalJSON() ([]byte, error) {")
+	g.p("props := struct {")
+	g.generateFields(schema.Properties)
+	g.p("}{")
+	for _, prop := range schema.Properties {
+		g.p(g.caps(prop.Name), ": m.", g.caps(prop.Name), ",")
+	}
+	g.p("}")
+	g.p("declared, err := json.Marshal(props)")
+	g.p("if err != nil || len(m.AdditionalProperties) == 0 {")
+	g.p("	return declared, err")
+	g.p("}")
+	g.p("additional, err := json.Marshal(m.AdditionalProperties)")
+	g.p("if err != nil {")
+	g.p("	return nil, err")
+	g.p("}")
+	g.p("return swag.ConcatJSON(declared, additional), nil")
+	g.p("}")
+	g.p()
+}
 
-	g.generateValidator(def)
+// generateBaseType renders a definition with a discriminator as an interface, the
+// properties it has go to a struct the models extending it embed. The functions
+// reading it pick the model from the value of the discriminator.
+func (g *Generator) generateBaseType(def *GenDefinition) {
+	field := def.DiscriminatorField
+	g.p("// ", def.Name, " is implemented by the models extending it, the ", field, " property tells them apart")
+	g.p("type ", def.Name, " interface {")
+	g.p(g.caps(field), "() string")
+	g.p("Validate() error")
+	g.p("}")
+	g.p()
 
+	// the discriminator isn't a field, the models tell their value with the method
+	base := def.GenSchema
+	base.Name = def.Name + "Base"
+	base.Properties = nil
 	for _, prop := range def.Properties {
-		if prop.sharedValidations.HasValidations || g.hasExtendFormat(&prop) {
-			g.generatePropValidator(def.Name, &prop)
+		if prop.Name != field {
+			base.Properties = append(base.Properties, prop)
 		}
 	}
+	g.p("// ", base.Name, " holds the properties every ", def.Name, " has")
+	g.generateSchema(&base)
+
+	values := make([]string, 0, len(def.Subtypes))
+	for _, sub := range def.Subtypes {
+		values = append(values, strconv.Quote(sub.Value))
+	}
+	g.p("// Unmarshal", def.Name, " reads raw into the model of ", def.Name, " its ", field, " property tells")
+	g.p("func Unmarshal", def.Name, "(raw []byte) (", def.Name, ", error) {")
+	g.p("var base struct {")
+	g.p(g.caps(field), " string `json:\"", field, "\"`")
+	g.p("}")
+	g.p("if err := json.Unmarshal(raw, &base); err != nil {")
+	g.p("	return nil, err")
+	g.p("}")
+	g.p()
+	g.p("var m ", def.Name)
+	g.p("switch base.", g.caps(field), " {")
+	for _, sub := range def.Subtypes {
+		g.p("case ", strconv.Quote(sub.Value), ":")
+		g.p("	m = new(", sub.Name, ")")
+	}
+	g.p("case \"\":")
+	g.p("	return nil, errors.Required(\"", field, "\", \"body\")")
+	g.p("default:")
+	g.p("	return nil, errors.EnumFail(\"", field, "\", \"body\", base.", g.caps(field), ", []interface{}{", strings.Join(values, ", "), "})")
+	g.p("}")
+	g.p("if err := json.Unmarshal(raw, m); err != nil {")
+	g.p("	return nil, err")
+	g.p("}")
+	g.p("return m, nil")
+	g.p("}")
+	g.p()
+
+	g.p("// Unmarshal", def.Name, "Slice reads an array of ", def.Name, ", every item into the model it tells")
+	g.p("func Unmarshal", def.Name, "Slice(raw []byte) ([]", def.Name, ", error) {")
+	g.p("var items []json.RawMessage")
+	g.p("if err := json.Unmarshal(raw, &items); err != nil {")
+	g.p("	return nil, err")
+	g.p("}")
+	g.p("res := make([]", def.Name, ", 0, len(items))")
+	g.p("for i, item := range items {")
+	g.p("	m, err := Unmarshal", def.Name, "(item)")
+	g.p("	if err != nil {")
+	g.p("		return nil, errors.Nested(strconv.Itoa(i), err)")
+	g.p("	}")
+	g.p("	res = append(res, m)")
+	g.p("}")
+	g.p("return res, nil")
+	g.p("}")
+	g.p()
+
+	g.p("// Unmarshal", def.Name, "Map reads an object of ", def.Name, ", every value into the model it tells")
+	g.p("func Unmarshal", def.Name, "Map(raw []byte) (map[string]", def.Name, ", error) {")
+	g.p("var values map[string]json.RawMessage")
+	g.p("if err := json.Unmarshal(raw, &values); err != nil {")
+	g.p("	return nil, err")
+	g.p("}")
+	g.p("res := make(map[string]", def.Name, ", len(values))")
+	g.p("for k, value := range values {")
+	g.p("	m, err := Unmarshal", def.Name, "(value)")
+	g.p("	if err != nil {")
+	g.p("		return nil, errors.Nested(k, err)")
+	g.p("	}")
+	g.p("	res[k] = m")
+	g.p("}")
+	g.p("return res, nil")
+	g.p("}")
+	g.p()
 }
 
-func (g *Generator) generateHandlers(buf *bytes.Buffer, specDoc *spec.Document) {
-	g.Buffer = buf
+// generateHandlers renders the routes and handlers, the generated packages are imported
+// from the import path of the target when it's known and left to goimports otherwise
+func (g *Generator) generateHandlers(buf *bytes.Buffer, specDoc *spec.Document, pkgs genPackages) error {
+	g.reset(buf)
+	g.pkgs = pkgs
+	if diags := checkOperations(specDoc); len(diags) > 0 {
+		return diags
+	}
+	g.bases = baseTypes(specDoc)
 	paths := specDoc.AllPaths()
-	groups := make(map[string]string)
+	groups := g.routeGroups(paths)
 
 	g.p("package ", specDoc.Spec().Info.Title)
 	g.p()
 	g.p()
 	g.p("import (")
+	g.p("	\"github.com/aiyi/swagger-gin/errors\"")
 	g.p("	\"github.com/gin-gonic/gin\"")
+	if pkgs.importPath != "" {
+		g.p("	\"", path.Join(pkgs.importPath, pkgs.models), "\"")
+		g.p("	\"", path.Join(pkgs.importPath, pkgs.api), "\"")
+	}
 	g.p(")")
 	g.p()
 
-	for _, path := range paths {
-		operations := path.PathItemProps
-		if post := operations.Post; post != nil {
-			tag := post.OperationProps.Tags[0]
-			groups[tag] = g.caps(tag)
-		}
-		if get := operations.Get; get != nil {
-			tag := get.OperationProps.Tags[0]
-			groups[tag] = g.caps(tag)
-		}
-		if put := operations.Put; put != nil {
-			tag := put.OperationProps.Tags[0]
-			groups[tag] = g.caps(tag)
-		}
-		if del := operations.Delete; del != nil {
-			tag := del.OperationProps.Tags[0]
-			groups[tag] = g.caps(tag)
-		}
-	}
-
 	g.p("var (")
-	for _, group := range groups {
-		g.p(group, " *gin.RouterGroup")
+	for _, group := range sortedGroups(groups) {
+		g.p(groups[group], " *gin.RouterGroup")
 	}
 	g.p(")")
 	g.p()
 	g.p("func AddRoutes() {")
 
-	for group, _ := range groups {
-		for pname, path := range paths {
-			operations := path.PathItemProps
+	for _, group := range sortedGroups(groups) {
+		for _, pname := range sortedPaths(paths) {
+			operations := paths[pname].PathItemProps
 			if post := operations.Post; post != nil {
+				g.pointer = pointerTo("paths", pname, "post")
 				g.generateRouter("POST", group, pname, post)
 			}
 			if get := operations.Get; get != nil {
+				g.pointer = pointerTo("paths", pname, "get")
 				g.generateRouter("GET", group, pname, get)
 			}
 			if put := operations.Put; put != nil {
+				g.pointer = pointerTo("paths", pname, "put")
 				g.generateRouter("PUT", group, pname, put)
 			}
 			if del := operations.Delete; del != nil {
+				g.pointer = pointerTo("paths", pname, "delete")
 				g.generateRouter("DELETE", group, pname, del)
 			}
 		}
@@ -152,23 +445,55 @@ func (g *Generator) generateHandlers(buf *bytes.Buffer, specDoc *spec.Document)
 	g.p("}")
 	g.p()
 
-	for group, _ := range groups {
-		for _, path := range paths {
-			operations := path.PathItemProps
+	for _, group := range sortedGroups(groups) {
+		for _, pname := range sortedPaths(paths) {
+			operations := paths[pname].PathItemProps
 			if post := operations.Post; post != nil {
+				g.pointer = pointerTo("paths", pname, "post")
 				g.generateHandler(group, post)
 			}
 			if get := operations.Get; get != nil {
+				g.pointer = pointerTo("paths", pname, "get")
 				g.generateHandler(group, get)
 			}
 			if put := operations.Put; put != nil {
+				g.pointer = pointerTo("paths", pname, "put")
 				g.generateHandler(group, put)
 			}
 			if del := operations.Delete; del != nil {
+				g.pointer = pointerTo("paths", pname, "delete")
 				g.generateHandler(group, del)
 			}
 		}
 	}
+	return g.diags.ErrorOrNil()
+}
+
+// routeGroups maps the tags selecting the route groups to the names of the group variables
+// generateBodyError serves the error reading a body, the models reading the base types
+// it holds fail with validation errors which are served as is, the others are parse errors
+func (g *Generator) generateBodyError(name string) {
+	g.p("switch err.(type) {")
+	g.p("case *errors.Validation, *errors.CompositeError:")
+	g.p("	errors.ServeError(c, err)")
+	g.p("default:")
+	g.p("	errors.ServeError(c, errors.ParseError(\"", name, "\", \"body\", \"\", err))")
+	g.p("}")
+	g.p("return")
+}
+
+func (g *Generator) routeGroups(paths map[string]spec.PathItem) map[string]string {
+	groups := make(map[string]string)
+	for _, pname := range sortedPaths(paths) {
+		operations := paths[pname].PathItemProps
+		for _, op := range []*spec.Operation{operations.Post, operations.Get, operations.Put, operations.Delete} {
+			if op != nil {
+				tag := op.OperationProps.Tags[0]
+				groups[tag] = g.caps(tag)
+			}
+		}
+	}
+	return groups
 }
 
 func (g *Generator) generateRouter(method, group, path string, op *spec.Operation) {
@@ -193,9 +518,10 @@ func (g *Generator) generateHandler(group string, op *spec.Operation) {
 	responses := op.Responses.ResponsesProps.StatusCodeResponses
 	opParams := ""
 	modelResp := ""
+	bodyModel := ""
 
 	for status, resp := range responses {
-		if status == 200 {
+		if status == 200 && resp.Schema != nil {
 			if refUrl := resp.Schema.SchemaProps.Ref.Ref.ReferenceURL; refUrl != nil {
 				modelResp = strings.TrimPrefix(refUrl.Fragment, "/definitions/")
 				break
@@ -223,14 +549,29 @@ func (g *Generator) generateHandler(group string, op *spec.Operation) {
 		pp := param.ParamProps
 		if pp.In == "body" {
 			ref := pp.Schema.SchemaProps.Ref.Ref.ReferenceURL.Fragment
-			g.p("var ", pp.Name, " models.", strings.TrimPrefix(ref, "/definitions/"))
-			g.p()
-			g.p("if err := c.BindJSON(&body); err != nil {")
-			g.p("		return")
-			g.p("	}")
-			g.p()
+			bodyModel = strings.TrimPrefix(ref, "/definitions/")
+			if g.bases[bodyModel] {
+				// a base type is read into the model its discriminator tells
+				g.p("raw, err := c.GetRawData()")
+				g.p("if err != nil {")
+				g.p("	errors.ServeError(c, errors.ParseError(\"", pp.Name, "\", \"body\", \"\", err))")
+				g.p("	return")
+				g.p("}")
+				g.p("body, err := ", g.pkgs.models, ".Unmarshal", bodyModel, "(raw)")
+				g.p("if err != nil {")
+				g.generateBodyError(pp.Name)
+				g.p("}")
+				g.p()
+			} else {
+				g.p("var ", pp.Name, " ", g.pkgs.models, ".", bodyModel)
+				g.p()
+				g.p("if err := c.ShouldBindJSON(&body); err != nil {")
+				g.generateBodyError(pp.Name)
+				g.p("}")
+				g.p()
+			}
 			g.p("if err := body.Validate(); err != nil {")
-			g.p("	c.JSON(http.StatusBadRequest, err)")
+			g.p("	errors.ServeError(c, err)")
 			g.p("	return")
 			g.p("}")
 			g.p()
@@ -240,7 +581,7 @@ func (g *Generator) generateHandler(group string, op *spec.Operation) {
 				g.p(pp.Name, " := queryValues.Get(\"", pp.Name, "\")")
 				if pp.Required {
 					g.p("if ", pp.Name, " == \"\" {")
-					g.p("	c.JSON(http.StatusBadRequest, gin.H{\"missing\": \"", pp.Name, "\"})")
+					g.p("	errors.ServeError(c, errors.Required(\"", pp.Name, "\", \"query\"))")
 					g.p("	return")
 					g.p("}")
 				}
@@ -250,12 +591,12 @@ func (g *Generator) generateHandler(group string, op *spec.Operation) {
 				g.p(strName, " := queryValues.Get(\"", pp.Name, "\")")
 				if pp.Required {
 					g.p("if ", strName, " == \"\" {")
-					g.p("	c.JSON(http.StatusBadRequest, gin.H{\"missing\": \"", pp.Name, "\"})")
+					g.p("	errors.ServeError(c, errors.Required(\"", pp.Name, "\", \"query\"))")
 					g.p("	return")
 					g.p("}")
 				}
 				g.p()
-				g.generateParamInt(strName, pp.Name, param.SimpleSchema.Format)
+				g.generateParamInt(strName, pp.Name, pp.In, param.SimpleSchema.Format)
 			}
 			opParams += pp.Name + ", "
 
@@ -264,7 +605,7 @@ func (g *Generator) generateHandler(group string, op *spec.Operation) {
 				g.p(pp.Name, " := c.Request.PostFormValue(\"", pp.Name, "\")")
 				if pp.Required {
 					g.p("if ", pp.Name, " == \"\" {")
-					g.p("	c.JSON(http.StatusBadRequest, gin.H{\"missing\": \"", pp.Name, "\"})")
+					g.p("	errors.ServeError(c, errors.Required(\"", pp.Name, "\", \"formData\"))")
 					g.p("	return")
 					g.p("}")
 				}
@@ -274,12 +615,12 @@ func (g *Generator) generateHandler(group string, op *spec.Operation) {
 				g.p(strName, " := c.Request.PostFormValue(\"", pp.Name, "\")")
 				if pp.Required {
 					g.p("if ", strName, " == \"\" {")
-					g.p("	c.JSON(http.StatusBadRequest, gin.H{\"missing\": \"", pp.Name, "\"})")
+					g.p("	errors.ServeError(c, errors.Required(\"", pp.Name, "\", \"formData\"))")
 					g.p("	return")
 					g.p("}")
 				}
 				g.p()
-				g.generateParamInt(strName, pp.Name, param.SimpleSchema.Format)
+				g.generateParamInt(strName, pp.Name, pp.In, param.SimpleSchema.Format)
 			}
 			opParams += pp.Name + ", "
 
@@ -290,34 +631,38 @@ func (g *Generator) generateHandler(group string, op *spec.Operation) {
 			} else {
 				strName := "str" + g.caps(pp.Name)
 				g.p(strName, " := c.Param(\"", pp.Name, "\")")
-				g.generateParamInt(strName, pp.Name, param.SimpleSchema.Format)
+				g.generateParamInt(strName, pp.Name, pp.In, param.SimpleSchema.Format)
 			}
 			opParams += pp.Name + ", "
 		}
 	}
 
 	if hasBodyParam {
-		opParams += "&body"
+		if g.bases[bodyModel] {
+			opParams += "body"
+		} else {
+			opParams += "&body"
+		}
 	}
 
 	if modelResp != "" {
-		g.p("if resp, err := operations.", g.caps(op.OperationProps.ID), "(", strings.TrimSuffix(opParams, ", "), "); err == nil {")
+		g.p("if resp, err := ", g.pkgs.api, ".", g.caps(op.OperationProps.ID), "(", strings.TrimSuffix(opParams, ", "), "); err == nil {")
 		g.p("	c.JSON(http.StatusOK, resp)")
 	} else {
-		g.p("if err := operations.", g.caps(op.OperationProps.ID), "(", strings.TrimSuffix(opParams, ", "), "); err == nil {")
+		g.p("if err := ", g.pkgs.api, ".", g.caps(op.OperationProps.ID), "(", strings.TrimSuffix(opParams, ", "), "); err == nil {")
 		g.p("	c.String(http.StatusOK, \"Success\")")
 	}
 	g.p("} else {")
-	g.p("	c.JSON(http.StatusBadRequest, err)")
+	g.p("	errors.ServeError(c, err)")
 	g.p("}")
 	g.p("}")
 	g.p()
 }
 
-func (g *Generator) generateParamInt(strName, name, format string) {
+func (g *Generator) generateParamInt(strName, name, in, format string) {
 	g.p("var ", name, " ", format)
 	g.p("if i, err := strconv.ParseInt(", strName, ", 10, ", strings.TrimPrefix(format, "int"), "); err != nil {")
-	g.p("	c.JSON(http.StatusBadRequest, gin.H{\"invalid\": \"", name, "\"})")
+	g.p("	errors.ServeError(c, errors.ParseError(\"", name, "\", \"", in, "\", ", strName, ", err))")
 	g.p("	return")
 	g.p("} else {")
 	g.p(name, " = ", format, "(i)")
@@ -325,28 +670,121 @@ func (g *Generator) generateParamInt(strName, name, format string) {
 	g.p()
 }
 
-func (g *Generator) generateOperations(buf *bytes.Buffer, specDoc *spec.Document) {
-	g.Buffer = buf
+func (g *Generator) generateOperations(buf *bytes.Buffer, specDoc *spec.Document, pkgs genPackages) error {
+	g.reset(buf)
+	g.pkgs = pkgs
+	if diags := checkOperations(specDoc); len(diags) > 0 {
+		return diags
+	}
+	g.bases = baseTypes(specDoc)
 	paths := specDoc.AllPaths()
 
-	g.p("package operations")
+	g.p("package ", pkgs.api)
 	g.p()
+	if pkgs.importPath != "" {
+		g.p("import \"", path.Join(pkgs.importPath, pkgs.models), "\"")
+		g.p()
+	}
 
-	for _, path := range paths {
-		operations := path.PathItemProps
+	for _, pname := range sortedPaths(paths) {
+		operations := paths[pname].PathItemProps
 		if post := operations.Post; post != nil {
+			g.pointer = pointerTo("paths", pname, "post")
 			g.generateOperation(post)
 		}
 		if get := operations.Get; get != nil {
+			g.pointer = pointerTo("paths", pname, "get")
 			g.generateOperation(get)
 		}
 		if put := operations.Put; put != nil {
+			g.pointer = pointerTo("paths", pname, "put")
 			g.generateOperation(put)
 		}
 		if del := operations.Delete; del != nil {
+			g.pointer = pointerTo("paths", pname, "delete")
 			g.generateOperation(del)
 		}
 	}
+	return g.diags.ErrorOrNil()
+}
+
+// baseTypes returns the names of the definitions with a discriminator
+func baseTypes(specDoc *spec.Document) map[string]bool {
+	bases := make(map[string]bool)
+	for name, def := range specDoc.Spec().Definitions {
+		if def.Discriminator != "" {
+			bases[name] = true
+		}
+	}
+	return bases
+}
+
+// the paths and groups are walked in order so a spec always renders the same code
+func sortedPaths(paths map[string]spec.PathItem) []string {
+	names := make([]string, 0, len(paths))
+	for name := range paths {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedGroups(groups map[string]string) []string {
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// checkOperations reports every construct of the operations that the handlers
+// can't be generated for, so they are all known before any file is written
+func checkOperations(specDoc *spec.Document) Diagnostics {
+	var diags Diagnostics
+	paths := specDoc.AllPaths()
+	for _, name := range sortedPaths(paths) {
+		item := paths[name]
+		ops := []struct {
+			method string
+			op     *spec.Operation
+		}{{"get", item.Get}, {"put", item.Put}, {"post", item.Post}, {"delete", item.Delete}}
+		for _, o := range ops {
+			if o.op != nil {
+				checkOperation(&diags, pointerTo("paths", name, o.method), o.op)
+			}
+		}
+	}
+	return diags
+}
+
+func checkOperation(diags *Diagnostics, ptr string, op *spec.Operation) {
+	if op.ID == "" {
+		diags.addf(ptr, "an operationId is required to name the handler")
+	}
+	if len(op.Tags) == 0 {
+		diags.addf(ptr, "a tag is required, the first tag selects the route group")
+	}
+	for i, param := range op.Parameters {
+		pp := ptr + "/parameters/" + strconv.Itoa(i)
+		if param.Ref.String() != "" {
+			diags.addf(pp, "parameter references are not supported")
+			continue
+		}
+		switch param.In {
+		case "body":
+			if param.Schema == nil || param.Schema.Ref.GetURL() == nil {
+				diags.addf(pp+"/schema", "the body schema must be a $ref to a definition")
+			}
+		case "query", "formData", "path":
+			if param.Type != "string" && !strings.HasPrefix(param.Format, "int") {
+				diags.addf(pp, "unsupported type %q with format %q, only strings and integers can be bound", param.Type, param.Format)
+			}
+		}
+	}
+	if op.Responses == nil {
+		diags.addf(ptr, "responses are required")
+	}
 }
 
 func (g *Generator) generateOperation(op *spec.Operation) {
@@ -358,7 +796,7 @@ func (g *Generator) generateOperation(op *spec.Operation) {
 	opParams := ""
 
 	for status, resp := range responses {
-		if status == 200 {
+		if status == 200 && resp.Schema != nil {
 			if refUrl := resp.Schema.SchemaProps.Ref.Ref.ReferenceURL; refUrl != nil {
 				modelResp = strings.TrimPrefix(refUrl.Fragment, "/definitions/")
 				break
@@ -404,12 +842,20 @@ func (g *Generator) generateOperation(op *spec.Operation) {
 	}
 
 	if hasBodyParam {
-		opParams += g.lowerFirst(model) + " *models." + model
+		if g.bases[model] {
+			opParams += g.lowerFirst(model) + " " + g.pkgs.models + "." + model
+		} else {
+			opParams += g.lowerFirst(model) + " *" + g.pkgs.models + "." + model
+		}
 	}
 
-	if modelResp != "" {
-		g.p("func ", g.caps(op.OperationProps.ID), "(", strings.TrimSuffix(opParams, ", "), ") (* models.", modelResp, ", error) {")
-		g.p("	return &models.", modelResp, "{}, nil")
+	if g.bases[modelResp] {
+		// a base type is an interface, the operation picks the model
+		g.p("func ", g.caps(op.OperationProps.ID), "(", strings.TrimSuffix(opParams, ", "), ") (", g.pkgs.models, ".", modelResp, ", error) {")
+		g.p("	return nil, nil")
+	} else if modelResp != "" {
+		g.p("func ", g.caps(op.OperationProps.ID), "(", strings.TrimSuffix(opParams, ", "), ") (*", g.pkgs.models, ".", modelResp, ", error) {")
+		g.p("	return &", g.pkgs.models, ".", modelResp, "{}, nil")
 	} else {
 		g.p("func ", g.caps(op.OperationProps.ID), "(", strings.TrimSuffix(opParams, ", "), ") (error) {")
 		g.p("	return nil")
@@ -448,65 +894,584 @@ func (g *Generator) p(str ...interface{}) {
 	g.WriteByte('\n')
 }
 
-func (g *Generator) generateStruct(def *GenDefinition) {
-	g.p("type ", def.GenSchema.Name, " struct {")
-	for _, prop := range def.Properties {
-		if g.hasExtendFormat(&prop) {
-			prop.resolvedType.GoType = "string"
-		} else if prop.resolvedType.SwaggerFormat == "date-time" {
-			prop.resolvedType.GoType = "time.Time"
-		}
+func (g *Generator) generateStruct(schema *GenSchema) {
+	g.p("type ", schema.Name, " struct {")
+	for _, member := range g.embedded(schema) {
+		g.p(member.GoType)
+	}
+	g.generateFields(schema.Properties)
+	if g.hasCatchAll(schema) {
+		g.p("AdditionalProperties map[string]", g.goType(schema.AdditionalProperties), " `json:\"-\"`")
+	} else if g.isClosed(schema) {
+		g.p("unknownProperties []string")
+	}
+	g.p("}")
+	g.p()
+}
+
+func (g *Generator) generateFields(props GenSchemaList) {
+	for _, prop := range props {
 		if prop.sharedValidations.Required {
-			g.p(g.caps(prop.Name), " ", prop.resolvedType.GoType, " `json:\"", prop.Name, "\" binding:\"required\"`")
+			g.p(g.caps(prop.Name), " ", g.goType(&prop), " `json:\"", prop.Name, "\" binding:\"required\"`")
+		} else if g.isOptionalStruct(&prop) {
+			g.p(g.caps(prop.Name), " *", g.goType(&prop), " `json:\"", prop.Name, ",omitempty\"`")
 		} else {
-			g.p(g.caps(prop.Name), " ", prop.resolvedType.GoType, " `json:\"", prop.Name, ",omitempty\"`")
+			g.p(g.caps(prop.Name), " ", g.goType(&prop), " `json:\"", prop.Name, ",omitempty\"`")
+		}
+	}
+}
+
+// isOptionalStruct tells whether a property holds a model rendered as a struct that may
+// be missing, it's a pointer so a missing model isn't taken for an empty one. The base
+// types and the maps are nil when they're missing already.
+func (g *Generator) isOptionalStruct(prop *GenSchema) bool {
+	return !prop.sharedValidations.Required && g.isNested(prop) && !prop.IsBaseType && !prop.resolvedType.IsMap
+}
+
+// goType is the Go type a schema is rendered with, the mapped types are used as is, the
+// strings of the formats govalidator checks stay strings and the maps and slices are
+// typed after what they hold
+func (g *Generator) goType(schema *GenSchema) string {
+	switch {
+	case schema.IsMapped:
+		return schema.GoType
+	case g.hasExtendFormat(schema):
+		return "string"
+	case schema.resolvedType.SwaggerFormat == "date-time":
+		return "time.Time"
+	case strings.HasPrefix(schema.GoType, "map[") && schema.AdditionalProperties != nil:
+		return "map[string]" + g.goType(schema.AdditionalProperties)
+	case strings.HasPrefix(schema.GoType, "[]") && schema.Items != nil:
+		return "[]" + g.goType(schema.Items)
+	}
+	return schema.GoType
+}
+
+// embedded returns the members of an allOf composition that are embedded in its struct,
+// the referenced models and the structs built for the inline members. A base type is
+// embedded as the struct holding its properties.
+func (g *Generator) embedded(schema *GenSchema) []GenSchema {
+	var members []GenSchema
+	for _, member := range schema.AllOf {
+		if member.IsComplexObject && !member.IsMap && member.GoType != "" {
+			if member.IsBaseType {
+				member.GoType += "Base"
+			}
+			members = append(members, member)
+		}
+	}
+	return members
+}
+
+// polymorphic returns the function reading a property that holds base types, or
+// nothing when JSON reads it as is
+func (g *Generator) polymorphic(prop *GenSchema) string {
+	switch {
+	case prop.IsBaseType:
+		return "Unmarshal" + prop.GoType
+	case prop.IsArray && prop.Items != nil && prop.Items.IsBaseType:
+		return "Unmarshal" + prop.Items.GoType + "Slice"
+	case prop.AdditionalProperties != nil && prop.AdditionalProperties.IsBaseType:
+		return "Unmarshal" + prop.AdditionalProperties.GoType + "Map"
+	}
+	return ""
+}
+
+func (g *Generator) hasPolymorphicProps(props GenSchemaList) bool {
+	for _, prop := range props {
+		if g.polymorphic(&prop) != "" {
+			return true
 		}
 	}
+	return false
+}
+
+// generatePropsUnmarshal reads the properties of a model from raw, the ones holding
+// base types are kept raw and read by the functions picking their models
+func (g *Generator) generatePropsUnmarshal(props GenSchemaList) {
+	g.p("var props struct {")
+	for _, prop := range props {
+		if g.polymorphic(&prop) != "" {
+			g.p(g.caps(prop.Name), " json.RawMessage `json:\"", prop.Name, "\"`")
+		} else {
+			g.generateFields(GenSchemaList{prop})
+		}
+	}
+	g.p("}")
+	g.p("if err := json.Unmarshal(raw, &props); err != nil {")
+	g.p("	return err")
+	g.p("}")
+	for _, prop := range props {
+		propName := g.caps(prop.Name)
+		fn := g.polymorphic(&prop)
+		if fn == "" {
+			g.p("m.", propName, " = props.", propName)
+			continue
+		}
+		g.p("if len(props.", propName, ") > 0 && string(props.", propName, ") != \"null\" {")
+		g.p("	v, err := ", fn, "(props.", propName, ")")
+		g.p("	if err != nil {")
+		g.p("		return errors.Nested(\"", prop.Name, "\", err)")
+		g.p("	}")
+		g.p("	m.", propName, " = v")
+		g.p("}")
+	}
+}
+
+// generateCompositionJSON reads and writes the members of a composition and its own
+// properties as one object. The methods of a member would otherwise be promoted and
+// marshal the member alone. A model extending a base type writes its discriminator.
+func (g *Generator) generateCompositionJSON(schema *GenSchema, members []GenSchema) {
+	g.p("// UnmarshalJSON reads every member of the composition from the same object")
+	g.p("func (m *", schema.Name, ") UnmarshalJSON(raw []byte) error {")
+	for _, member := range members {
+		g.p("if err := json.Unmarshal(raw, &m.", member.GoType, "); err != nil {")
+		g.p("	return err")
+		g.p("}")
+	}
+	if len(schema.Properties) > 0 {
+		g.generatePropsUnmarshal(schema.Properties)
+	}
+	if g.isClosed(schema) {
+		g.generateUnknownProperties(schema, members)
+	}
+	g.p("return nil")
+	g.p("}")
+	g.p()
+
+	parts := make([]string, 0, len(members)+2)
+	for _, member := range members {
+		if member.IsBaseType {
+			field := member.DiscriminatorField
+			parts = append([]string{"map[string]string{" + strconv.Quote(field) + ": m." + g.caps(field) + "()}"}, parts...)
+			g.p("// ", g.caps(field), " tells the value of the ", field, " discriminator for a ", schema.Name)
+			g.p("func (m ", schema.Name, ") ", g.caps(field), "() string {")
+			g.p("	return ", strconv.Quote(schema.DiscriminatorValue))
+			g.p("}")
+			g.p()
+		}
+		parts = append(parts, "m."+member.GoType)
+	}
+	g.p("// MarshalJSON writes the members of the composition as one object")
+	g.p("func (m ", schema.Name, ") MarshalJSON() ([]byte, error) {")
+	if len(schema.Properties) > 0 {
+		g.p("props := struct {")
+		g.generateFields(schema.Properties)
+		g.p("}{")
+		for _, prop := range schema.Properties {
+			g.p(g.caps(prop.Name), ": m.", g.caps(prop.Name), ",")
+		}
+		g.p("}")
+		parts = append(parts, "props")
+	}
+	g.p("var parts [][]byte")
+	g.p("for _, member := range []interface{}{", strings.Join(parts, ", "), "} {")
+	g.p("	part, err := json.Marshal(member)")
+	g.p("	if err != nil {")
+	g.p("		return nil, err")
+	g.p("	}")
+	g.p("	parts = append(parts, part)")
+	g.p("}")
+	g.p("return swag.ConcatJSON(parts...), nil")
 	g.p("}")
 	g.p()
 }
 
-func (g *Generator) generateValidator(def *GenDefinition) {
-	g.p("func (m *", def.Name, ") Validate() error {")
-	for _, prop := range def.Properties {
-		if prop.sharedValidations.HasValidations || g.hasExtendFormat(&prop) {
+// generateUnknownProperties keeps the properties none of the members of a composition
+// declares, the closed members forget the ones they saw as each reads the whole object
+func (g *Generator) generateUnknownProperties(schema *GenSchema, members []GenSchema) {
+	for _, member := range members {
+		if member.IsClosedMember {
+			g.p("m.", member.GoType, ".unknownProperties = nil")
+		}
+	}
+	g.p("var all map[string]json.RawMessage")
+	g.p("if err := json.Unmarshal(raw, &all); err != nil {")
+	g.p("	return err")
+	g.p("}")
+	declared := make(map[string]bool)
+	for _, name := range schema.AllOfProperties {
+		declared[name] = true
+	}
+	for _, prop := range schema.Properties {
+		declared[prop.Name] = true
+	}
+	names := make([]string, 0, len(declared))
+	for name := range declared {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		g.p("delete(all, ", strconv.Quote(name), ")")
+	}
+	g.p("m.unknownProperties = nil")
+	g.p("for k := range all {")
+	g.p("	m.unknownProperties = append(m.unknownProperties, k)")
+	g.p("}")
+	g.p("sort.Strings(m.unknownProperties)")
+}
+
+func (g *Generator) generateValidator(schema *GenSchema) {
+	g.p("func (m *", schema.Name, ") Validate() error {")
+	for _, member := range g.embedded(schema) {
+		g.p("if err := m.", member.GoType, ".Validate(); err != nil {")
+		g.p("	return err")
+		g.p("}")
+		g.p()
+	}
+	for _, prop := range schema.Properties {
+		if missing := g.missing(&prop); prop.sharedValidations.Required && missing != "" {
+			g.p("if ", missing, " {")
+			g.p("	return errors.Required(\"", prop.Name, "\", \"body\")")
+			g.p("}")
+			g.p()
+		}
+	}
+	for _, prop := range schema.Properties {
+		if g.hasPropValidator(&prop) {
 			g.p("if err := m.validate", g.caps(prop.Name), "(); err != nil {")
 			g.p("	return err")
 			g.p("}")
 			g.p()
 		}
 	}
+	if g.hasAdditionalPropertiesValidator(schema) {
+		g.p("if err := m.validateAdditionalProperties(); err != nil {")
+		g.p("	return err")
+		g.p("}")
+		g.p()
+	}
 	g.p("	return nil")
 	g.p("}")
 	g.p()
 }
 
-func (g *Generator) generatePropValidator(model string, prop *GenSchema) {
-	propName := g.caps(prop.Name)
+// missing returns the condition telling a property of the model wasn't sent, its zero value.
+// It's empty when the zero value can't be told from a value that was sent: a false bool,
+// a nested struct, which validates its own required properties, and the mapped types.
+// The binding tags don't reach the models held by slices and maps, so the validator
+// checks the required properties itself.
+func (g *Generator) missing(prop *GenSchema) string {
+	field := "m." + g.caps(prop.Name)
+	goType := g.goType(prop)
+	switch {
+	case prop.IsMapped:
+		return ""
+	case goType == "string":
+		return field + " == \"\""
+	case goType == "time.Time":
+		return field + ".IsZero()"
+	case prop.IsBaseType, goType == "interface{}",
+		strings.HasPrefix(goType, "[]"), strings.HasPrefix(goType, "map["):
+		return field + " == nil"
+	case strings.HasPrefix(goType, "int"), strings.HasPrefix(goType, "uint"), strings.HasPrefix(goType, "float"):
+		return field + " == 0"
+	}
+	return ""
+}
 
-	if prop.sharedValidations.Enum != nil {
-		varEnum := g.lowerFirst(model) + propName + "Enum"
-		jsonEnum, _ := json.Marshal(prop.sharedValidations.Enum)
-		g.p("var ", varEnum, " []interface{}")
-		g.p()
-		g.p("func (m *", model, ") validate", propName, "Enum(path, location string, value ", prop.resolvedType.GoType, ") error {")
-		g.p("	if ", varEnum, " == nil {")
-		g.p("		var res []", prop.resolvedType.GoType)
-		g.p("		if err := json.Unmarshal([]byte(`", string(jsonEnum), "`), &res); err != nil {")
-		g.p("			return err")
-		g.p("		}")
-		g.p("		for _, v := range res {")
-		g.p("			", varEnum, " = append(", varEnum, ", v)")
-		g.p("		}")
-		g.p("	}")
-		g.p("	if err:= validate.Enum(path, location, value, ", varEnum, "); err != nil {")
-		g.p("		return err")
-		g.p("	}")
-		g.p()
+// hasPropValidator tells whether a property gets a validate method
+func (g *Generator) hasPropValidator(prop *GenSchema) bool {
+	return !prop.IsMapped && (prop.sharedValidations.HasValidations || g.needsValidation(prop))
+}
+
+// needsValidation tells whether a value has constraints to check, itself or the values
+// it holds. The values of a mapped type are left to the type.
+func (g *Generator) needsValidation(schema *GenSchema) bool {
+	v := schema.sharedValidations
+	switch {
+	case schema.IsMapped:
+		return false
+	case v.MaxLength != nil || v.MinLength != nil || v.Pattern != "" || v.Enum != nil:
+		return true
+	case v.MultipleOf != nil || v.Minimum != nil || v.Maximum != nil:
+		return true
+	case g.hasExtendFormat(schema) || g.isNested(schema):
+		return true
+	case g.isMap(schema):
+		return v.MinProperties != nil || v.MaxProperties != nil ||
+			(schema.AdditionalProperties != nil && g.needsValidation(schema.AdditionalProperties))
+	case g.isSlice(schema):
+		return v.MinItems != nil || v.MaxItems != nil || v.UniqueItems || g.needsValidation(schema.Items)
+	}
+	return false
+}
+
+// isMap tells whether a value is rendered as a map
+func (g *Generator) isMap(schema *GenSchema) bool {
+	return strings.HasPrefix(g.goType(schema), "map[")
+}
+
+// isSlice tells whether a value is rendered as a slice of its items
+func (g *Generator) isSlice(schema *GenSchema) bool {
+	return strings.HasPrefix(g.goType(schema), "[]") && schema.Items != nil
+}
+
+// hasAdditionalPropertiesValidator tells whether a struct validates the properties it
+// doesn't declare
+func (g *Generator) hasAdditionalPropertiesValidator(schema *GenSchema) bool {
+	return g.isClosed(schema) || (g.hasCatchAll(schema) && g.needsValidation(schema.AdditionalProperties))
+}
+
+// generateAdditionalPropertiesValidator rejects the properties a closed struct doesn't
+// declare, or validates the additional properties of the others
+func (g *Generator) generateAdditionalPropertiesValidator(schema *GenSchema) {
+	if g.isClosed(schema) {
+		g.p("func (m *", schema.Name, ") validateAdditionalProperties() error {")
+		g.p("if len(m.unknownProperties) == 0 {")
 		g.p("	return nil")
 		g.p("}")
+		g.p("errs := make([]error, 0, len(m.unknownProperties))")
+		g.p("for _, k := range m.unknownProperties {")
+		g.p("	errs = append(errs, errors.PropertyNotAllowed(\"\", \"body\", k))")
+		g.p("}")
+		g.p("return errors.CompositeValidationError(errs...)")
+		g.p("}")
 		g.p()
+		return
+	}
+
+	// the additional properties are validated like the values of a map property, they're
+	// named after their keys and the parent nests them under the property of the struct
+	// the way it nests the keys of a map under the name of the map
+	catchAll := GenSchema{AdditionalProperties: schema.AdditionalProperties}
+	catchAll.GoType = "map[string]" + g.goType(schema.AdditionalProperties)
+	g.generateEnums(schema.Name, "AdditionalProperties", &catchAll)
+	g.p("func (m *", schema.Name, ") validateAdditionalProperties() error {")
+	g.generateMapValidation(schema.Name, "AdditionalProperties", &catchAll, "m.AdditionalProperties", `""`, true)
+	g.p()
+	g.p("	return nil")
+	g.p("}")
+	g.p()
+}
+
+// isNested tells whether a value is a model validating itself, a definition it references
+// or one of the structs built for the inline objects
+func (g *Generator) isNested(schema *GenSchema) bool {
+	t := schema.resolvedType
+	if t.GoType == "" || strings.HasPrefix(t.GoType, "[]") || strings.HasPrefix(t.GoType, "map[") {
+		return false
+	}
+	return g.nested[t.GoType] || (!t.IsAnonymous && (t.IsComplexObject || t.IsMap || t.IsBaseType))
+}
+
+// generateNestedValidation validates the nested model of a property, its failures
+// are named after the path of the property
+func (g *Generator) generateNestedValidation(prop *GenSchema) {
+	g.generateModelValidation(prop, "m."+g.caps(prop.Name), strconv.Quote(prop.Name))
+	g.p()
+}
+
+// generateModelValidation validates a nested model, a base type is an interface that
+// is only validated when it holds a model
+func (g *Generator) generateModelValidation(schema *GenSchema, value, path string) {
+	if schema.IsBaseType {
+		g.p("if ", value, " != nil {")
+	}
+	g.p("if err := ", value, ".Validate(); err != nil {")
+	g.p("	return errors.Nested(", path, ", err)")
+	g.p("}")
+	if schema.IsBaseType {
+		g.p("}")
 	}
+}
+
+// generateEnums renders the helpers checking the enums of a value and of the values it
+// holds, they're named after the value, validateStatusEnum for the status of a model
+// and validateLabelsValueEnum for the values of its labels
+func (g *Generator) generateEnums(model, name string, schema *GenSchema) {
+	if schema.IsMapped {
+		return
+	}
+	if schema.sharedValidations.Enum != nil {
+		g.generateEnum(model, name, schema)
+	}
+	if g.isMap(schema) && schema.AdditionalProperties != nil {
+		g.generateEnums(model, name+"Value", schema.AdditionalProperties)
+	}
+	if g.isSlice(schema) {
+		g.generateEnums(model, name+"Items", schema.Items)
+	}
+}
+
+func (g *Generator) generateEnum(model, name string, schema *GenSchema) {
+	varEnum := g.lowerFirst(model) + name + "Enum"
+	goType := g.goType(schema)
+	jsonEnum, _ := json.Marshal(schema.sharedValidations.Enum)
+	g.p("var ", varEnum, " []interface{}")
+	g.p()
+	g.p("func (m *", model, ") validate", name, "Enum(path, location string, value ", goType, ") error {")
+	g.p("	if ", varEnum, " == nil {")
+	g.p("		var res []", goType)
+	g.p("		if err := json.Unmarshal([]byte(`", string(jsonEnum), "`), &res); err != nil {")
+	g.p("			return err")
+	g.p("		}")
+	g.p("		for _, v := range res {")
+	g.p("			", varEnum, " = append(", varEnum, ", v)")
+	g.p("		}")
+	g.p("	}")
+	g.p("	if err:= validate.Enum(path, location, value, ", varEnum, "); err != nil {")
+	g.p("		return err")
+	g.p("	}")
+	g.p()
+	g.p("	return nil")
+	g.p("}")
+	g.p()
+}
+
+// joinPath appends a key to the path of a value, both are Go expressions
+func joinPath(path, key string) string {
+	if path == `""` {
+		return key
+	}
+	if strings.HasSuffix(path, `"`) {
+		return path[:len(path)-1] + `."+` + key
+	}
+	return path + `+"."+` + key
+}
+
+// generateValueValidation validates a value held by a property against its schema, the
+// failures are named after path. Both value and path are Go expressions and name is
+// the one the enum helpers of the value are rendered with.
+func (g *Generator) generateValueValidation(model, name string, schema *GenSchema, value, path string) {
+	v := schema.sharedValidations
+	str := value
+	if g.goType(schema) != "string" {
+		str = "string(" + value + ")"
+	}
+	if v.MaxLength != nil {
+		g.p("if err := validate.MaxLength(", path, ", \"body\", ", str, ", ", v.MaxLength, "); err != nil {")
+		g.p("	return err")
+		g.p("}")
+	}
+	if v.MinLength != nil {
+		g.p("if err := validate.MinLength(", path, ", \"body\", ", str, ", ", v.MinLength, "); err != nil {")
+		g.p("	return err")
+		g.p("}")
+	}
+	if v.Pattern != "" {
+		g.p("if err := validate.Pattern(", path, ", \"body\", ", str, ", `", v.Pattern, "`); err != nil {")
+		g.p("	return err")
+		g.p("}")
+	}
+	if v.MultipleOf != nil {
+		g.p("if err := validate.MultipleOf(", path, ", \"body\", float64(", value, "), ", v.MultipleOf, "); err != nil {")
+		g.p("	return err")
+		g.p("}")
+	}
+	if v.Minimum != nil {
+		g.p("if err := validate.Minimum(", path, ", \"body\", float64(", value, "), ", v.Minimum, ", ", v.ExclusiveMinimum, "); err != nil {")
+		g.p("	return err")
+		g.p("}")
+	}
+	if v.Maximum != nil {
+		g.p("if err := validate.Maximum(", path, ", \"body\", float64(", value, "), ", v.Maximum, ", ", v.ExclusiveMaximum, "); err != nil {")
+		g.p("	return err")
+		g.p("}")
+	}
+	if v.Enum != nil {
+		g.p("if err := m.validate", name, "Enum(", path, ", \"body\", ", value, "); err != nil {")
+		g.p("	return err")
+		g.p("}")
+	}
+	if g.hasExtendFormat(schema) {
+		validatefunc, _ := govalidator.TagMap[schema.resolvedType.SwaggerFormat]
+		funcName := runtime.FuncForPC(reflect.ValueOf(validatefunc).Pointer()).Name()
+		g.p("if !", funcName[22:], "(", value, ") {")
+		g.p("	return errors.InvalidType(", path, ", \"body\", \"", schema.resolvedType.SwaggerFormat, "\", ", value, ")")
+		g.p("}")
+	}
+	if g.isNested(schema) {
+		g.generateModelValidation(schema, value, path)
+	}
+	if g.isMap(schema) {
+		g.generateMapValidation(model, name, schema, value, path, true)
+	}
+	if g.isSlice(schema) {
+		g.generateSliceValidation(model, name, schema, value, path, true)
+	}
+}
+
+// generateSliceValidation validates the number of items of a slice, their uniqueness
+// and every item, the items are named after their index. An optional slice that's
+// missing isn't checked.
+func (g *Generator) generateSliceValidation(model, name string, schema *GenSchema, value, path string, required bool) {
+	v := schema.sharedValidations
+	size := "int64(len(" + value + "))"
+	if v.MinItems != nil {
+		if !required {
+			g.p("if ", value, " != nil {")
+		}
+		g.p("if err := validate.MinItems(", path, ", \"body\", ", size, ", ", v.MinItems, "); err != nil {")
+		g.p("	return err")
+		g.p("}")
+		if !required {
+			g.p("}")
+		}
+	}
+	if v.MaxItems != nil {
+		g.p("if err := validate.MaxItems(", path, ", \"body\", ", size, ", ", v.MaxItems, "); err != nil {")
+		g.p("	return err")
+		g.p("}")
+	}
+	if v.UniqueItems {
+		g.p("if err := validate.UniqueItems(", path, ", \"body\", ", value, "); err != nil {")
+		g.p("	return err")
+		g.p("}")
+	}
+
+	if !g.needsValidation(schema.Items) {
+		return
+	}
+	index := schema.IndexVar
+	if index == "" {
+		index = "i"
+	}
+	g.p("for ", index, " := range ", value, " {")
+	g.generateValueValidation(model, name+"Items", schema.Items, value+"["+index+"]", joinPath(path, "strconv.Itoa("+index+")"))
+	g.p("}")
+}
+
+// generateMapValidation validates the number of properties of a map and every value in
+// it, the values are named after their key. An optional map that's missing isn't checked.
+func (g *Generator) generateMapValidation(model, name string, schema *GenSchema, value, path string, required bool) {
+	v := schema.sharedValidations
+	size := "int64(len(" + value + "))"
+	if v.MinProperties != nil {
+		if !required {
+			g.p("if ", value, " != nil {")
+		}
+		g.p("if err := validate.MinProperties(", path, ", \"body\", ", size, ", ", v.MinProperties, "); err != nil {")
+		g.p("	return err")
+		g.p("}")
+		if !required {
+			g.p("}")
+		}
+	}
+	if v.MaxProperties != nil {
+		g.p("if err := validate.MaxProperties(", path, ", \"body\", ", size, ", ", v.MaxProperties, "); err != nil {")
+		g.p("	return err")
+		g.p("}")
+	}
+
+	addl := schema.AdditionalProperties
+	if addl == nil || !g.needsValidation(addl) {
+		return
+	}
+	key := addl.KeyVar
+	if key == "" {
+		key = "k"
+	}
+	val := strings.Repeat("v", len(key))
+	g.p("for ", key, ", ", val, " := range ", value, " {")
+	g.generateValueValidation(model, name+"Value", addl, val, joinPath(path, key))
+	g.p("}")
+}
+
+func (g *Generator) generatePropValidator(model string, prop *GenSchema) {
+	propName := g.caps(prop.Name)
+
+	g.generateEnums(model, propName, prop)
 
 	g.p("func (m *", model, ") validate", propName, "() error {")
 
@@ -521,6 +1486,12 @@ func (g *Generator) generatePropValidator(model string, prop *GenSchema) {
 			g.p("	return nil")
 			g.p("}")
 			g.p()
+		} else if g.isNested(prop) && !prop.IsBaseType {
+			// an optional model is nil when it's missing, an empty one is still validated
+			g.p("if m.", propName, " == nil {")
+			g.p("	return nil")
+			g.p("}")
+			g.p()
 		}
 	}
 	if prop.sharedValidations.MaxLength != nil {
@@ -587,6 +1558,17 @@ func (g *Generator) generatePropValidator(model string, prop *GenSchema) {
 		g.p("}")
 		g.p()
 	}
+	if g.isNested(prop) {
+		g.generateNestedValidation(prop)
+	}
+	if g.isMap(prop) && g.needsValidation(prop) {
+		g.generateMapValidation(model, propName, prop, "m."+propName, strconv.Quote(prop.Name), prop.sharedValidations.Required)
+		g.p()
+	}
+	if g.isSlice(prop) && g.needsValidation(prop) {
+		g.generateSliceValidation(model, propName, prop, "m."+propName, strconv.Quote(prop.Name), prop.sharedValidations.Required)
+		g.p()
+	}
 	g.p("	return nil")
 	g.p("}")
 	g.p()
@@ -0,0 +1,282 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aiyi/swagger-gin/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+func tempGenDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "generator")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return dir
+}
+
+func writeSpecFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+// genOpts writes the spec to a temporary directory, the files are generated into a file set
+func genOpts(t *testing.T, dir, doc string) GenOpts {
+	writeSpecFiles(t, dir, map[string]string{"swagger.yml": doc})
+	return GenOpts{
+		Spec:       filepath.Join(dir, "swagger.yml"),
+		Target:     filepath.Join(dir, "gen"),
+		ImportPath: "example.com/gen",
+		Files:      NewFileSet(),
+	}
+}
+
+// generatedFile returns the content of a file generated into the target of the options
+func generatedFile(t *testing.T, opts GenOpts, name string) string {
+	content, ok := opts.Files.Content(filepath.Join(opts.Target, filepath.FromSlash(name)))
+	if !assert.True(t, ok, "%s was not generated, got %v", name, opts.Files.Paths()) {
+		t.FailNow()
+	}
+	return string(content)
+}
+
+// expected is what a generated file holds: the strings it contains, the regular
+// expressions it matches and the strings it doesn't contain
+type expected struct {
+	contains, matches, absent []string
+}
+
+// checkGenerated checks the files generated into the target of the options, by name
+func checkGenerated(t *testing.T, opts GenOpts, files map[string]expected) {
+	for name, exp := range files {
+		content := generatedFile(t, opts, name)
+		for _, s := range exp.contains {
+			assert.Contains(t, content, s, name)
+		}
+		for _, re := range exp.matches {
+			assert.Regexp(t, re, content, name)
+		}
+		for _, s := range exp.absent {
+			assert.NotContains(t, content, s, name)
+		}
+	}
+}
+
+const unboundPets = `
+swagger: "2.0"
+info: {title: restapi, version: "1.0"}
+paths:
+  /pets:
+    get:
+      parameters:
+        - {name: limit, in: query, type: boolean}
+        - $ref: "#/parameters/offset"
+      responses:
+        200: {description: the pets}
+    post:
+      operationId: addPet
+      tags: [pets]
+      parameters:
+        - name: body
+          in: body
+          schema: {type: object, properties: {name: {type: string}}}
+      responses:
+        default: {description: added}
+parameters:
+  offset: {name: offset, in: query, type: integer, format: int32}
+`
+
+func TestGenerateServerOperation_Diagnostics(t *testing.T) {
+	dir := tempGenDir(t)
+	defer os.RemoveAll(dir)
+	opts := genOpts(t, dir, unboundPets)
+
+	err := GenerateServerOperation(true, true, opts)
+	diags, ok := err.(Diagnostics)
+	if !assert.True(t, ok, "expected diagnostics, got %v", err) {
+		return
+	}
+	expected := []Diagnostic{
+		{"/paths/~1pets/get", "an operationId is required to name the handler"},
+		{"/paths/~1pets/get", "a tag is required, the first tag selects the route group"},
+		{"/paths/~1pets/get/parameters/0", `unsupported type "boolean" with format "", only strings and integers can be bound`},
+		{"/paths/~1pets/get/parameters/1", "parameter references are not supported"},
+		{"/paths/~1pets/post/parameters/0/schema", "the body schema must be a $ref to a definition"},
+	}
+	if assert.Len(t, diags, len(expected)) {
+		for i, diag := range diags {
+			assert.Equal(t, expected[i], *diag)
+		}
+	}
+	assert.Contains(t, err.Error(), "5 problem(s) found in the spec:\n  /paths/~1pets/get: an operationId")
+	// nothing is generated when the spec has problems
+	assert.Empty(t, opts.Files.Paths())
+}
+
+func TestGenerateDefinition_Diagnostics(t *testing.T) {
+	dir := tempGenDir(t)
+	defer os.RemoveAll(dir)
+	opts := genOpts(t, dir, `
+swagger: "2.0"
+info: {title: restapi, version: "1.0"}
+paths: {}
+definitions:
+  Pet:
+    properties:
+      name: {type: string}
+  Tag:
+    properties:
+      label: {type: label}
+  Toy:
+    properties:
+      size: {type: integer, format: int32}
+      shape: {type: shape}
+`)
+
+	err := GenerateDefinition(true, true, opts)
+	diags, ok := err.(Diagnostics)
+	if !assert.True(t, ok, "expected diagnostics, got %v", err) {
+		return
+	}
+	if assert.Len(t, diags, 2) {
+		assert.Equal(t, "/definitions/Tag/properties/label", diags[0].Pointer)
+		assert.Contains(t, diags[0].Message, "unresolvable")
+		assert.Equal(t, "/definitions/Toy/properties/shape", diags[1].Pointer)
+	}
+	// the definitions without problems are still generated
+	assert.Contains(t, generatedFile(t, opts, "models/pet.go"), "type Pet struct")
+}
+
+func TestDiagnostics_ErrorOrNil(t *testing.T) {
+	var diags Diagnostics
+	assert.NoError(t, diags.ErrorOrNil())
+
+	diags.add("/definitions/Pet", nil)
+	assert.NoError(t, diags.ErrorOrNil())
+
+	diags.add("/definitions/Pet", assert.AnError)
+	diags.add("/definitions/Tag", &Diagnostic{Pointer: "/definitions/Tag/properties/label", Message: "unresolvable"})
+	diags.add("", Diagnostics{{Message: "no pointer"}})
+	diags.sort()
+	if assert.Len(t, diags, 3) {
+		assert.Equal(t, "no pointer", diags[0].Error())
+		assert.Equal(t, "/definitions/Pet: "+assert.AnError.Error(), diags[1].Error())
+		assert.Equal(t, "/definitions/Tag/properties/label", diags[2].Pointer)
+	}
+	assert.Equal(t, "/a~1b/~0c", pointerTo("a/b", "~c"))
+}
+
+func TestGenerator_FailPointer(t *testing.T) {
+	doc, err := spec.New(json.RawMessage(`{
+  "swagger": "2.0", "info": {"title": "restapi", "version": "1.0"}, "paths": {},
+  "definitions": {"Pet": {"properties": {"owner": {"properties": {"name": {"type": "string"}}}}}}
+}`), "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	def, err := makeGenDefinition("Pet", "models", doc.Spec().Definitions["Pet"], doc, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "/definitions/Pet", def.Pointer)
+	if assert.Len(t, def.Properties, 1) {
+		assert.Equal(t, "/definitions/Pet/properties/owner", def.Properties[0].Pointer)
+	}
+
+	// the printer failures are reported at the node being rendered
+	var buf bytes.Buffer
+	g := NewGenerator()
+	g.reset(&buf)
+	g.generateSchema(&def.GenSchema)
+	g.p(struct{}{})
+	if assert.Len(t, g.diags, 1) {
+		assert.Equal(t, "/definitions/Pet", g.diags[0].Pointer)
+		assert.Equal(t, "unknown type in printer: struct {}", g.diags[0].Message)
+	}
+}
+
+func TestGenerateServerOperation_Stable(t *testing.T) {
+	dir := tempGenDir(t)
+	defer os.RemoveAll(dir)
+	opts := genOpts(t, dir, taggedPets)
+
+	// watch mode compares each run with the files on disk, a run must render
+	// the same spec the same way or every poll would rewrite the handlers
+	var previous map[string]string
+	for i := 0; i < 5; i++ {
+		opts.Files = NewFileSet()
+		if !assert.NoError(t, GenerateServerOperation(true, true, opts)) {
+			return
+		}
+		current := make(map[string]string)
+		for _, path := range opts.Files.Paths() {
+			content, _ := opts.Files.Content(path)
+			current[path] = string(content)
+		}
+		if previous != nil {
+			assert.Equal(t, previous, current)
+		}
+		previous = current
+	}
+
+	routes := generatedFile(t, opts, "restapi.go")
+	assert.Regexp(t, `Pets\s+\*gin.RouterGroup\s+Store \*gin.RouterGroup`, routes)
+	assert.Regexp(t, `(?s)Pets.POST\("", AddPetHandler\)\s+Pets.GET\("", ListPetsHandler\)\s+Store.GET\("/orders", ListOrdersHandler\)`, routes)
+}
+
+func TestGenerate_MultiFile(t *testing.T) {
+	dir := tempGenDir(t)
+	defer os.RemoveAll(dir)
+	writeSpecFiles(t, dir, map[string]string{
+		"paths/pets.yml": `post:
+  operationId: addPet
+  tags: [pets]
+  parameters:
+    - {name: body, in: body, schema: {$ref: ../definitions/pet.json}}
+  responses:
+    201: {description: added, schema: {$ref: "../definitions/pet.json#/definitions/Receipt"}}
+`,
+		"definitions/pet.json": `{"type": "object", "required": ["name"], "properties": {
+  "name": {"type": "string"},
+  "owner": {"$ref": "owner.json"},
+  "toys": {"type": "array", "items": {"$ref": "toy.json"}}
+}, "definitions": {"Receipt": {"type": "object", "properties": {"id": {"type": "string"}}}}}`,
+		"definitions/owner.json": `{"type": "object", "properties": {"name": {"type": "string", "minLength": 1}}}`,
+		"definitions/toy.json":   `{"type": "object", "properties": {"owner": {"$ref": "owner.json"}}}`,
+	})
+	opts := genOpts(t, dir, `
+swagger: "2.0"
+info: {title: restapi, version: "1.0"}
+paths:
+  /pets:
+    $ref: paths/pets.yml
+definitions:
+  Pet:
+    $ref: definitions/pet.json
+`)
+
+	if !assert.NoError(t, GenerateDefinition(true, true, opts)) {
+		return
+	}
+	if !assert.NoError(t, GenerateServerOperation(true, true, opts)) {
+		return
+	}
+
+	checkGenerated(t, opts, map[string]expected{
+		// the schemas referenced from the properties get models of their own
+		"models/pet.go":     {matches: []string{`Owner\s+\*Owner\s+` + "`json:\"owner,omitempty\"`", `Toys\s+\[\]Toy\s+`}},
+		"models/owner.go":   {contains: []string{"type Owner struct"}},
+		"models/toy.go":     {matches: []string{`Owner\s+\*Owner\s+`}},
+		"models/receipt.go": {contains: []string{"type Receipt struct"}},
+		// the body references the definition the document was loaded into
+		"restapi.go": {contains: []string{"var body models.Pet"}},
+	})
+}
@@ -15,11 +15,13 @@ import (
 )
 
 // GenerateDefinition generates a model file for a schema defintion.
+// All the definitions are processed, the problems found in any of them
+// are returned together as Diagnostics.
 func GenerateDefinition(includeModel, includeValidator bool, opts GenOpts) error {
 	var modelNames []string
 
 	// Load the spec
-	specPath, specDoc, err := loadSpec(opts.Spec)
+	_, specDoc, err := loadFilteredSpec(opts)
 	if err != nil {
 		return err
 	}
@@ -27,31 +29,42 @@ func GenerateDefinition(includeModel, includeValidator bool, opts GenOpts) error
 	for k := range specDoc.Spec().Definitions {
 		modelNames = append(modelNames, k)
 	}
+	sort.Strings(modelNames)
 
+	// the subtypes are found before the inline objects get added to the definitions
+	subtypes := discriminatedSubtypes(specDoc.Spec())
+	files, flush := opts.fileSet()
+	var diags Diagnostics
 	for _, modelName := range modelNames {
 		// lookup schema
-		model, ok := specDoc.Spec().Definitions[modelName]
-		if !ok {
-			return fmt.Errorf("model %q not found in definitions in %s", modelName, specPath)
-		}
+		model := specDoc.Spec().Definitions[modelName]
 
 		// generate files
 		generator := definitionGenerator{
 			Name:             modelName,
 			Model:            model,
 			SpecDoc:          specDoc,
-			Target:           filepath.Join(opts.Target, opts.ModelPackage),
+			Target:           filepath.Join(opts.Target, opts.packages().models),
 			IncludeModel:     includeModel,
 			IncludeValidator: includeValidator,
 			DumpData:         opts.DumpData,
+			Files:            files,
+			Subtypes:         subtypes[modelName],
+			TypeMapping:      opts.TypeMapping,
+			Imports:          opts.Imports,
 		}
 
 		if err := generator.Generate(); err != nil {
-			return err
+			diags.add(pointerTo("definitions", modelName), err)
 		}
 	}
 
-	return nil
+	if flush {
+		if _, err := files.Write(); err != nil {
+			diags.add("", err)
+		}
+	}
+	return diags.ErrorOrNil()
 }
 
 type definitionGenerator struct {
@@ -63,10 +76,14 @@ type definitionGenerator struct {
 	IncludeValidator bool
 	Data             interface{}
 	DumpData         bool
+	Files            *FileSet
+	Subtypes         []GenSubtype
+	TypeMapping      map[string]string
+	Imports          map[string]string
 }
 
 func (m *definitionGenerator) Generate() error {
-	mod, err := makeGenDefinition(m.Name, m.Target, m.Model, m.SpecDoc)
+	mod, err := makeGenDefinition(m.Name, m.Target, m.Model, m.SpecDoc, m.TypeMapping)
 	if err != nil {
 		return err
 	}
@@ -77,11 +94,13 @@ func (m *definitionGenerator) Generate() error {
 	}
 
 	mod.IncludeValidator = m.IncludeValidator
+	mod.Subtypes = m.Subtypes
+	mod.Imports = m.Imports
 	m.Data = mod
 
 	if m.IncludeModel {
 		if err := m.generateModel(); err != nil {
-			return fmt.Errorf("model: %s", err)
+			return err
 		}
 	}
 	log.Println("generated model", m.Name)
@@ -92,21 +111,25 @@ func (m *definitionGenerator) Generate() error {
 func (m *definitionGenerator) generateModel() error {
 	buf := bytes.NewBuffer(nil)
 
-	codeGen.generateModel(buf, m.Data.(*GenDefinition))
+	if err := codeGen.generateModel(buf, m.Data.(*GenDefinition)); err != nil {
+		return err
+	}
 	//log.Println("rendered model template:", m.Name)
 
-	return writeToFile(m.Target, m.Name, buf.Bytes())
+	return m.Files.AddGoFile(m.Target, m.Name, buf.Bytes())
 }
 
-func makeGenDefinition(name, pkg string, schema spec.Schema, specDoc *spec.Document) (*GenDefinition, error) {
+func makeGenDefinition(name, pkg string, schema spec.Schema, specDoc *spec.Document, typeMapping map[string]string) (*GenDefinition, error) {
 	receiver := "m"
 	resolver := &typeResolver{
 		ModelsPackage: "",
 		ModelName:     name,
 		Doc:           specDoc,
+		TypeMapping:   typeMapping,
 	}
 	pg := schemaGenContext{
 		Path:         "",
+		Pointer:      pointerTo("definitions", name),
 		Name:         name,
 		Receiver:     receiver,
 		IndexVar:     "i",
@@ -153,6 +176,46 @@ type GenDefinition struct {
 	ExtraSchemas     []GenSchema
 	DependsOn        []string
 	IncludeValidator bool
+	Subtypes         []GenSubtype
+}
+
+// GenSubtype is a model extending a base type, the discriminator of the base type
+// tells it apart by its value
+type GenSubtype struct {
+	Name  string
+	Value string
+}
+
+// discriminatedSubtypes maps the definitions with a discriminator to the definitions
+// extending them, the ones referencing them in their allOf
+func discriminatedSubtypes(sw *spec.Swagger) map[string][]GenSubtype {
+	names := make([]string, 0, len(sw.Definitions))
+	for name := range sw.Definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	subtypes := make(map[string][]GenSubtype)
+	for _, name := range names {
+		def := sw.Definitions[name]
+		for i := range def.AllOf {
+			base, ok := localName(&def.AllOf[i].Ref, "definitions")
+			if !ok || sw.Definitions[base].Discriminator == "" {
+				continue
+			}
+			subtypes[base] = append(subtypes[base], GenSubtype{Name: name, Value: discriminatorValue(name, def)})
+		}
+	}
+	return subtypes
+}
+
+// discriminatorValue is the value of the discriminator telling a model apart, its
+// name unless x-discriminator-value sets another one
+func discriminatorValue(name string, schema spec.Schema) string {
+	if value, ok := schema.Extensions.GetString("x-discriminator-value"); ok {
+		return value
+	}
+	return name
 }
 
 // GenSchemaList is a list of schemas for generation.
@@ -167,6 +230,7 @@ func (g GenSchemaList) Less(i, j int) bool { return g[i].Name < g[j].Name }
 
 type schemaGenContext struct {
 	Path               string
+	Pointer            string // locates the schema in the spec for diagnostics
 	Name               string
 	ParamName          string
 	Accessor           string
@@ -195,6 +259,7 @@ func (sg *schemaGenContext) NewSliceBranch(schema *spec.Schema) *schemaGenContex
 	} else {
 		pg.Path = pg.Path + "+ \".\" + strconv.Itoa(" + indexVar + ")"
 	}
+	pg.Pointer = sg.Pointer + "/items"
 	pg.IndexVar = indexVar + "i"
 	pg.ValueExpr = pg.ValueExpr + "[" + indexVar + "]"
 	pg.Schema = *schema
@@ -221,6 +286,7 @@ func (sg *schemaGenContext) NewAdditionalItems(schema *spec.Schema) *schemaGenCo
 	} else {
 		pg.Path = pg.Path + "+ \".\" + strconv.Itoa(" + indexVar + mod + ")"
 	}
+	pg.Pointer = sg.Pointer + "/additionalItems"
 	pg.IndexVar = indexVar
 	pg.ValueExpr = sg.ValueExpr + "." + swag.ToGoName(sg.Name) + "Items[" + indexVar + "]"
 	pg.Schema = spec.Schema{}
@@ -238,6 +304,7 @@ func (sg *schemaGenContext) NewTupleElement(schema *spec.Schema, index int) *sch
 	} else {
 		pg.Path = pg.Path + "+ \".\"+\"" + strconv.Itoa(index) + "\""
 	}
+	pg.Pointer = sg.Pointer + "/items/" + strconv.Itoa(index)
 	pg.ValueExpr = pg.ValueExpr + ".P" + strconv.Itoa(index)
 	pg.Required = true
 	pg.Schema = *schema
@@ -251,6 +318,7 @@ func (sg *schemaGenContext) NewStructBranch(name string, schema spec.Schema) *sc
 	} else {
 		pg.Path = pg.Path + "+\".\"+" + fmt.Sprintf("%q", name)
 	}
+	pg.Pointer = sg.Pointer + pointerTo("properties", name)
 	pg.Name = name
 	pg.ValueExpr = pg.ValueExpr + "." + swag.ToGoName(name)
 	pg.Schema = schema
@@ -276,6 +344,7 @@ func (sg *schemaGenContext) shallowClone() *schemaGenContext {
 func (sg *schemaGenContext) NewCompositionBranch(schema spec.Schema, index int) *schemaGenContext {
 	pg := sg.shallowClone()
 	pg.Schema = schema
+	pg.Pointer = sg.Pointer + "/allOf/" + strconv.Itoa(index)
 	pg.Name = "AO" + strconv.Itoa(index)
 	if sg.Name != sg.TypeResolver.ModelName {
 		pg.Name = sg.Name + pg.Name
@@ -287,6 +356,7 @@ func (sg *schemaGenContext) NewCompositionBranch(schema spec.Schema, index int)
 func (sg *schemaGenContext) NewAdditionalProperty(schema spec.Schema) *schemaGenContext {
 	pg := sg.shallowClone()
 	pg.Schema = schema
+	pg.Pointer = sg.Pointer + "/additionalProperties"
 	if pg.KeyVar == "" {
 		pg.ValueExpr = sg.ValueExpr
 	}
@@ -310,7 +380,8 @@ func (sg *schemaGenContext) schemaValidations() sharedValidations {
 	hasNumberValidation := model.Maximum != nil || model.Minimum != nil || model.MultipleOf != nil
 	hasStringValidation := model.MaxLength != nil || model.MinLength != nil || model.Pattern != ""
 	hasSliceValidations := model.MaxItems != nil || model.MinItems != nil || model.UniqueItems
-	hasValidations := isRequired || hasNumberValidation || hasStringValidation || hasSliceValidations
+	hasObjectValidations := model.MaxProperties != nil || model.MinProperties != nil
+	hasValidations := isRequired || hasNumberValidation || hasStringValidation || hasSliceValidations || hasObjectValidations
 
 	if len(sg.Schema.Enum) > 0 {
 		hasValidations = true
@@ -328,6 +399,8 @@ func (sg *schemaGenContext) schemaValidations() sharedValidations {
 		MaxItems:            model.MaxItems,
 		MinItems:            model.MinItems,
 		UniqueItems:         model.UniqueItems,
+		MinProperties:       model.MinProperties,
+		MaxProperties:       model.MaxProperties,
 		MultipleOf:          model.MultipleOf,
 		Enum:                sg.Schema.Enum,
 		HasValidations:      hasValidations,
@@ -348,28 +421,74 @@ func (sg *schemaGenContext) MergeResult(other *schemaGenContext) {
 }
 
 func (sg *schemaGenContext) buildProperties() error {
+	var diags Diagnostics
 	for k, v := range sg.Schema.Properties {
 		emprop := sg.NewStructBranch(k, v)
-		if err := emprop.makeGenSchema(); err != nil {
-			return err
+		if err := emprop.liftSpecialAllOf(); err != nil {
+			diags.add(emprop.Pointer, err)
+			continue
+		}
+		build := emprop.makeGenSchema
+		if isInlineObject(&emprop.Schema) {
+			build = emprop.buildInlineObject
+		}
+		if err := build(); err != nil {
+			diags.add(emprop.Pointer, err)
+			continue
 		}
 		sg.MergeResult(emprop)
 		sg.GenSchema.Properties = append(sg.GenSchema.Properties, emprop.GenSchema)
 	}
 	sort.Sort(sg.GenSchema.Properties)
-	return nil
+	diags.sort()
+	return diags.ErrorOrNil()
 }
 
 func (sg *schemaGenContext) buildAllOf() error {
+	var diags Diagnostics
 	for i, sch := range sg.Schema.AllOf {
 		comprop := sg.NewCompositionBranch(sch, i)
-		if err := comprop.makeGenSchema(); err != nil {
-			return err
+		build := comprop.makeGenSchema
+		if isInlineObject(&sch) {
+			// an inline member becomes a struct too, DogAllOf1, so it embeds like the referenced ones
+			comprop.Name = "allOf" + strconv.Itoa(i)
+			build = comprop.buildInlineObject
+		}
+		if err := build(); err != nil {
+			diags.add(comprop.Pointer, err)
+			continue
 		}
+		names, closed := sg.declaredProperties(&sch)
+		sg.GenSchema.AllOfProperties = append(sg.GenSchema.AllOfProperties, names...)
+		comprop.GenSchema.IsClosedMember = closed
 		sg.MergeResult(comprop)
 		sg.GenSchema.AllOf = append(sg.GenSchema.AllOf, comprop.GenSchema)
 	}
-	return nil
+	sort.Strings(sg.GenSchema.AllOfProperties)
+	return diags.ErrorOrNil()
+}
+
+// declaredProperties returns the properties a member of a composition declares, with the
+// ones of its own members, and tells whether it or one of its members rejects the others
+func (sg *schemaGenContext) declaredProperties(schema *spec.Schema) (names []string, closed bool) {
+	if schema.Ref.GetURL() != nil {
+		ref, err := spec.ResolveRef(sg.TypeResolver.Doc.Spec(), &schema.Ref)
+		if err != nil {
+			return nil, false
+		}
+		schema = ref
+	}
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	addp := schema.AdditionalProperties
+	closed = addp != nil && !addp.Allows && addp.Schema == nil
+	for i := range schema.AllOf {
+		more, c := sg.declaredProperties(&schema.AllOf[i])
+		names = append(names, more...)
+		closed = closed || c
+	}
+	return names, closed
 }
 
 type mapStack struct {
@@ -511,7 +630,13 @@ func (sg *schemaGenContext) buildAdditionalProperties() error {
 	if !sg.GenSchema.IsMap && (sg.GenSchema.IsAdditionalProperties && sg.Named) {
 		sg.GenSchema.ValueExpression += "." + sg.GenSchema.Name
 		comprop := sg.NewAdditionalProperty(*addp.Schema)
-		if err := comprop.makeGenSchema(); err != nil {
+		build := comprop.makeGenSchema
+		if isInlineObject(addp.Schema) {
+			// the values are objects of their own, PetAdditionalProperties for a Pet
+			comprop.Name = "additionalProperties"
+			build = comprop.buildInlineObject
+		}
+		if err := build(); err != nil {
 			return err
 		}
 		sg.MergeResult(comprop)
@@ -567,15 +692,19 @@ func (sg *schemaGenContext) makeNewStruct(name string, schema spec.Schema) *sche
 		name = swag.ToGoName(sg.TypeResolver.ModelName + " " + name)
 	}
 	sp.Definitions[name] = schema
+	// the new struct is a model of its own, its type and the structs nested in it are named after it
+	resolver := *sg.TypeResolver
+	resolver.ModelName = name
 	pg := schemaGenContext{
 		Path:         "",
+		Pointer:      sg.Pointer,
 		Name:         name,
 		Receiver:     "m",
 		IndexVar:     "i",
 		ValueExpr:    "m",
 		Schema:       schema,
 		Required:     false,
-		TypeResolver: sg.TypeResolver,
+		TypeResolver: &resolver,
 		Named:        true,
 		ExtraSchemas: make(map[string]GenSchema),
 	}
@@ -585,6 +714,32 @@ func (sg *schemaGenContext) makeNewStruct(name string, schema spec.Schema) *sche
 	return &pg
 }
 
+// buildInlineObject builds an anonymous object as a new struct named after the model
+// and the property, PetOwner for the owner of a Pet, and the property references it
+func (sg *schemaGenContext) buildInlineObject() error {
+	newObj := sg.makeNewStruct(sg.Name, sg.Schema)
+	if err := newObj.makeGenSchema(); err != nil {
+		return err
+	}
+
+	sg.Schema = *spec.RefProperty("#/definitions/" + newObj.Name)
+	if err := sg.makeGenSchema(); err != nil {
+		return err
+	}
+	sg.MergeResult(newObj)
+	sg.ExtraSchemas[newObj.Name] = newObj.GenSchema
+	return nil
+}
+
+// isInlineObject tells whether a schema is an object with properties, or a composition,
+// that has no type of its own
+func isInlineObject(schema *spec.Schema) bool {
+	if schema.Ref.GetURL() != nil || (len(schema.Properties) == 0 && len(schema.AllOf) == 0) {
+		return false
+	}
+	return len(schema.Type) == 0 || schema.Type.Contains("object")
+}
+
 func (sg *schemaGenContext) buildArray() error {
 	tpe, err := sg.TypeResolver.ResolveSchema(sg.Schema.Items.Schema, true)
 	if err != nil {
@@ -742,6 +897,7 @@ func (sg *schemaGenContext) shortCircuitNamedRef() (bool, error) {
 		tpe.IsAnonymous = false
 
 		item := sg.NewCompositionBranch(sg.Schema, 0)
+		item.Pointer = sg.Pointer
 		if err := item.makeGenSchema(); err != nil {
 			return true, err
 		}
@@ -755,8 +911,9 @@ func (sg *schemaGenContext) shortCircuitNamedRef() (bool, error) {
 }
 
 func (sg *schemaGenContext) liftSpecialAllOf() error {
-	// if there is only a $ref or a primitive and an x-isnullable schema then this is a nullable pointer
-	if len(sg.Schema.AllOf) > 0 {
+	// if there is only a $ref or a primitive and an x-isnullable schema then this is a nullable pointer,
+	// a definition keeps its composition so it embeds what it references
+	if len(sg.Schema.AllOf) > 0 && !sg.Named {
 		var seenSchema int
 		var seenNullable bool
 		var schemaToLift spec.Schema
@@ -769,7 +926,7 @@ func (sg *schemaGenContext) liftSpecialAllOf() error {
 			if sg.TypeResolver.isNullable(&sch) {
 				seenNullable = true
 			}
-			if len(sch.Type) > 0 || sch.Ref.GetURL() != nil {
+			if len(sch.Type) > 0 || sch.Ref.GetURL() != nil || len(sch.Properties) > 0 {
 				seenSchema++
 				if (!tpe.IsAnonymous && tpe.IsComplexObject) || tpe.IsPrimitive {
 					schemaToLift = sch
@@ -777,7 +934,8 @@ func (sg *schemaGenContext) liftSpecialAllOf() error {
 			}
 		}
 
-		if seenSchema == 1 {
+		// a member with properties of its own makes a composition, not a nullable type
+		if seenSchema == 1 && (schemaToLift.Ref.GetURL() != nil || len(schemaToLift.Type) > 0) {
 			sg.Schema = schemaToLift
 			sg.GenSchema.IsNullable = seenNullable
 		}
@@ -786,7 +944,16 @@ func (sg *schemaGenContext) liftSpecialAllOf() error {
 	return nil
 }
 
-func (sg *schemaGenContext) makeGenSchema() error {
+func (sg *schemaGenContext) makeGenSchema() (err error) {
+	// errors raised for this schema point at it, nested ones keep their own pointer
+	defer func() {
+		if err != nil {
+			var diags Diagnostics
+			diags.add(sg.Pointer, err)
+			err = diags.ErrorOrNil()
+		}
+	}()
+
 	ex := ""
 	if sg.Schema.Example != nil {
 		ex = fmt.Sprintf("%#v", sg.Schema.Example)
@@ -798,11 +965,15 @@ func (sg *schemaGenContext) makeGenSchema() error {
 	sg.GenSchema.ValueExpression = sg.ValueExpr
 	sg.GenSchema.KeyVar = sg.KeyVar
 	sg.GenSchema.Name = sg.Name
+	sg.GenSchema.Pointer = sg.Pointer
 	sg.GenSchema.Title = sg.Schema.Title
 	sg.GenSchema.Description = sg.Schema.Description
 	sg.GenSchema.ReceiverName = sg.Receiver
 	sg.GenSchema.sharedValidations = sg.schemaValidations()
 	sg.GenSchema.ReadOnly = sg.Schema.ReadOnly
+	if sg.Named {
+		sg.GenSchema.DiscriminatorValue = discriminatorValue(sg.Name, sg.Schema)
+	}
 
 	returns, err := sg.shortCircuitNamedRef()
 	if err != nil {
@@ -852,7 +1023,7 @@ func (sg *schemaGenContext) makeGenSchema() error {
 	sg.GenSchema.IsAdditionalProperties = prev.IsAdditionalProperties
 
 	if err := sg.buildProperties(); err != nil {
-		return nil
+		return err
 	}
 
 	if err := sg.buildXMLName(); err != nil {
@@ -905,6 +1076,14 @@ type GenSchema struct {
 	AdditionalProperties    *GenSchema
 	ReadOnly                bool
 	IsVirtual               bool
+	DiscriminatorValue      string
+	// AllOfProperties are the properties the members of a composition declare
+	AllOfProperties []string
+	// IsClosedMember tells a member of a composition that rejects the properties it
+	// doesn't declare, the composition rejects the ones none of its members declares
+	IsClosedMember bool
+	// Pointer locates the schema in the spec, the generator reports its failures there
+	Pointer string
 }
 
 type sharedValidations struct {
@@ -925,4 +1104,6 @@ type sharedValidations struct {
 	UniqueItems         bool
 	HasSliceValidations bool
 	NeedsSize           bool
+	MinProperties       *int64
+	MaxProperties       *int64
 }
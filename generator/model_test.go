@@ -1,1254 +1,426 @@
 package generator
 
 import (
-	"bytes"
-	"regexp"
-	"strconv"
-	"strings"
+	"os"
 	"testing"
-	"text/template"
 
-	"github.com/aiyi/swagger-gin/spec"
 	"github.com/stretchr/testify/assert"
 )
 
-type templateTest struct {
-	t        testing.TB
-	template *template.Template
-}
-
-func (tt *templateTest) assertRender(data interface{}, expected string) bool {
-	buf := bytes.NewBuffer(nil)
-	err := tt.template.Execute(buf, data)
-	if !assert.NoError(tt.t, err) {
-		return false
-	}
-	return assert.Equal(tt.t, expected, buf.String())
-}
-
-func TestGenerateModel_Sanity(t *testing.T) {
-	// just checks if it can render and format these things
-	specDoc, err := spec.Load("../fixtures/codegen/todolist.models.yml")
-	if assert.NoError(t, err) {
-		definitions := specDoc.Spec().Definitions
-
-		//k := "Comment"
-		//schema := definitions[k]
-		for k, schema := range definitions {
-			genModel, err := makeGenDefinition(k, "models", schema, specDoc)
-
-			// log.Printf("trying model: %s", k)
-			if assert.NoError(t, err) {
-				//b, _ := json.MarshalIndent(genModel, "", "  ")
-				//fmt.Println(string(b))
-				rendered := bytes.NewBuffer(nil)
-
-				err := modelTemplate.Execute(rendered, genModel)
-				if assert.NoError(t, err) {
-					if assert.NoError(t, err) {
-						_, err := formatGoFile(strings.ToLower(k)+".go", rendered.Bytes())
-						assert.NoError(t, err)
-						//if assert.NoError(t, err) {
-						//fmt.Println(string(formatted))
-						//} else {
-						//fmt.Println(rendered.String())
-						////break
-						//}
-
-						//assert.EqualValues(t, strings.TrimSpace(string(expected)), strings.TrimSpace(string(formatted)))
-					}
-				}
-			}
-		}
-	}
-}
-
-func TestGenerateModel_DocString(t *testing.T) {
-	templ := template.Must(template.New("docstring").Funcs(FuncMap).Parse(string(assetDocString)))
-	tt := templateTest{t, templ}
-
-	var gmp GenSchema
-	gmp.Title = "The title of the property"
-	gmp.Description = "The description of the property"
-	var expected = `The title of the property
-
-The description of the property
+// definitions is the head of the specs of the model cases, the definitions follow
+const definitions = `
+swagger: "2.0"
+info: {title: restapi, version: "1.0"}
+paths: {}
+definitions:
 `
-	tt.assertRender(gmp, expected)
-
-	gmp.Title = ""
-	expected = `The description of the property
-`
-	tt.assertRender(gmp, expected)
-
-	gmp.Description = ""
-	gmp.Name = "theModel"
-	expected = `TheModel the model
-`
-	tt.assertRender(gmp, expected)
-}
-
-func TestGenerateModel_PropertyValidation(t *testing.T) {
-	templ := template.Must(template.New("propertyValidationDocString").Funcs(FuncMap).Parse(string(assetStuctFieldValidation)))
-	tt := templateTest{t, templ}
-
-	var gmp GenSchema
-	gmp.Required = true
-	tt.assertRender(gmp, `
-Required: true
-`)
-	var fl float64 = 10
-	var in1 int64 = 20
-	var in2 int64 = 30
-	gmp.Maximum = &fl
-	gmp.ExclusiveMaximum = true
-	gmp.Minimum = &fl
-	gmp.ExclusiveMinimum = true
-	gmp.MaxLength = &in1
-	gmp.MinLength = &in1
-	gmp.Pattern = "\\w[\\w- ]+"
-	gmp.MaxItems = &in2
-	gmp.MinItems = &in2
-	gmp.UniqueItems = true
-
-	tt.assertRender(gmp, `
-Required: true
-Maximum: < 10
-Minimum: > 10
-Max Length: 20
-Min Length: 20
-Pattern: \w[\w- ]+
-Max Items: 30
-Min Items: 30
-Unique: true
-`)
-
-	gmp.Required = false
-	gmp.ExclusiveMaximum = false
-	gmp.ExclusiveMinimum = false
-	tt.assertRender(gmp, `
-Maximum: 10
-Minimum: 10
-Max Length: 20
-Min Length: 20
-Pattern: \w[\w- ]+
-Max Items: 30
-Min Items: 30
-Unique: true
-`)
-
-}
-
-func TestGenerateModel_SchemaField(t *testing.T) {
-	tt := templateTest{t, modelTemplate.Lookup("structfield")}
-
-	var gmp GenSchema
-	gmp.Name = "some name"
-	gmp.resolvedType = resolvedType{GoType: "string", IsPrimitive: true}
-	gmp.Title = "The title of the property"
-
-	tt.assertRender(gmp, `/* The title of the property
- */
-`+"SomeName string `json:\"some name,omitempty\"`\n")
-
-	var fl float64 = 10
-	var in1 int64 = 20
-	var in2 int64 = 30
-
-	gmp.Description = "The description of the property"
-	gmp.Required = true
-	gmp.Maximum = &fl
-	gmp.ExclusiveMaximum = true
-	gmp.Minimum = &fl
-	gmp.ExclusiveMinimum = true
-	gmp.MaxLength = &in1
-	gmp.MinLength = &in1
-	gmp.Pattern = "\\w[\\w- ]+"
-	gmp.MaxItems = &in2
-	gmp.MinItems = &in2
-	gmp.UniqueItems = true
-	gmp.ReadOnly = true
-	tt.assertRender(gmp, `/* The title of the property
-
-The description of the property
-
-Required: true
-Read Only: true
-Maximum: < 10
-Minimum: > 10
-Max Length: 20
-Min Length: 20
-Pattern: \w[\w- ]+
-Max Items: 30
-Min Items: 30
-Unique: true
- */
-`+"SomeName string `json:\"some name\"`\n")
-}
-
-var schTypeGenDataSimple = []struct {
-	Value    GenSchema
-	Expected string
-}{
-	{GenSchema{resolvedType: resolvedType{GoType: "string", IsPrimitive: true}}, "string"},
-	{GenSchema{resolvedType: resolvedType{GoType: "string", IsPrimitive: true, IsNullable: true}}, "*string"},
-	{GenSchema{resolvedType: resolvedType{GoType: "bool", IsPrimitive: true}}, "bool"},
-	{GenSchema{resolvedType: resolvedType{GoType: "int32", IsPrimitive: true}}, "int32"},
-	{GenSchema{resolvedType: resolvedType{GoType: "int64", IsPrimitive: true}}, "int64"},
-	{GenSchema{resolvedType: resolvedType{GoType: "float32", IsPrimitive: true}}, "float32"},
-	{GenSchema{resolvedType: resolvedType{GoType: "float64", IsPrimitive: true}}, "float64"},
-	{GenSchema{resolvedType: resolvedType{GoType: "strfmt.Base64", IsPrimitive: true}}, "strfmt.Base64"},
-	{GenSchema{resolvedType: resolvedType{GoType: "strfmt.Date", IsPrimitive: true}}, "strfmt.Date"},
-	{GenSchema{resolvedType: resolvedType{GoType: "strfmt.DateTime", IsPrimitive: true}}, "strfmt.DateTime"},
-	{GenSchema{resolvedType: resolvedType{GoType: "strfmt.URI", IsPrimitive: true}}, "strfmt.URI"},
-	{GenSchema{resolvedType: resolvedType{GoType: "strfmt.Email", IsPrimitive: true}}, "strfmt.Email"},
-	{GenSchema{resolvedType: resolvedType{GoType: "strfmt.Hostname", IsPrimitive: true}}, "strfmt.Hostname"},
-	{GenSchema{resolvedType: resolvedType{GoType: "strfmt.IPv4", IsPrimitive: true}}, "strfmt.IPv4"},
-	{GenSchema{resolvedType: resolvedType{GoType: "strfmt.IPv6", IsPrimitive: true}}, "strfmt.IPv6"},
-	{GenSchema{resolvedType: resolvedType{GoType: "strfmt.UUID", IsPrimitive: true}}, "strfmt.UUID"},
-	{GenSchema{resolvedType: resolvedType{GoType: "strfmt.UUID3", IsPrimitive: true}}, "strfmt.UUID3"},
-	{GenSchema{resolvedType: resolvedType{GoType: "strfmt.UUID4", IsPrimitive: true}}, "strfmt.UUID4"},
-	{GenSchema{resolvedType: resolvedType{GoType: "strfmt.UUID5", IsPrimitive: true}}, "strfmt.UUID5"},
-	{GenSchema{resolvedType: resolvedType{GoType: "strfmt.ISBN", IsPrimitive: true}}, "strfmt.ISBN"},
-	{GenSchema{resolvedType: resolvedType{GoType: "strfmt.ISBN10", IsPrimitive: true}}, "strfmt.ISBN10"},
-	{GenSchema{resolvedType: resolvedType{GoType: "strfmt.ISBN13", IsPrimitive: true}}, "strfmt.ISBN13"},
-	{GenSchema{resolvedType: resolvedType{GoType: "strfmt.CreditCard", IsPrimitive: true}}, "strfmt.CreditCard"},
-	{GenSchema{resolvedType: resolvedType{GoType: "strfmt.SSN", IsPrimitive: true}}, "strfmt.SSN"},
-	{GenSchema{resolvedType: resolvedType{GoType: "strfmt.HexColor", IsPrimitive: true}}, "strfmt.HexColor"},
-	{GenSchema{resolvedType: resolvedType{GoType: "strfmt.RGBColor", IsPrimitive: true}}, "strfmt.RGBColor"},
-	{GenSchema{resolvedType: resolvedType{GoType: "strfmt.Duration", IsPrimitive: true}}, "strfmt.Duration"},
-	{GenSchema{resolvedType: resolvedType{GoType: "strfmt.Password", IsPrimitive: true}}, "strfmt.Password"},
-	{GenSchema{resolvedType: resolvedType{GoType: "interface{}", IsInterface: true}}, "interface{}"},
-	{GenSchema{resolvedType: resolvedType{GoType: "[]int32", IsArray: true}}, "[]int32"},
-	{GenSchema{resolvedType: resolvedType{GoType: "[]string", IsArray: true}}, "[]string"},
-	{GenSchema{resolvedType: resolvedType{GoType: "map[string]int32", IsMap: true}}, "map[string]int32"},
-	{GenSchema{resolvedType: resolvedType{GoType: "models.Task", IsComplexObject: true, IsNullable: true, IsAnonymous: false}}, "*models.Task"},
-}
-
-func TestGenSchemaType(t *testing.T) {
-	tt := templateTest{t, modelTemplate.Lookup("schemaType")}
-	for _, v := range schTypeGenDataSimple {
-		tt.assertRender(v.Value, v.Expected)
-	}
-}
-func TestGenerateModel_Primitives(t *testing.T) {
-	tt := templateTest{t, modelTemplate.Lookup("schema")}
-	for _, v := range schTypeGenDataSimple {
-		val := v.Value
-		if val.IsComplexObject {
-			continue
-		}
-		val.Name = "theType"
-		exp := v.Expected
-		tt.assertRender(val, "type TheType "+exp+"\n\n")
-	}
-}
-
-func TestGenerateModel_Nota(t *testing.T) {
-	specDoc, err := spec.Load("../fixtures/codegen/todolist.models.yml")
-	if assert.NoError(t, err) {
-		definitions := specDoc.Spec().Definitions
-		k := "Nota"
-		schema := definitions[k]
-		genModel, err := makeGenDefinition(k, "models", schema, specDoc)
-		if assert.NoError(t, err) {
-			buf := bytes.NewBuffer(nil)
-			err := modelTemplate.Execute(buf, genModel)
-			if assert.NoError(t, err) {
-				res := buf.String()
-				assertInCode(t, "type Nota map[string]int32", res)
-			}
-		}
-	}
-}
-
-func TestGenerateModel_NotaWithRef(t *testing.T) {
-	specDoc, err := spec.Load("../fixtures/codegen/todolist.models.yml")
-	if assert.NoError(t, err) {
-		definitions := specDoc.Spec().Definitions
-		k := "NotaWithRef"
-		schema := definitions[k]
-		genModel, err := makeGenDefinition(k, "models", schema, specDoc)
-		if assert.NoError(t, err) {
-			buf := bytes.NewBuffer(nil)
-			err := modelTemplate.Execute(buf, genModel)
-			if assert.NoError(t, err) {
-				ff, err := formatGoFile("nota_with_ref.go", buf.Bytes())
-				if assert.NoError(t, err) {
-					res := string(ff)
-					assertInCode(t, "type NotaWithRef map[string]Notable", res)
-				}
-			}
-		}
-	}
-}
-
-func TestGenerateModel_NotaWithMeta(t *testing.T) {
-	specDoc, err := spec.Load("../fixtures/codegen/todolist.models.yml")
-	if assert.NoError(t, err) {
-		definitions := specDoc.Spec().Definitions
-		k := "NotaWithMeta"
-		schema := definitions[k]
-		genModel, err := makeGenDefinition(k, "models", schema, specDoc)
-		if assert.NoError(t, err) {
-			buf := bytes.NewBuffer(nil)
-			err := modelTemplate.Execute(buf, genModel)
-			if assert.NoError(t, err) {
-				ff, err := formatGoFile("nota_with_meta.go", buf.Bytes())
-				if assert.NoError(t, err) {
-					res := string(ff)
-					assertInCode(t, "type NotaWithMeta map[string]NotaWithMetaAnon", res)
-					assertInCode(t, "type NotaWithMetaAnon struct {", res)
-					assertInCode(t, "Comment string `json:\"comment,omitempty\"`", res)
-					assertInCode(t, "Count int32 `json:\"count,omitempty\"`", res)
-				}
-			}
-		}
-	}
-}
-
-func TestGenerateModel_NotaWithName(t *testing.T) {
-	specDoc, err := spec.Load("../fixtures/codegen/todolist.models.yml")
-	if assert.NoError(t, err) {
-		definitions := specDoc.Spec().Definitions
-		k := "NotaWithName"
-		schema := definitions[k]
-		genModel, err := makeGenDefinition(k, "models", schema, specDoc)
-		if assert.NoError(t, err) {
-			assert.True(t, genModel.IsAdditionalProperties)
-			assert.False(t, genModel.IsComplexObject)
-			assert.False(t, genModel.IsMap)
-			assert.False(t, genModel.IsAnonymous)
-			buf := bytes.NewBuffer(nil)
-			err := modelTemplate.Execute(buf, genModel)
-			if assert.NoError(t, err) {
-				res := buf.String()
-				assertInCode(t, "type "+k+" struct {", res)
-				assertInCode(t, k+" map[string]int32 `json:\"-\"`", res)
-				assertInCode(t, "Name string `json:\"name,omitempty\"`", res)
-				assertInCode(t, k+") UnmarshalJSON", res)
-				assertInCode(t, k+") MarshalJSON", res)
-				assertInCode(t, "json.Marshal(m)", res)
-				assertInCode(t, "json.Marshal(m."+k+")", res)
-				assertInCode(t, "json.Unmarshal(data, &stage1)", res)
-				assertInCode(t, "json.Unmarshal(data, &stage2)", res)
-				assertInCode(t, "json.Unmarshal(v, &toadd)", res)
-				assertInCode(t, "result[k] = toadd", res)
-				assertInCode(t, "m."+k+" = result", res)
-				for _, p := range genModel.Properties {
-					assertInCode(t, "delete(stage2, \""+p.Name+"\")", res)
-				}
-
-			}
-		}
-	}
-}
-
-func TestGenerateModel_NotaWithRefRegistry(t *testing.T) {
-	specDoc, err := spec.Load("../fixtures/codegen/todolist.models.yml")
-	if assert.NoError(t, err) {
-		definitions := specDoc.Spec().Definitions
-		k := "NotaWithRefRegistry"
-		schema := definitions[k]
-		genModel, err := makeGenDefinition(k, "models", schema, specDoc)
-		if assert.NoError(t, err) {
-			buf := bytes.NewBuffer(nil)
-			err := modelTemplate.Execute(buf, genModel)
-			if assert.NoError(t, err) {
-				ff, err := formatGoFile("nota_with_ref_registry.go", buf.Bytes())
-				if assert.NoError(t, err) {
-					res := string(ff)
-					assertInCode(t, "type "+k+" map[string]map[string]map[string]Notable", res)
-				}
-			}
-		}
-	}
-}
-
-func TestGenerateModel_NotaWithMetaRegistry(t *testing.T) {
-	specDoc, err := spec.Load("../fixtures/codegen/todolist.models.yml")
-	if assert.NoError(t, err) {
-		definitions := specDoc.Spec().Definitions
-		k := "NotaWithMetaRegistry"
-		schema := definitions[k]
-		genModel, err := makeGenDefinition(k, "models", schema, specDoc)
-		if assert.NoError(t, err) {
-			buf := bytes.NewBuffer(nil)
-			err := modelTemplate.Execute(buf, genModel)
-			if assert.NoError(t, err) {
-				ff, err := formatGoFile("nota_with_meta_registry.go", buf.Bytes())
-				if assert.NoError(t, err) {
-					res := string(ff)
-					assertInCode(t, "type "+k+" map[string]map[string]map[string]NotaWithMetaRegistryAnon", res)
-					assertInCode(t, "type NotaWithMetaRegistryAnon struct {", res)
-					assertInCode(t, "Comment string `json:\"comment,omitempty\"`", res)
-					assertInCode(t, "Count int32 `json:\"count,omitempty\"`", res)
-				}
-			}
-		}
-	}
-}
-
-func TestGenerateModel_WithMap(t *testing.T) {
-	specDoc, err := spec.Load("../fixtures/codegen/todolist.models.yml")
-	if assert.NoError(t, err) {
-		definitions := specDoc.Spec().Definitions
-		schema := definitions["WithMap"]
-		genModel, err := makeGenDefinition("WithMap", "models", schema, specDoc)
-		if assert.NoError(t, err) {
-			assert.False(t, genModel.HasAdditionalProperties)
-			prop := getDefinitionProperty(genModel, "data")
-			assert.True(t, prop.HasAdditionalProperties)
-			assert.True(t, prop.IsMap)
-			assert.False(t, prop.IsComplexObject)
-			buf := bytes.NewBuffer(nil)
-			err := modelTemplate.Execute(buf, genModel)
-			if assert.NoError(t, err) {
-				res := buf.String()
-				assertInCode(t, "type WithMap struct {", res)
-				assertInCode(t, "Data map[string]string `json:\"data,omitempty\"`", res)
-			}
-		}
-	}
-}
-
-func TestGenerateModel_WithMapRef(t *testing.T) {
-	specDoc, err := spec.Load("../fixtures/codegen/todolist.models.yml")
-	if assert.NoError(t, err) {
-		definitions := specDoc.Spec().Definitions
-		k := "WithMapRef"
-		schema := definitions[k]
-		genModel, err := makeGenDefinition(k, "models", schema, specDoc)
-		if assert.NoError(t, err) {
-			assert.False(t, genModel.HasAdditionalProperties)
-			prop := getDefinitionProperty(genModel, "data")
-			assert.True(t, prop.HasAdditionalProperties)
-			assert.True(t, prop.IsMap)
-			assert.False(t, prop.IsComplexObject)
-			buf := bytes.NewBuffer(nil)
-			err := modelTemplate.Execute(buf, genModel)
-			if assert.NoError(t, err) {
-				res := buf.String()
-				assertInCode(t, "type "+k+" struct {", res)
-				assertInCode(t, "Data map[string]Notable `json:\"data,omitempty\"`", res)
-			}
-		}
-	}
-}
-
-func TestGenerateModel_WithMapComplex(t *testing.T) {
-	specDoc, err := spec.Load("../fixtures/codegen/todolist.models.yml")
-	if assert.NoError(t, err) {
-		definitions := specDoc.Spec().Definitions
-		k := "WithMapComplex"
-		schema := definitions[k]
-		genModel, err := makeGenDefinition(k, "models", schema, specDoc)
-		if assert.NoError(t, err) {
-			assert.False(t, genModel.HasAdditionalProperties)
-			prop := getDefinitionProperty(genModel, "data")
-			assert.True(t, prop.HasAdditionalProperties)
-			assert.True(t, prop.IsMap)
-			assert.False(t, prop.IsComplexObject)
-			buf := bytes.NewBuffer(nil)
-			err := modelTemplate.Execute(buf, genModel)
-			if assert.NoError(t, err) {
-				res := buf.String()
-				assertInCode(t, "type "+k+" struct {", res)
-				assertInCode(t, "Data map[string]"+k+"DataAnon `json:\"data,omitempty\"`", res)
-			}
-		}
-	}
-}
-
-func TestGenerateModel_WithMapRegistry(t *testing.T) {
-	specDoc, err := spec.Load("../fixtures/codegen/todolist.models.yml")
-	if assert.NoError(t, err) {
-		definitions := specDoc.Spec().Definitions
-		schema := definitions["WithMapRegistry"]
-		genModel, err := makeGenDefinition("WithMap", "models", schema, specDoc)
-		if assert.NoError(t, err) {
-			assert.False(t, genModel.HasAdditionalProperties)
-			prop := getDefinitionProperty(genModel, "data")
-			assert.True(t, prop.HasAdditionalProperties)
-			assert.True(t, prop.IsMap)
-			assert.False(t, prop.IsComplexObject)
-			buf := bytes.NewBuffer(nil)
-			err := modelTemplate.Execute(buf, genModel)
-			if assert.NoError(t, err) {
-				res := buf.String()
-				assertInCode(t, "type WithMap struct {", res)
-				assertInCode(t, "Data map[string]map[string]map[string]string `json:\"data,omitempty\"`", res)
-			}
-		}
-	}
-}
-
-func TestGenerateModel_WithMapRegistryRef(t *testing.T) {
-	specDoc, err := spec.Load("../fixtures/codegen/todolist.models.yml")
-	if assert.NoError(t, err) {
-		definitions := specDoc.Spec().Definitions
-		k := "WithMapRegistryRef"
-		schema := definitions[k]
-		genModel, err := makeGenDefinition(k, "models", schema, specDoc)
-		if assert.NoError(t, err) {
-			assert.False(t, genModel.HasAdditionalProperties)
-			prop := getDefinitionProperty(genModel, "data")
-			assert.True(t, prop.HasAdditionalProperties)
-			assert.True(t, prop.IsMap)
-			assert.False(t, prop.IsComplexObject)
-			buf := bytes.NewBuffer(nil)
-			err := modelTemplate.Execute(buf, genModel)
-			if assert.NoError(t, err) {
-				res := buf.String()
-				assertInCode(t, "type "+k+" struct {", res)
-				assertInCode(t, "Data map[string]map[string]map[string]Notable `json:\"data,omitempty\"`", res)
-			}
-		}
-	}
-}
-
-func TestGenerateModel_WithMapComplexRegistry(t *testing.T) {
-	specDoc, err := spec.Load("../fixtures/codegen/todolist.models.yml")
-	if assert.NoError(t, err) {
-		definitions := specDoc.Spec().Definitions
-		k := "WithMapComplexRegistry"
-		schema := definitions[k]
-		genModel, err := makeGenDefinition(k, "models", schema, specDoc)
-		if assert.NoError(t, err) {
-			assert.False(t, genModel.HasAdditionalProperties)
-			prop := getDefinitionProperty(genModel, "data")
-			assert.True(t, prop.HasAdditionalProperties)
-			assert.True(t, prop.IsMap)
-			assert.False(t, prop.IsComplexObject)
-			buf := bytes.NewBuffer(nil)
-			err := modelTemplate.Execute(buf, genModel)
-			if assert.NoError(t, err) {
-				res := buf.String()
-				assertInCode(t, "type "+k+" struct {", res)
-				assertInCode(t, "Data map[string]map[string]map[string]"+k+"DataAnon `json:\"data,omitempty\"`", res)
-			}
-		}
-	}
-}
-
-func TestGenerateModel_WithAdditional(t *testing.T) {
-	specDoc, err := spec.Load("../fixtures/codegen/todolist.models.yml")
-	if assert.NoError(t, err) {
-		definitions := specDoc.Spec().Definitions
-		k := "WithAdditional"
-		schema := definitions[k]
-		genModel, err := makeGenDefinition(k, "models", schema, specDoc)
-		if assert.NoError(t, err) && assert.NotEmpty(t, genModel.ExtraSchemas) {
-			assert.False(t, genModel.HasAdditionalProperties)
-			assert.False(t, genModel.IsMap)
-			assert.False(t, genModel.IsAdditionalProperties)
-			assert.True(t, genModel.IsComplexObject)
 
-			sch := genModel.ExtraSchemas[0]
-			assert.True(t, sch.HasAdditionalProperties)
-			assert.False(t, sch.IsMap)
-			assert.True(t, sch.IsAdditionalProperties)
-			assert.False(t, sch.IsComplexObject)
-
-			if assert.NotNil(t, sch.AdditionalProperties) {
-				prop := findProperty(genModel.Properties, "data")
-				assert.False(t, prop.HasAdditionalProperties)
-				assert.False(t, prop.IsMap)
-				assert.False(t, prop.IsAdditionalProperties)
-				assert.True(t, prop.IsComplexObject)
-				buf := bytes.NewBuffer(nil)
-				err := modelTemplate.Execute(buf, genModel)
-				if assert.NoError(t, err) {
-					res := buf.String()
-					assertInCode(t, "type "+k+" struct {", res)
-					assertInCode(t, "Data "+k+"DataP0 `json:\"data,omitempty\"`", res)
-					assertInCode(t, "type "+k+"DataP0 struct {", res)
-					assertInCode(t, k+"DataP0 map[string]string `json:\"-\"`", res)
-					assertInCode(t, "Name string `json:\"name,omitempty\"`", res)
-					assertInCode(t, k+"DataP0) UnmarshalJSON", res)
-					assertInCode(t, k+"DataP0) MarshalJSON", res)
-					assertInCode(t, "json.Marshal(m)", res)
-					assertInCode(t, "json.Marshal(m."+k+"DataP0)", res)
-					assertInCode(t, "json.Unmarshal(data, &stage1)", res)
-					assertInCode(t, "json.Unmarshal(data, &stage2)", res)
-					assertInCode(t, "json.Unmarshal(v, &toadd)", res)
-					assertInCode(t, "result[k] = toadd", res)
-					assertInCode(t, "m."+k+"DataP0 = result", res)
-					for _, p := range sch.Properties {
-						assertInCode(t, "delete(stage2, \""+p.Name+"\")", res)
-					}
-				}
-			}
-		}
-	}
-}
-
-func TestGenerateModel_JustRef(t *testing.T) {
-	tt := templateTest{t, modelTemplate.Lookup("schema")}
-	specDoc, err := spec.Load("../fixtures/codegen/todolist.models.yml")
-	if assert.NoError(t, err) {
-		definitions := specDoc.Spec().Definitions
-		schema := definitions["JustRef"]
-		genModel, err := makeGenDefinition("JustRef", "models", schema, specDoc)
-		if assert.NoError(t, err) {
-			assert.NotEmpty(t, genModel.AllOf)
-			assert.True(t, genModel.IsComplexObject)
-			assert.Equal(t, "JustRef", genModel.Name)
-			assert.Equal(t, "JustRef", genModel.GoType)
-			buf := bytes.NewBuffer(nil)
-			tt.template.Execute(buf, genModel)
-			res := buf.String()
-			assertInCode(t, "type JustRef struct {", res)
-			assertInCode(t, "Notable", res)
-		}
-	}
-}
-
-func TestGenerateModel_WithRef(t *testing.T) {
-	tt := templateTest{t, modelTemplate.Lookup("schema")}
-	specDoc, err := spec.Load("../fixtures/codegen/todolist.models.yml")
-	if assert.NoError(t, err) {
-		definitions := specDoc.Spec().Definitions
-		schema := definitions["WithRef"]
-		genModel, err := makeGenDefinition("WithRef", "models", schema, specDoc)
-		if assert.NoError(t, err) {
-			assert.True(t, genModel.IsComplexObject)
-			assert.Equal(t, "WithRef", genModel.Name)
-			assert.Equal(t, "WithRef", genModel.GoType)
-			buf := bytes.NewBuffer(nil)
-			tt.template.Execute(buf, genModel)
-			res := buf.String()
-			assertInCode(t, "type WithRef struct {", res)
-			assertInCode(t, "Notes Notable `json:\"notes,omitempty\"`", res)
-		}
-	}
-}
-
-func TestGenerateModel_WithNullableRef(t *testing.T) {
-	tt := templateTest{t, modelTemplate.Lookup("schema")}
-	specDoc, err := spec.Load("../fixtures/codegen/todolist.models.yml")
-	if assert.NoError(t, err) {
-		definitions := specDoc.Spec().Definitions
-		schema := definitions["WithNullableRef"]
-		genModel, err := makeGenDefinition("WithNullableRef", "models", schema, specDoc)
-		if assert.NoError(t, err) {
-			assert.True(t, genModel.IsComplexObject)
-			assert.Equal(t, "WithNullableRef", genModel.Name)
-			assert.Equal(t, "WithNullableRef", genModel.GoType)
-			prop := getDefinitionProperty(genModel, "notes")
-			assert.True(t, prop.IsNullable)
-			assert.True(t, prop.IsComplexObject)
-			buf := bytes.NewBuffer(nil)
-			tt.template.Execute(buf, genModel)
-			res := buf.String()
-			assertInCode(t, "type WithNullableRef struct {", res)
-			assertInCode(t, "Notes *Notable `json:\"notes,omitempty\"`", res)
-		}
-	}
-}
-
-func TestGenerateModel_Scores(t *testing.T) {
-	specDoc, err := spec.Load("../fixtures/codegen/todolist.models.yml")
-	if assert.NoError(t, err) {
-		definitions := specDoc.Spec().Definitions
-		k := "Scores"
-		schema := definitions[k]
-		genModel, err := makeGenDefinition(k, "models", schema, specDoc)
-		if assert.NoError(t, err) {
-			buf := bytes.NewBuffer(nil)
-			err := modelTemplate.Execute(buf, genModel)
-			if assert.NoError(t, err) {
-				ff, err := formatGoFile("scores.go", buf.Bytes())
-				if assert.NoError(t, err) {
-					res := string(ff)
-					assertInCode(t, "type Scores []float32", res)
-				}
-			}
-		}
-	}
-}
-
-func TestGenerateModel_JaggedScores(t *testing.T) {
-	specDoc, err := spec.Load("../fixtures/codegen/todolist.models.yml")
-	if assert.NoError(t, err) {
-		definitions := specDoc.Spec().Definitions
-		k := "JaggedScores"
-		schema := definitions[k]
-		genModel, err := makeGenDefinition(k, "models", schema, specDoc)
-		if assert.NoError(t, err) {
-			buf := bytes.NewBuffer(nil)
-			err := modelTemplate.Execute(buf, genModel)
-			if assert.NoError(t, err) {
-				ff, err := formatGoFile("jagged_scores.go", buf.Bytes())
-				if assert.NoError(t, err) {
-					res := string(ff)
-					assertInCode(t, "type JaggedScores [][][]float32", res)
-				}
-			}
-		}
-	}
-}
-
-func TestGenerateModel_Notables(t *testing.T) {
-	specDoc, err := spec.Load("../fixtures/codegen/todolist.models.yml")
-	if assert.NoError(t, err) {
-		definitions := specDoc.Spec().Definitions
-		k := "Notables"
-		schema := definitions[k]
-		genModel, err := makeGenDefinition(k, "models", schema, specDoc)
-		if assert.NoError(t, err) {
-			buf := bytes.NewBuffer(nil)
-			err := modelTemplate.Execute(buf, genModel)
-			if assert.NoError(t, err) {
-				ff, err := formatGoFile("notables.go", buf.Bytes())
-				if assert.NoError(t, err) {
-					res := string(ff)
-					assertInCode(t, "type Notables []Notable", res)
-				}
-			}
-		}
-	}
-}
-
-func TestGenerateModel_Notablix(t *testing.T) {
-	specDoc, err := spec.Load("../fixtures/codegen/todolist.models.yml")
-	if assert.NoError(t, err) {
-		definitions := specDoc.Spec().Definitions
-		k := "Notablix"
-		schema := definitions[k]
-		genModel, err := makeGenDefinition(k, "models", schema, specDoc)
-		if assert.NoError(t, err) {
-			buf := bytes.NewBuffer(nil)
-			err := modelTemplate.Execute(buf, genModel)
-			if assert.NoError(t, err) {
-				ff, err := formatGoFile("notablix.go", buf.Bytes())
-				if assert.NoError(t, err) {
-					res := string(ff)
-					assertInCode(t, "type Notablix [][][]Notable", res)
-				}
-			}
-		}
-	}
-}
-
-func TestGenerateModel_Stats(t *testing.T) {
-	specDoc, err := spec.Load("../fixtures/codegen/todolist.models.yml")
-	if assert.NoError(t, err) {
-		definitions := specDoc.Spec().Definitions
-		k := "Stats"
-		schema := definitions[k]
-		genModel, err := makeGenDefinition(k, "models", schema, specDoc)
-		if assert.NoError(t, err) {
-			buf := bytes.NewBuffer(nil)
-			err := modelTemplate.Execute(buf, genModel)
-			if assert.NoError(t, err) {
-				ff, err := formatGoFile("stats.go", buf.Bytes())
-				if assert.NoError(t, err) {
-					res := string(ff)
-					assertInCode(t, "type Stats []StatsItems0", res)
-					assertInCode(t, "type StatsItems0 struct {", res)
-					assertInCode(t, "Points []int64 `json:\"points,omitempty\"`", res)
-				}
+// modelCase is a spec and what the files generated from it hold, the handlers are
+// generated along with the models when the spec has operations
+type modelCase struct {
+	name       string
+	doc        string
+	setup      func(*GenOpts)
+	operations bool
+	files      map[string]expected
+}
+
+var modelCases = []modelCase{
+	{
+		name: "type mapping",
+		doc: definitions + `
+  Pet:
+    required: [id]
+    properties:
+      id: {type: string, format: uuid, maxLength: 36}
+      friends: {type: array, maxItems: 3, items: {type: string, format: uuid}}
+      age: {type: integer, minimum: 0}
+      weight: {type: integer, format: int32, minimum: 0}
+      name: {type: string, minLength: 1}
+`,
+		setup: func(opts *GenOpts) {
+			opts.TypeMapping = map[string]string{"uuid": "uuid.UUID", "integer": "int"}
+			opts.Imports = map[string]string{"uuid": "github.com/google/uuid", "decimal": "github.com/shopspring/decimal"}
+		},
+		files: map[string]expected{
+			"models/pet.go": {
+				matches: []string{
+					`Id\s+uuid.UUID\s+` + "`json:\"id\" binding:\"required\"`",
+					`Friends\s+\[\]uuid.UUID\s+`,
+					`Age\s+int\s+`,
+					// a format takes precedence over the type it's mapped with
+					`Weight\s+int32\s+`,
+				},
+				contains: []string{
+					`uuid "github.com/google/uuid"`,
+					// the mapped values are checked by their type, the others still are validated
+					`validate.MaxItems("friends", "body", int64(len(m.Friends)), 3)`,
+					"func (m *Pet) validateWeight() error",
+					"func (m *Pet) validateName() error",
+				},
+				absent: []string{"shopspring", "validateId", "validateAge", `errors.Required("id", "body")`},
+			},
+		},
+	},
+	{
+		name: "nested structs",
+		doc: definitions + `
+  Pet:
+    properties:
+      owner:
+        type: object
+        required: [name]
+        properties:
+          name: {type: string}
+          address:
+            type: object
+            properties:
+              city: {type: string, minLength: 2}
+      toys:
+        type: array
+        items:
+          type: object
+          required: [label]
+          properties:
+            label: {type: string, maxLength: 10}
+`,
+		files: map[string]expected{
+			"models/pet.go": {
+				// the inline objects are structs named after the path to them, at any depth
+				matches: []string{
+					`Owner\s+\*PetOwner\s+` + "`json:\"owner,omitempty\"`",
+					`Toys\s+\[\]PetToysItems0\s+`,
+					`Address\s+\*PetOwnerAddress\s+`,
+					`Name\s+string\s+` + "`json:\"name\" binding:\"required\"`",
+					// the binding tags don't reach the items, the validators check the required properties
+					`func \(m \*PetToysItems0\) Validate\(\) error {\s+if m.Label == "" {\s+return errors.Required\("label", "body"\)`,
+				},
+				contains: []string{
+					"type PetOwner struct",
+					"type PetOwnerAddress struct",
+					"type PetToysItems0 struct",
+					// every struct validates itself and the parents nest the failures under the property
+					"func (m *PetOwnerAddress) Validate() error",
+					`validate.MinLength("city", "body", string(m.City), 2)`,
+					`validate.MaxLength("label", "body", string(m.Label), 10)`,
+					"if err := m.Address.Validate(); err != nil {\n\t\treturn errors.Nested(\"address\", err)",
+					"if err := m.Owner.Validate(); err != nil {\n\t\treturn errors.Nested(\"owner\", err)",
+					"if err := m.Toys[i].Validate(); err != nil {\n\t\t\treturn errors.Nested(\"toys.\"+strconv.Itoa(i), err)",
+				},
+			},
+		},
+	},
+	{
+		name: "allOf",
+		doc: definitions + `
+  Base:
+    required: [id]
+    properties:
+      id: {type: string, minLength: 3}
+  Derived:
+    allOf:
+      - $ref: "#/definitions/Base"
+      - properties:
+          extra: {type: integer, maximum: 9}
+    properties:
+      note: {type: string, maxLength: 5}
+`,
+		files: map[string]expected{
+			"models/derived.go": {
+				matches: []string{
+					// the members are embedded, the inline one as a struct of its own
+					`type Derived struct {\s+Base\s+DerivedAllOf1\s+Note\s+string\s+`,
+					// every member validates itself along with the properties
+					`(?s)func \(m \*Derived\) Validate\(\) error {\s+if err := m.Base.Validate\(\); err != nil {.*` +
+						`if err := m.DerivedAllOf1.Validate\(\); err != nil {.*if err := m.validateNote\(\); err != nil {`,
+				},
+				contains: []string{
+					"type DerivedAllOf1 struct",
+					`validate.Maximum("extra", "body", float64(m.Extra), 9, false)`,
+					// the members and the properties are read from and written to the same object
+					"json.Unmarshal(raw, &m.Base)",
+					"json.Unmarshal(raw, &m.DerivedAllOf1)",
+					"m.Note = props.Note",
+					"[]interface{}{m.Base, m.DerivedAllOf1, props}",
+					"return swag.ConcatJSON(parts...), nil",
+				},
+			},
+			"models/base.go": {
+				contains: []string{`validate.MinLength("id", "body", string(m.Id), 3)`},
+			},
+		},
+	},
+	{
+		name: "discriminator",
+		doc: `
+swagger: "2.0"
+info: {title: restapi, version: "1.0"}
+paths:
+  /events:
+    post:
+      operationId: addEvent
+      tags: [events]
+      parameters:
+        - {name: body, in: body, schema: {$ref: "#/definitions/Event"}}
+      responses:
+        default: {description: added}
+definitions:
+  Event:
+    type: object
+    discriminator: kind
+    required: [kind]
+    properties:
+      kind: {type: string}
+      at: {type: string}
+  Click:
+    allOf:
+      - $ref: "#/definitions/Event"
+      - properties: {x: {type: integer, minimum: 0}}
+  Log:
+    properties:
+      last: {$ref: "#/definitions/Event"}
+      events: {type: array, items: {$ref: "#/definitions/Event"}}
+      byName: {type: object, additionalProperties: {$ref: "#/definitions/Event"}}
+`,
+		operations: true,
+		files: map[string]expected{
+			// the base definition is an interface, its properties but the discriminator go to a struct
+			"models/event.go": {
+				matches: []string{
+					`type Event interface {\s+Kind\(\) string\s+Validate\(\) error\s+}`,
+					`type EventBase struct {\s+At\s+string\s+`,
+					`case "Click":\s+m = new\(Click\)`,
+				},
+				contains: []string{
+					"func UnmarshalEvent(raw []byte) (Event, error)",
+					`return nil, errors.Required("kind", "body")`,
+					`errors.EnumFail("kind", "body", base.Kind, []interface{}{"Click"})`,
+					"func UnmarshalEventSlice(raw []byte) ([]Event, error)",
+					"func UnmarshalEventMap(raw []byte) (map[string]Event, error)",
+				},
+			},
+			// a subtype embeds the base struct and writes its discriminator
+			"models/click.go": {
+				matches: []string{
+					`type Click struct {\s+EventBase\s+ClickAllOf1\s+}`,
+					`func \(m Click\) Kind\(\) string {\s+return "Click"`,
+				},
+				contains: []string{`[]interface{}{map[string]string{"kind": m.Kind()}, m.EventBase, m.ClickAllOf1}`},
+			},
+			// the properties holding the base type are read by the functions picking the models
+			"models/log.go": {
+				matches: []string{
+					`Last\s+Event\s+`,
+					`Events\s+\[\]Event\s+`,
+					`ByName\s+map\[string\]Event\s+`,
+				},
+				contains: []string{
+					"v, err := UnmarshalEvent(props.Last)",
+					"v, err := UnmarshalEventSlice(props.Events)",
+					"v, err := UnmarshalEventMap(props.ByName)",
+					`return errors.Nested("byName."+k, err)`,
+				},
+			},
+			// and so is a body, the discriminator failures are served as they are
+			"restapi.go": {
+				contains: []string{"body, err := models.UnmarshalEvent(raw)"},
+				matches: []string{
+					`switch err.\(type\) {\s+case \*errors.Validation, \*errors.CompositeError:\s+errors.ServeError\(c, err\)\s+` +
+						`default:\s+errors.ServeError\(c, errors.ParseError\("body", "body", "", err\)\)`,
+				},
+			},
+		},
+	},
+	{
+		name: "additional properties",
+		doc: definitions + `
+  Pet:
+    properties:
+      labels:
+        type: object
+        minProperties: 1
+        additionalProperties: {type: string, maxLength: 4}
+      extras:
+        type: object
+        properties:
+          a: {type: string}
+        additionalProperties: {type: string, maxLength: 4, enum: [x, yy]}
+  Labels:
+    type: object
+    maxProperties: 3
+    additionalProperties: {type: integer, maximum: 3}
+`,
+		files: map[string]expected{
+			"models/pet.go": {
+				matches: []string{
+					`Labels\s+map\[string\]string\s+`,
+					`Extras\s+\*PetExtras\s+`,
+					`AdditionalProperties\s+map\[string\]string\s+` + "`json:\"-\"`",
+				},
+				contains: []string{
+					`validate.MinProperties("labels", "body", int64(len(m.Labels)), 1)`,
+					// the keys of a map property and the ones of a catch-all are both named after the
+					// path to their object, labels.b and extras.b once the parent nests the struct
+					`validate.MaxLength("labels."+k, "body", v, 4)`,
+					"for k, v := range m.AdditionalProperties {",
+					`validate.MaxLength(k, "body", v, 4)`,
+					`m.validateAdditionalPropertiesValueEnum(k, "body", v)`,
+					`return errors.Nested("extras", err)`,
+				},
+			},
+			"models/labels.go": {
+				contains: []string{
+					"type Labels map[string]int64",
+					`validate.MaxProperties("", "body", int64(len(*m)), 3)`,
+					`validate.Maximum(k, "body", float64(v), 3, false)`,
+				},
+			},
+		},
+	},
+	{
+		name: "closed allOf",
+		doc: definitions + `
+  Strict:
+    additionalProperties: false
+    properties:
+      a: {type: string}
+  StrictChild:
+    allOf:
+      - $ref: "#/definitions/Strict"
+      - properties: {b: {type: string}}
+    properties:
+      c: {type: string}
+  StrictGrandChild:
+    allOf:
+      - $ref: "#/definitions/StrictChild"
+      - properties: {d: {type: string}}
+`,
+		files: map[string]expected{
+			"models/strict.go": {
+				contains: []string{"unknownProperties []string", `delete(all, "a")`},
+			},
+			// the closed member would reject the properties of the others, the composition
+			// rejects the ones none of its members declares instead
+			"models/strict_child.go": {
+				matches: []string{
+					`type StrictChild struct {\s+Strict\s+StrictChildAllOf1\s+C\s+string\s+` + "`json:\"c,omitempty\"`" + `\s+unknownProperties \[\]string\s+}`,
+					`m.Strict.unknownProperties = nil\s+var all map\[string\]json.RawMessage`,
+					`delete\(all, "a"\)\s+delete\(all, "b"\)\s+delete\(all, "c"\)\s+m.unknownProperties = nil`,
+					`if err := m.validateAdditionalProperties\(\); err != nil {`,
+				},
+				contains: []string{`errors.PropertyNotAllowed("", "body", k)`},
+			},
+			"models/strict_grand_child.go": {
+				contains: []string{"m.StrictChild.unknownProperties = nil"},
+				matches:  []string{`delete\(all, "a"\)\s+delete\(all, "b"\)\s+delete\(all, "c"\)\s+delete\(all, "d"\)`},
+			},
+		},
+	},
+	{
+		name: "arrays",
+		doc: definitions + `
+  Pet:
+    required: [photoUrls]
+    properties:
+      photoUrls:
+        type: array
+        minItems: 1
+        maxItems: 5
+        uniqueItems: true
+        items: {type: string, minLength: 3, enum: [aaa, bbb]}
+      grid:
+        type: array
+        minItems: 2
+        items: {type: array, maxItems: 2, items: {type: integer, maximum: 9}}
+      tags: {type: array, items: {$ref: "#/definitions/Tag"}}
+  Tag:
+    required: [name]
+    properties:
+      name: {type: string, pattern: "^[a-z]+$"}
+`,
+		files: map[string]expected{
+			"models/pet.go": {
+				matches: []string{
+					// the size and the uniqueness of the array, a missing optional one isn't checked
+					`func \(m \*Pet\) validatePhotoUrls\(\) error {\s+if err := validate.MinItems\("photoUrls", "body", int64\(len\(m.PhotoUrls\)\), 1\)`,
+					`if m.Grid != nil {\s+if err := validate.MinItems\("grid", "body", int64\(len\(m.Grid\)\), 2\)`,
+					`if m.PhotoUrls == nil {\s+return errors.Required\("photoUrls", "body"\)`,
+				},
+				contains: []string{
+					`validate.MaxItems("photoUrls", "body", int64(len(m.PhotoUrls)), 5)`,
+					`validate.UniqueItems("photoUrls", "body", m.PhotoUrls)`,
+					// every item is validated and named after its index
+					`validate.MinLength("photoUrls."+strconv.Itoa(i), "body", m.PhotoUrls[i], 3)`,
+					`m.validatePhotoUrlsItemsEnum("photoUrls."+strconv.Itoa(i), "body", m.PhotoUrls[i])`,
+					`validate.MaxItems("grid."+strconv.Itoa(i), "body", int64(len(m.Grid[i])), 2)`,
+					`validate.Maximum("grid."+strconv.Itoa(i)+"."+strconv.Itoa(ii), "body", float64(m.Grid[i][ii]), 9, false)`,
+					`return errors.Nested("tags."+strconv.Itoa(i), err)`,
+				},
+			},
+			"models/tag.go": {
+				contains: []string{"validate.Pattern(\"name\", \"body\", string(m.Name), `^[a-z]+$`)"},
+				matches:  []string{`if m.Name == "" {\s+return errors.Required\("name", "body"\)`},
+			},
+		},
+	},
+	{
+		name: "optional models",
+		doc: definitions + `
+  Pet:
+    required: [owner]
+    properties:
+      category: {$ref: "#/definitions/Category"}
+      owner: {$ref: "#/definitions/Category"}
+      labels: {$ref: "#/definitions/Labels"}
+      tags: {type: array, items: {$ref: "#/definitions/Category"}}
+  Category:
+    required: [name]
+    properties:
+      name: {type: string, minLength: 1}
+  Labels:
+    type: object
+    minProperties: 1
+    additionalProperties: {type: string}
+`,
+		files: map[string]expected{
+			"models/pet.go": {
+				matches: []string{
+					// a missing optional model is nil, so "category": {} is validated and fails on its name
+					`Category\s+\*Category\s+` + "`json:\"category,omitempty\"`",
+					`func \(m \*Pet\) validateCategory\(\) error {\s+if m.Category == nil {\s+return nil\s+}\s+if err := m.Category.Validate\(\); err != nil {\s+return errors.Nested\("category", err\)`,
+					// a required model is always validated, the maps and the items are left as they are
+					`Owner\s+Category\s+` + "`json:\"owner\" binding:\"required\"`",
+					`func \(m \*Pet\) validateOwner\(\) error {\s+if err := m.Owner.Validate\(\); err != nil {`,
+					`Labels\s+Labels\s+`,
+					`func \(m \*Pet\) validateLabels\(\) error {\s+if m.Labels == nil {`,
+					`Tags\s+\[\]Category\s+`,
+				},
+			},
+			"models/category.go": {
+				matches:  []string{`Name\s+string\s+` + "`json:\"name\" binding:\"required\"`"},
+				contains: []string{`validate.MinLength("name", "body", string(m.Name), 1)`},
+			},
+		},
+	},
+}
+
+func TestGenerateDefinition(t *testing.T) {
+	for _, c := range modelCases {
+		t.Run(c.name, func(t *testing.T) {
+			dir := tempGenDir(t)
+			defer os.RemoveAll(dir)
+			opts := genOpts(t, dir, c.doc)
+			if c.setup != nil {
+				c.setup(&opts)
 			}
-		}
-	}
-}
 
-func TestGenerateModel_Statix(t *testing.T) {
-	specDoc, err := spec.Load("../fixtures/codegen/todolist.models.yml")
-	if assert.NoError(t, err) {
-		definitions := specDoc.Spec().Definitions
-		k := "Statix"
-		schema := definitions[k]
-		genModel, err := makeGenDefinition(k, "models", schema, specDoc)
-		if assert.NoError(t, err) {
-			buf := bytes.NewBuffer(nil)
-			err := modelTemplate.Execute(buf, genModel)
-			if assert.NoError(t, err) {
-				ff, err := formatGoFile("statix.go", buf.Bytes())
-				if assert.NoError(t, err) {
-					res := string(ff)
-					assertInCode(t, "type Statix [][][]StatixItems0", res)
-					assertInCode(t, "type StatixItems0 struct {", res)
-					assertInCode(t, "Points []int64 `json:\"points,omitempty\"`", res)
-				}
+			if !assert.NoError(t, GenerateDefinition(true, true, opts)) {
+				return
 			}
-		}
-	}
-}
-
-func TestGenerateModel_WithItems(t *testing.T) {
-	tt := templateTest{t, modelTemplate.Lookup("schema")}
-	specDoc, err := spec.Load("../fixtures/codegen/todolist.models.yml")
-	if assert.NoError(t, err) {
-		definitions := specDoc.Spec().Definitions
-		schema := definitions["WithItems"]
-		genModel, err := makeGenDefinition("WithItems", "models", schema, specDoc)
-		if assert.NoError(t, err) {
-			assert.Nil(t, genModel.Items)
-			assert.True(t, genModel.IsComplexObject)
-			prop := getDefinitionProperty(genModel, "tags")
-			assert.NotNil(t, prop.Items)
-			assert.True(t, prop.IsArray)
-			assert.False(t, prop.IsComplexObject)
-			buf := bytes.NewBuffer(nil)
-			err := tt.template.Execute(buf, genModel)
-			if assert.NoError(t, err) {
-				res := buf.String()
-				assertInCode(t, "type WithItems struct {", res)
-				assertInCode(t, "Tags []string `json:\"tags,omitempty\"`", res)
+			if c.operations && !assert.NoError(t, GenerateServerOperation(true, true, opts)) {
+				return
 			}
-		}
+			checkGenerated(t, opts, c.files)
+		})
 	}
 }
-
-func TestGenerateModel_WithComplexItems(t *testing.T) {
-	specDoc, err := spec.Load("../fixtures/codegen/todolist.models.yml")
-	if assert.NoError(t, err) {
-		definitions := specDoc.Spec().Definitions
-		k := "WithComplexItems"
-		schema := definitions[k]
-		genModel, err := makeGenDefinition(k, "models", schema, specDoc)
-		if assert.NoError(t, err) {
-			assert.Nil(t, genModel.Items)
-			assert.True(t, genModel.IsComplexObject)
-			prop := getDefinitionProperty(genModel, "tags")
-			assert.NotNil(t, prop.Items)
-			assert.True(t, prop.IsArray)
-			assert.False(t, prop.IsComplexObject)
-			buf := bytes.NewBuffer(nil)
-			err := modelTemplate.Execute(buf, genModel)
-			if assert.NoError(t, err) {
-				b, err := formatGoFile("with_complex_items.go", buf.Bytes())
-				if assert.NoError(t, err) {
-					res := string(b)
-					assertInCode(t, "type WithComplexItems struct {", res)
-					assertInCode(t, "type WithComplexItemsTagsItems0 struct {", res)
-					assertInCode(t, "Tags []WithComplexItemsTagsItems0 `json:\"tags,omitempty\"`", res)
-				}
-			}
-		}
-	}
-}
-
-func TestGenerateModel_WithItemsAndAdditional(t *testing.T) {
-	specDoc, err := spec.Load("../fixtures/codegen/todolist.models.yml")
-	if assert.NoError(t, err) {
-		definitions := specDoc.Spec().Definitions
-		k := "WithItemsAndAdditional"
-		schema := definitions[k]
-		genModel, err := makeGenDefinition(k, "models", schema, specDoc)
-		if assert.NoError(t, err) {
-			assert.Nil(t, genModel.Items)
-			assert.True(t, genModel.IsComplexObject)
-			prop := getDefinitionProperty(genModel, "tags")
-			assert.True(t, prop.IsComplexObject)
-			buf := bytes.NewBuffer(nil)
-			err := modelTemplate.Execute(buf, genModel)
-			if assert.NoError(t, err) {
-				b, err := formatGoFile("with_complex_items.go", buf.Bytes())
-				if assert.NoError(t, err) {
-					res := string(b)
-					assertInCode(t, "type "+k+" struct {", res)
-					assertInCode(t, "type "+k+"TagsTuple0 struct {", res)
-					// this would fail if it accepts additionalItems because it would come out as []interface{}
-					assertInCode(t, "Tags "+k+"TagsTuple0 `json:\"tags,omitempty\"`", res)
-					assertInCode(t, "P0 string `json:\"-\"`", res)
-					assertInCode(t, k+"TagsTuple0Items []interface{} `json:\"-\"`", res)
-				}
-			}
-		}
-	}
-}
-
-func TestGenerateModel_WithItemsAndAdditional2(t *testing.T) {
-	specDoc, err := spec.Load("../fixtures/codegen/todolist.models.yml")
-	if assert.NoError(t, err) {
-		definitions := specDoc.Spec().Definitions
-		k := "WithItemsAndAdditional2"
-		schema := definitions[k]
-		genModel, err := makeGenDefinition(k, "models", schema, specDoc)
-		if assert.NoError(t, err) {
-			assert.Nil(t, genModel.Items)
-			assert.True(t, genModel.IsComplexObject)
-			prop := getDefinitionProperty(genModel, "tags")
-			assert.True(t, prop.IsComplexObject)
-			buf := bytes.NewBuffer(nil)
-			err := modelTemplate.Execute(buf, genModel)
-			if assert.NoError(t, err) {
-				b, err := formatGoFile("with_complex_items.go", buf.Bytes())
-				if assert.NoError(t, err) {
-					res := string(b)
-					assertInCode(t, "type "+k+" struct {", res)
-					assertInCode(t, "type "+k+"TagsTuple0 struct {", res)
-					// this would fail if it accepts additionalItems because it would come out as []interface{}
-					assertInCode(t, "P0 string `json:\"-\"`", res)
-					assertInCode(t, "Tags "+k+"TagsTuple0 `json:\"tags,omitempty\"`", res)
-					assertInCode(t, k+"TagsTuple0Items []int32 `json:\"-\"`", res)
-
-				}
-			}
-		}
-	}
-}
-
-func TestGenerateModel_WithComplexAdditional(t *testing.T) {
-	specDoc, err := spec.Load("../fixtures/codegen/todolist.models.yml")
-	if assert.NoError(t, err) {
-		definitions := specDoc.Spec().Definitions
-		k := "WithComplexAdditional"
-		schema := definitions[k]
-		genModel, err := makeGenDefinition(k, "models", schema, specDoc)
-		if assert.NoError(t, err) {
-			assert.Nil(t, genModel.Items)
-			assert.True(t, genModel.IsComplexObject)
-			prop := getDefinitionProperty(genModel, "tags")
-			assert.True(t, prop.IsComplexObject)
-			buf := bytes.NewBuffer(nil)
-			err := modelTemplate.Execute(buf, genModel)
-			if assert.NoError(t, err) {
-				b, err := formatGoFile("with_complex_additional.go", buf.Bytes())
-				if assert.NoError(t, err) {
-					res := string(b)
-					assertInCode(t, "type WithComplexAdditional struct {", res)
-					assertInCode(t, "type WithComplexAdditionalTagsTuple0 struct {", res)
-					assertInCode(t, "Tags WithComplexAdditionalTagsTuple0 `json:\"tags,omitempty\"`", res)
-					assertInCode(t, "P0 string `json:\"-\"`", res)
-					assertInCode(t, "WithComplexAdditionalTagsTuple0Items []WithComplexAdditionalTagsItems `json:\"-\"`", res)
-				}
-			}
-		}
-	}
-}
-
-func TestGenerateModel_SimpleTuple(t *testing.T) {
-	tt := templateTest{t, modelTemplate}
-	specDoc, err := spec.Load("../fixtures/codegen/todolist.models.yml")
-	if assert.NoError(t, err) {
-		definitions := specDoc.Spec().Definitions
-		k := "SimpleTuple"
-		schema := definitions[k]
-		genModel, err := makeGenDefinition(k, "models", schema, specDoc)
-		if assert.NoError(t, err) && assert.Empty(t, genModel.ExtraSchemas) {
-			assert.True(t, genModel.IsTuple)
-			assert.False(t, genModel.IsComplexObject)
-			assert.False(t, genModel.IsArray)
-			assert.False(t, genModel.IsAnonymous)
-			assert.Equal(t, k, genModel.Name)
-			assert.Equal(t, k, genModel.GoType)
-			assert.Len(t, genModel.Properties, 5)
-			buf := bytes.NewBuffer(nil)
-			tt.template.Execute(buf, genModel)
-			res := buf.String()
-			assertInCode(t, "swagger:model "+k, res)
-			assertInCode(t, "type "+k+" struct {", res)
-			assertInCode(t, "P0 int64 `json:\"-\"`", res)
-			assertInCode(t, "P1 string `json:\"-\"`", res)
-			assertInCode(t, "P2 strfmt.DateTime `json:\"-\"`", res)
-			assertInCode(t, "P3 Notable `json:\"-\"`", res)
-			assertInCode(t, "P4 *Notable `json:\"-\"`", res)
-			assertInCode(t, k+") UnmarshalJSON", res)
-			assertInCode(t, k+") MarshalJSON", res)
-			assertInCode(t, "json.Marshal(data)", res)
-			assert.NotRegexp(t, regexp.MustCompile("lastIndex"), res)
-
-			for i, p := range genModel.Properties {
-				r := "m.P" + strconv.Itoa(i)
-				if !p.IsNullable {
-					r = "&" + r
-				}
-				assertInCode(t, "json.Unmarshal(stage1["+strconv.Itoa(i)+"], "+r+")", res)
-				assertInCode(t, "P"+strconv.Itoa(i)+",", res)
-			}
-		}
-	}
-}
-
-func TestGenerateModel_TupleWithExtra(t *testing.T) {
-	specDoc, err := spec.Load("../fixtures/codegen/todolist.models.yml")
-	if assert.NoError(t, err) {
-		definitions := specDoc.Spec().Definitions
-		k := "TupleWithExtra"
-		schema := definitions[k]
-		genModel, err := makeGenDefinition(k, "models", schema, specDoc)
-		if assert.NoError(t, err) && assert.Empty(t, genModel.ExtraSchemas) {
-			assert.True(t, genModel.IsTuple)
-			assert.False(t, genModel.IsComplexObject)
-			assert.False(t, genModel.IsArray)
-			assert.False(t, genModel.IsAnonymous)
-			assert.True(t, genModel.HasAdditionalItems)
-			assert.NotNil(t, genModel.AdditionalItems)
-			assert.Equal(t, k, genModel.Name)
-			assert.Equal(t, k, genModel.GoType)
-			assert.Len(t, genModel.Properties, 4)
-			buf := bytes.NewBuffer(nil)
-			err := modelTemplate.Execute(buf, genModel)
-			if assert.NoError(t, err) {
-				ff, err := formatGoFile("tuple_with_extra.go", buf.Bytes())
-				if assert.NoError(t, err) {
-					res := string(ff)
-					assertInCode(t, "swagger:model "+k, res)
-					assertInCode(t, "type "+k+" struct {", res)
-					assertInCode(t, "P0 int64 `json:\"-\"`", res)
-					assertInCode(t, "P1 string `json:\"-\"`", res)
-					assertInCode(t, "P2 strfmt.DateTime `json:\"-\"`", res)
-					assertInCode(t, "P3 Notable `json:\"-\"`", res)
-					assertInCode(t, k+"Items []float64 `json:\"-\"`", res)
-					assertInCode(t, k+") UnmarshalJSON", res)
-					assertInCode(t, k+") MarshalJSON", res)
-
-					for i, p := range genModel.Properties {
-						r := "m.P" + strconv.Itoa(i)
-						if !p.IsNullable {
-							r = "&" + r
-						}
-						assertInCode(t, "lastIndex = "+strconv.Itoa(i), res)
-						assertInCode(t, "json.Unmarshal(stage1["+strconv.Itoa(i)+"], "+r+")", res)
-						assertInCode(t, "P"+strconv.Itoa(i)+",", res)
-					}
-					assertInCode(t, "var lastIndex int", res)
-					assertInCode(t, "var toadd float64", res)
-					assertInCode(t, "for _, val := range stage1[lastIndex+1:]", res)
-					assertInCode(t, "json.Unmarshal(val, &toadd)", res)
-					assertInCode(t, "json.Marshal(data)", res)
-					assertInCode(t, "for _, v := range m."+k+"Items", res)
-				}
-			}
-		}
-	}
-}
-
-func TestGenerateModel_TupleWithComplex(t *testing.T) {
-	specDoc, err := spec.Load("../fixtures/codegen/todolist.models.yml")
-	if assert.NoError(t, err) {
-		definitions := specDoc.Spec().Definitions
-		k := "TupleWithComplex"
-		schema := definitions[k]
-		genModel, err := makeGenDefinition(k, "models", schema, specDoc)
-		if assert.NoError(t, err) { //&& assert.Empty(t, genModel.ExtraSchemas) {
-			assert.True(t, genModel.IsTuple)
-			assert.False(t, genModel.IsComplexObject)
-			assert.False(t, genModel.IsArray)
-			assert.False(t, genModel.IsAnonymous)
-			assert.True(t, genModel.HasAdditionalItems)
-			assert.NotNil(t, genModel.AdditionalItems)
-			assert.Equal(t, k, genModel.Name)
-			assert.Equal(t, k, genModel.GoType)
-			assert.Len(t, genModel.Properties, 4)
-			buf := bytes.NewBuffer(nil)
-			err := modelTemplate.Execute(buf, genModel)
-			if assert.NoError(t, err) {
-				ff, err := formatGoFile("tuple_with_extra.go", buf.Bytes())
-				if assert.NoError(t, err) {
-					res := string(ff)
-					assertInCode(t, "swagger:model "+k, res)
-					assertInCode(t, "type "+k+" struct {", res)
-					assertInCode(t, "P0 int64 `json:\"-\"`", res)
-					assertInCode(t, "P1 string `json:\"-\"`", res)
-					assertInCode(t, "P2 strfmt.DateTime `json:\"-\"`", res)
-					assertInCode(t, "P3 Notable `json:\"-\"`", res)
-					assertInCode(t, k+"Items []TupleWithComplexItems `json:\"-\"`", res)
-					assertInCode(t, k+") UnmarshalJSON", res)
-					assertInCode(t, k+") MarshalJSON", res)
-
-					for i, p := range genModel.Properties {
-						r := "m.P" + strconv.Itoa(i)
-						if !p.IsNullable {
-							r = "&" + r
-						}
-						assertInCode(t, "lastIndex = "+strconv.Itoa(i), res)
-						assertInCode(t, "json.Unmarshal(stage1["+strconv.Itoa(i)+"], "+r+")", res)
-						assertInCode(t, "P"+strconv.Itoa(i)+",", res)
-					}
-					assertInCode(t, "var lastIndex int", res)
-					assertInCode(t, "var toadd TupleWithComplexItems", res)
-					assertInCode(t, "for _, val := range stage1[lastIndex+1:]", res)
-					assertInCode(t, "json.Unmarshal(val, &toadd)", res)
-					assertInCode(t, "json.Marshal(data)", res)
-					assertInCode(t, "for _, v := range m."+k+"Items", res)
-				}
-			}
-		}
-	}
-}
-
-func TestGenerateModel_WithTuple(t *testing.T) {
-	specDoc, err := spec.Load("../fixtures/codegen/todolist.models.yml")
-	if assert.NoError(t, err) {
-		definitions := specDoc.Spec().Definitions
-		k := "WithTuple"
-		schema := definitions[k]
-		genModel, err := makeGenDefinition(k, "models", schema, specDoc)
-		if assert.NoError(t, err) && assert.NotEmpty(t, genModel.ExtraSchemas) && assert.NotEmpty(t, genModel.Properties) {
-			assert.False(t, genModel.IsTuple)
-			assert.True(t, genModel.IsComplexObject)
-			assert.False(t, genModel.IsArray)
-			assert.False(t, genModel.IsAnonymous)
-
-			sch := genModel.ExtraSchemas[0]
-			assert.True(t, sch.IsTuple)
-			assert.False(t, sch.IsComplexObject)
-			assert.False(t, sch.IsArray)
-			assert.False(t, sch.IsAnonymous)
-			assert.Equal(t, k+"FlagsTuple0", sch.Name)
-			assert.False(t, sch.HasAdditionalItems)
-			assert.Nil(t, sch.AdditionalItems)
-
-			prop := genModel.Properties[0]
-			assert.False(t, genModel.IsTuple)
-			assert.True(t, genModel.IsComplexObject)
-			assert.False(t, prop.IsArray)
-			assert.False(t, prop.IsAnonymous)
-			assert.Equal(t, k+"FlagsTuple0", prop.GoType)
-			assert.Equal(t, "flags", prop.Name)
-			buf := bytes.NewBuffer(nil)
-			err := modelTemplate.Execute(buf, genModel)
-			if assert.NoError(t, err) {
-				ff, err := formatGoFile("with_tuple.go", buf.Bytes())
-				if assert.NoError(t, err) {
-					res := string(ff)
-					assertInCode(t, "swagger:model "+k+"Flags", res)
-					assertInCode(t, "type "+k+"FlagsTuple0 struct {", res)
-					assertInCode(t, "P0 int64 `json:\"-\"`", res)
-					assertInCode(t, "P1 string `json:\"-\"`", res)
-					assertInCode(t, k+"FlagsTuple0) UnmarshalJSON", res)
-					assertInCode(t, k+"FlagsTuple0) MarshalJSON", res)
-					assertInCode(t, "json.Marshal(data)", res)
-					assert.NotRegexp(t, regexp.MustCompile("lastIndex"), res)
-
-					for i, p := range sch.Properties {
-						r := "m.P" + strconv.Itoa(i)
-						if !p.IsNullable {
-							r = "&" + r
-						}
-						assertInCode(t, "json.Unmarshal(stage1["+strconv.Itoa(i)+"], "+r+")", res)
-						assertInCode(t, "P"+strconv.Itoa(i)+",", res)
-					}
-				}
-			}
-		}
-	}
-}
-
-func TestGenerateModel_WithTupleWithExtra(t *testing.T) {
-	tt := templateTest{t, modelTemplate}
-	specDoc, err := spec.Load("../fixtures/codegen/todolist.models.yml")
-	if assert.NoError(t, err) {
-		definitions := specDoc.Spec().Definitions
-		k := "WithTupleWithExtra"
-		schema := definitions[k]
-		genModel, err := makeGenDefinition(k, "models", schema, specDoc)
-		if assert.NoError(t, err) && assert.NotEmpty(t, genModel.ExtraSchemas) && assert.NotEmpty(t, genModel.Properties) {
-			assert.False(t, genModel.IsTuple)
-			assert.True(t, genModel.IsComplexObject)
-			assert.False(t, genModel.IsArray)
-			assert.False(t, genModel.IsAnonymous)
-
-			sch := genModel.ExtraSchemas[0]
-			assert.True(t, sch.IsTuple)
-			assert.False(t, sch.IsComplexObject)
-			assert.False(t, sch.IsArray)
-			assert.False(t, sch.IsAnonymous)
-			assert.Equal(t, k+"FlagsTuple0", sch.Name)
-			assert.True(t, sch.HasAdditionalItems)
-			assert.NotEmpty(t, sch.AdditionalItems)
-
-			prop := genModel.Properties[0]
-			assert.False(t, genModel.IsTuple)
-			assert.True(t, genModel.IsComplexObject)
-			assert.False(t, prop.IsArray)
-			assert.False(t, prop.IsAnonymous)
-			assert.Equal(t, k+"FlagsTuple0", prop.GoType)
-			assert.Equal(t, "flags", prop.Name)
-			buf := bytes.NewBuffer(nil)
-			err := tt.template.Execute(buf, genModel)
-			if assert.NoError(t, err) {
-				ff, err := formatGoFile("with_tuple.go", buf.Bytes())
-				if assert.NoError(t, err) {
-					res := string(ff)
-					assertInCode(t, "swagger:model "+k+"Flags", res)
-					assertInCode(t, "type "+k+"FlagsTuple0 struct {", res)
-					assertInCode(t, "P0 int64 `json:\"-\"`", res)
-					assertInCode(t, "P1 string `json:\"-\"`", res)
-					assertInCode(t, k+"FlagsTuple0Items []float32 `json:\"-\"`", res)
-					assertInCode(t, k+"FlagsTuple0) UnmarshalJSON", res)
-					assertInCode(t, k+"FlagsTuple0) MarshalJSON", res)
-					assertInCode(t, "json.Marshal(data)", res)
-
-					for i, p := range sch.Properties {
-						r := "m.P" + strconv.Itoa(i)
-						if !p.IsNullable {
-							r = "&" + r
-						}
-						assertInCode(t, "lastIndex = "+strconv.Itoa(i), res)
-						assertInCode(t, "json.Unmarshal(stage1["+strconv.Itoa(i)+"], "+r+")", res)
-						assertInCode(t, "P"+strconv.Itoa(i)+",", res)
-					}
-
-					assertInCode(t, "var lastIndex int", res)
-					assertInCode(t, "var toadd float32", res)
-					assertInCode(t, "for _, val := range stage1[lastIndex+1:]", res)
-					assertInCode(t, "json.Unmarshal(val, &toadd)", res)
-					assertInCode(t, "json.Marshal(data)", res)
-					assertInCode(t, "for _, v := range m."+k+"FlagsTuple0Items", res)
-				}
-			}
-		}
-	}
-}
-
-func TestGenerateModel_WithAllOf(t *testing.T) {
-	specDoc, err := spec.Load("../fixtures/codegen/todolist.models.yml")
-	if assert.NoError(t, err) {
-		definitions := specDoc.Spec().Definitions
-		schema := definitions["WithAllOf"]
-		genModel, err := makeGenDefinition("WithAllOf", "models", schema, specDoc)
-		if assert.NoError(t, err) {
-			assert.Len(t, genModel.AllOf, 7)
-			assert.True(t, genModel.AllOf[1].HasAdditionalProperties)
-			assert.True(t, genModel.IsComplexObject)
-			assert.Equal(t, "WithAllOf", genModel.Name)
-			assert.Equal(t, "WithAllOf", genModel.GoType)
-			buf := bytes.NewBuffer(nil)
-			err := modelTemplate.Execute(buf, genModel)
-			if assert.NoError(t, err) {
-				ct, err := formatGoFile("all_of_schema.go", buf.Bytes())
-				if assert.NoError(t, err) {
-					res := string(ct)
-					//fmt.Println(res)
-					assertInCode(t, "type WithAllOf struct {", res)
-					assertInCode(t, "type WithAllOfAO2P2 struct {", res)
-					assertInCode(t, "type WithAllOfAO3P3 struct {", res)
-					assertInCode(t, "type WithAllOfParamsAnon struct {", res)
-					assertInCode(t, "type WithAllOfAO4Tuple4 struct {", res)
-					assertInCode(t, "type WithAllOfAO5Tuple5 struct {", res)
-					assertInCode(t, "Notable", res)
-					assertInCode(t, "Title string `json:\"title,omitempty\"`", res)
-					assertInCode(t, "Body string `json:\"body,omitempty\"`", res)
-					assertInCode(t, "Name string `json:\"name,omitempty\"`", res)
-					assertInCode(t, "P0 float32 `json:\"-\"`", res)
-					assertInCode(t, "P0 float64 `json:\"-\"`", res)
-					assertInCode(t, "P1 strfmt.DateTime `json:\"-\"`", res)
-					assertInCode(t, "P1 strfmt.Date `json:\"-\"`", res)
-					assertInCode(t, "Opinion string `json:\"opinion,omitempty\"`", res)
-					assertInCode(t, "WithAllOfAO5Tuple5Items []strfmt.Password `json:\"-\"`", res)
-					assertInCode(t, "AO1 map[string]int32 `json:\"-\"`", res)
-					assertInCode(t, "WithAllOfAO2P2 map[string]int64 `json:\"-\"`", res)
-				}
-			}
-		}
-	}
-}
-
-func findProperty(properties []GenSchema, name string) *GenSchema {
-	for _, p := range properties {
-		if p.Name == name {
-			return &p
-		}
-	}
-	return nil
-}
-
-func getDefinitionProperty(genModel *GenDefinition, name string) *GenSchema {
-	return findProperty(genModel.Properties, name)
-}
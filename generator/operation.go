@@ -18,22 +18,42 @@ import (
 // Allows for specifying a list of tags to include only certain tags for the generation
 func GenerateServerOperation(includeHandler, includeParameters bool, opts GenOpts) error {
 	// Load the spec
-	_, specDoc, err := loadSpec(opts.Spec)
+	_, specDoc, err := loadFilteredSpec(opts)
 	if err != nil {
 		return err
 	}
 
+	files, flush := opts.fileSet()
+	pkgs := opts.packages()
 	buf := bytes.NewBuffer(nil)
-	codeGen.generateOperations(buf, specDoc)
+	if err := codeGen.generateOperations(buf, specDoc, pkgs); err != nil {
+		return err
+	}
+	fp := filepath.Join(opts.Target, pkgs.api)
+	if err := files.AddGoFile(fp, pkgs.api, buf.Bytes()); err != nil {
+		return err
+	}
 	log.Println("generated operation examples")
-	fp := filepath.Join(opts.Target, "operations")
-	writeToFile(fp, "operations", buf.Bytes())
-	
+
 	buf.Reset()
-	codeGen.generateHandlers(buf, specDoc)
+	if err := codeGen.generateHandlers(buf, specDoc, pkgs); err != nil {
+		return err
+	}
+	server := opts.ServerPackage
+	if server == "" {
+		server = "restapi"
+	}
+	if err := files.AddGoFile(opts.Target, server, buf.Bytes()); err != nil {
+		return err
+	}
 	log.Println("generated gin restful APIs")
-	return writeToFile(opts.Target, "restapi", buf.Bytes())
-	
+
+	if flush {
+		_, err := files.Write()
+		return err
+	}
+	return nil
+
 /*
 	if len(operationNames) == 0 {
 		operationNames = specDoc.OperationIDs()
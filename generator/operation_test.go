@@ -1,127 +0,0 @@
-package generator
-
-import (
-	"errors"
-	"testing"
-
-	"github.com/aiyi/swagger-gin/spec"
-	"github.com/kr/pretty"
-	"github.com/stretchr/testify/assert"
-)
-
-func TestMakeResponseHeader(t *testing.T) {
-	b, err := opBuilder("getTasks", "")
-	if assert.NoError(t, err) {
-		hdr := findResponseHeader(&b.Operation, 200, "X-Rate-Limit")
-		gh := b.MakeHeader("a", "X-Rate-Limit", *hdr)
-		assert.True(t, gh.IsPrimitive)
-		assert.Equal(t, "int32", gh.GoType)
-		assert.Equal(t, "X-Rate-Limit", gh.Name)
-	}
-}
-
-func TestMakeResponse(t *testing.T) {
-	b, err := opBuilder("getTasks", "")
-	if assert.NoError(t, err) {
-		resolver := &typeResolver{ModelsPackage: b.ModelsPackage, Doc: b.Doc}
-		gO, err := b.MakeResponse("a", "getTasksSuccess", true, resolver, b.Operation.Responses.StatusCodeResponses[200])
-		if assert.NoError(t, err) {
-			assert.Len(t, gO.Headers, 2)
-			assert.NotNil(t, gO.Schema)
-			assert.True(t, gO.Schema.IsArray)
-			assert.NotNil(t, gO.Schema.Items)
-			assert.False(t, gO.Schema.IsAnonymous)
-			assert.Equal(t, "[]models.Task", gO.Schema.GoType)
-		}
-	}
-}
-
-func TestMakeOperationParam(t *testing.T) {
-	b, err := opBuilder("getTasks", "")
-	if assert.NoError(t, err) {
-		resolver := &typeResolver{ModelsPackage: b.ModelsPackage, Doc: b.Doc}
-		gO, err := b.MakeParameter("a", resolver, b.Operation.Parameters[0])
-		if assert.NoError(t, err) {
-			assert.Equal(t, "size", gO.Name)
-			assert.True(t, gO.IsPrimitive)
-		}
-	}
-}
-
-func TestMakeOperationParamItem(t *testing.T) {
-	b, err := opBuilder("arrayQueryParams", "../fixtures/codegen/todolist.arrayquery.yml")
-	if assert.NoError(t, err) {
-		resolver := &typeResolver{ModelsPackage: b.ModelsPackage, Doc: b.Doc}
-		gO, err := b.MakeParameterItem("a", "siString", "i", "siString", "a.SiString", resolver, b.Operation.Parameters[1].Items, nil)
-		if assert.NoError(t, err) {
-			assert.Nil(t, gO.Parent)
-			assert.True(t, gO.IsPrimitive)
-		}
-	}
-}
-
-func TestMakeOperation(t *testing.T) {
-	b, err := opBuilder("getTasks", "")
-	if assert.NoError(t, err) {
-		gO, err := b.MakeOperation()
-		if assert.NoError(t, err) {
-			pretty.Println(gO)
-			assert.Equal(t, "getTasks", gO.Name)
-			assert.Len(t, gO.Params, 2)
-			assert.Len(t, gO.Responses, 1)
-			assert.NotNil(t, gO.DefaultResponse)
-			assert.NotNil(t, gO.SuccessResponse)
-		}
-
-		// TODO: validate rendering of a complex operation
-	}
-}
-
-func opBuilder(name, fname string) (codeGenOpBuilder, error) {
-	if fname == "" {
-		fname = "../fixtures/codegen/todolist.simple.yml"
-	}
-
-	specDoc, err := spec.Load(fname)
-	if err != nil {
-		return codeGenOpBuilder{}, err
-	}
-
-	op, ok := specDoc.OperationForName(name)
-	if !ok {
-		return codeGenOpBuilder{}, errors.New("No operation could be found for " + name)
-	}
-
-	return codeGenOpBuilder{
-		Name:          name,
-		APIPackage:    "restapi",
-		ModelsPackage: "models",
-		Principal:     "models.User",
-		Target:        ".",
-		Operation:     *op,
-		Doc:           specDoc,
-		Authed:        false,
-		ExtraSchemas:  make(map[string]GenSchema),
-	}, nil
-}
-
-func findResponseHeader(op *spec.Operation, code int, name string) *spec.Header {
-	resp := op.Responses.Default
-	if code > 0 {
-		bb, ok := op.Responses.StatusCodeResponses[code]
-		if ok {
-			resp = &bb
-		}
-	}
-
-	if resp == nil {
-		return nil
-	}
-
-	hdr, ok := resp.Headers[name]
-	if !ok {
-		return nil
-	}
-
-	return &hdr
-}
@@ -0,0 +1,127 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+
+	"github.com/aiyi/swagger-gin/spec"
+)
+
+// GenerateService generates what turns the API packages into a runnable service:
+// the go.mod of the module, the spec served by the service and a main.go which
+// mounts the routes under the basePath of the spec, validates the requests and
+// shuts the server down gracefully. The API packages are expected in the
+// ServerPackage directory of the target, an existing go.mod is left alone.
+func GenerateService(module string, opts GenOpts) error {
+	_, specDoc, err := loadFilteredSpec(opts)
+	if err != nil {
+		return err
+	}
+
+	files, flush := opts.fileSet()
+	if _, err := os.Stat(filepath.Join(opts.Target, "go.mod")); os.IsNotExist(err) {
+		files.Add(filepath.Join(opts.Target, "go.mod"), []byte("module "+module+"\n\ngo 1.16\n"))
+	}
+
+	var raw bytes.Buffer
+	if err := json.Indent(&raw, specDoc.Raw(), "", "  "); err != nil {
+		return err
+	}
+	raw.WriteString("\n")
+	files.Add(filepath.Join(opts.Target, "swagger.json"), raw.Bytes())
+
+	buf := bytes.NewBuffer(nil)
+	if err := codeGen.generateMain(buf, specDoc, path.Join(module, opts.ServerPackage)); err != nil {
+		return err
+	}
+	if err := files.AddGoFile(opts.Target, "main", buf.Bytes()); err != nil {
+		return err
+	}
+	log.Println("generated the service")
+
+	if flush {
+		_, err := files.Write()
+		return err
+	}
+	return nil
+}
+
+func (g *Generator) generateMain(buf *bytes.Buffer, specDoc *spec.Document, apiPath string) error {
+	g.reset(buf)
+	if diags := checkOperations(specDoc); len(diags) > 0 {
+		return diags
+	}
+	groups := g.routeGroups(specDoc.AllPaths())
+
+	g.p("package main")
+	g.p()
+	g.p("import (")
+	g.p("	\"context\"")
+	g.p("	_ \"embed\"")
+	g.p("	\"flag\"")
+	g.p("	\"log\"")
+	g.p("	\"net/http\"")
+	g.p("	\"os\"")
+	g.p("	\"os/signal\"")
+	g.p("	\"syscall\"")
+	g.p("	\"time\"")
+	g.p()
+	g.p("	\"github.com/aiyi/swagger-gin/middleware\"")
+	g.p("	\"github.com/aiyi/swagger-gin/spec\"")
+	g.p("	\"github.com/gin-gonic/gin\"")
+	g.p()
+	g.p("	api \"", apiPath, "\"")
+	g.p(")")
+	g.p()
+	g.p("//go:embed swagger.json")
+	g.p("var swaggerJSON []byte")
+	g.p()
+	g.p("func main() {")
+	g.p("	addr := flag.String(\"addr\", \":8080\", \"the address the server listens on\")")
+	g.p("	shutdownTimeout := flag.Duration(\"shutdown-timeout\", 10*time.Second, \"how long the requests in flight are waited for on shutdown\")")
+	g.p("	flag.Parse()")
+	g.p()
+	g.p("	doc, err := spec.New(swaggerJSON, \"\")")
+	g.p("	if err != nil {")
+	g.p("		log.Fatal(err)")
+	g.p("	}")
+	g.p()
+	g.p("	r := gin.New()")
+	g.p("	r.Use(gin.Logger(), gin.Recovery())")
+	g.p("	r.GET(\"/swagger.json\", func(c *gin.Context) {")
+	g.p("		c.Data(http.StatusOK, \"application/json\", swaggerJSON)")
+	g.p("	})")
+	g.p()
+	g.p("	base := r.Group(doc.BasePath())")
+	g.p("	base.Use(middleware.Validator(doc))")
+	for _, group := range sortedGroups(groups) {
+		g.p("	api.", groups[group], " = base.Group(", strconv.Quote("/"+group), ")")
+	}
+	g.p("	api.AddRoutes()")
+	g.p()
+	g.p("	srv := &http.Server{Addr: *addr, Handler: r}")
+	g.p("	go func() {")
+	g.p("		log.Printf(\"serving %s on %s\", ", strconv.Quote(specDoc.Spec().Info.Title), ", *addr)")
+	g.p("		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {")
+	g.p("			log.Fatal(err)")
+	g.p("		}")
+	g.p("	}()")
+	g.p()
+	g.p("	quit := make(chan os.Signal, 1)")
+	g.p("	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)")
+	g.p("	<-quit")
+	g.p("	log.Print(\"shutting down\")")
+	g.p()
+	g.p("	ctx, cancel := context.WithTimeout(context.Background(), *shutdownTimeout)")
+	g.p("	defer cancel()")
+	g.p("	if err := srv.Shutdown(ctx); err != nil {")
+	g.p("		log.Fatal(err)")
+	g.p("	}")
+	g.p("}")
+	return g.diags.ErrorOrNil()
+}
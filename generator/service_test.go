@@ -0,0 +1,38 @@
+package generator
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateService_Main(t *testing.T) {
+	dir := tempGenDir(t)
+	defer os.RemoveAll(dir)
+	opts := genOpts(t, dir, `
+swagger: "2.0"
+info: {title: "Pets \"API\" at 100%", version: "1.0"}
+basePath: /v1
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      tags: [pets]
+      responses:
+        200: {description: the pets}
+`)
+	opts.ServerPackage = "restapi"
+
+	if !assert.NoError(t, GenerateService("example.com/pets", opts)) {
+		return
+	}
+	assert.Equal(t, "module example.com/pets\n\ngo 1.16\n", generatedFile(t, opts, "go.mod"))
+	assert.Contains(t, generatedFile(t, opts, "swagger.json"), `"basePath": "/v1"`)
+
+	// the title is printed as an argument, its quotes and verbs don't end up in the source
+	main := generatedFile(t, opts, "main.go")
+	assert.Contains(t, main, `log.Printf("serving %s on %s", "Pets \"API\" at 100%", *addr)`)
+	assert.Contains(t, main, `api "example.com/pets/restapi"`)
+	assert.Contains(t, main, `api.Pets = base.Group("/pets")`)
+}
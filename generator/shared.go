@@ -3,8 +3,8 @@ package generator
 import (
 	"fmt"
 	"io/ioutil"
-	"log"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
@@ -40,18 +40,93 @@ func findSwaggerSpec(name string) (string, error) {
 	return name, nil
 }
 
-// GenOpts the options for the generator
+// GenOpts the options for the generator, the json names are the keys of the configuration file
 type GenOpts struct {
-	Spec          string
-	APIPackage    string
-	ModelPackage  string
-	ServerPackage string
-	ClientPackage string
-	Principal     string
-	Target        string
-	TypeMapping   map[string]string
-	Imports       map[string]string
-	DumpData      bool
+	Spec          string `json:"spec,omitempty"`
+	APIPackage    string `json:"apiPackage,omitempty"`
+	ModelPackage  string `json:"modelPackage,omitempty"`
+	ServerPackage string `json:"serverPackage,omitempty"`
+	ClientPackage string `json:"clientPackage,omitempty"`
+	// Principal is the type of the authenticated principal handed to the secured operations
+	Principal string `json:"principal,omitempty"`
+	Target    string `json:"target,omitempty"`
+	DumpData  bool   `json:"dumpData,omitempty"`
+	// TypeMapping maps a format, or a primitive type without a format, to the go type
+	// of the values, Imports maps the aliases of the packages of these types to their path
+	TypeMapping map[string]string `json:"typeMapping,omitempty"`
+	Imports     map[string]string `json:"imports,omitempty"`
+	// Tags and Operations restrict the generation to the matching operations
+	// and the models they use, everything is generated when both are empty
+	Tags       []string `json:"tags,omitempty"`
+	Operations []string `json:"operations,omitempty"`
+	// Files receives the generated files instead of the disk when it's set
+	Files *FileSet `json:"-"`
+	// BaseDir and URLMappings tell where the documents referenced by the spec are loaded from
+	BaseDir     string            `json:"baseDir,omitempty"`
+	URLMappings map[string]string `json:"urlMappings,omitempty"`
+	// ImportPath is the import path of the target, the generated packages import each
+	// other through it. It's taken from the go.mod above the target when it's empty.
+	ImportPath string `json:"importPath,omitempty"`
+}
+
+// fileSet returns the file set the generated files go to, and whether
+// it has to be written out once the generation is done
+func (o *GenOpts) fileSet() (*FileSet, bool) {
+	if o.Files != nil {
+		return o.Files, false
+	}
+	return NewFileSet(), true
+}
+
+// packages returns the names of the generated packages, the models and operations by default
+func (o *GenOpts) packages() genPackages {
+	pkgs := genPackages{importPath: o.importPath(), models: o.ModelPackage, api: o.APIPackage}
+	if pkgs.models == "" {
+		pkgs.models = "models"
+	}
+	if pkgs.api == "" {
+		pkgs.api = "operations"
+	}
+	return pkgs
+}
+
+// importPath returns the import path of the target, or an empty string when
+// it can't be found and goimports has to guess the imports
+func (o *GenOpts) importPath() string {
+	if o.ImportPath != "" {
+		return o.ImportPath
+	}
+	target, err := filepath.Abs(o.Target)
+	if err != nil {
+		return ""
+	}
+	for dir := target; ; dir = filepath.Dir(dir) {
+		if module := modulePath(filepath.Join(dir, "go.mod")); module != "" {
+			rel, err := filepath.Rel(dir, target)
+			if err != nil {
+				return ""
+			}
+			return path.Join(module, filepath.ToSlash(rel))
+		}
+		if filepath.Dir(dir) == dir {
+			return ""
+		}
+	}
+}
+
+// modulePath reads the module path declared in a go.mod file
+func modulePath(gomod string) string {
+	data, err := ioutil.ReadFile(gomod)
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], "\"`")
+		}
+	}
+	return ""
 }
 
 type generatorOptions struct {
@@ -105,7 +180,26 @@ type genValidations struct {
 	NeedsSize           bool
 }
 
-func loadSpec(specFile string) (string, *spec.Document, error) {
+// loadFilteredSpec loads the spec and narrows it down to the operations selected in the options.
+// The schemas of the other documents the spec references are copied to the definitions,
+// so each one gets a model and every reference names a local definition.
+func loadFilteredSpec(opts GenOpts) (string, *spec.Document, error) {
+	specPath, specDoc, err := loadSpec(opts.Spec, spec.LoaderOptions{BaseDir: opts.BaseDir, URLMappings: opts.URLMappings})
+	if err != nil {
+		return "", nil, err
+	}
+	specDoc, err = specDoc.Bundled(spec.BundleOptions{})
+	if err != nil {
+		return "", nil, err
+	}
+	specDoc, err = filterSpec(specDoc, opts.Tags, opts.Operations)
+	if err != nil {
+		return "", nil, err
+	}
+	return specPath, specDoc, nil
+}
+
+func loadSpec(specFile string, loaderOpts spec.LoaderOptions) (string, *spec.Document, error) {
 	// find swagger spec document, verify it exists
 	specPath, err := findSwaggerSpec(specFile)
 	if err != nil {
@@ -113,7 +207,7 @@ func loadSpec(specFile string) (string, *spec.Document, error) {
 	}
 
 	// load swagger spec
-	specDoc, err := spec.Load(specPath)
+	specDoc, err := spec.LoadWithOptions(specPath, loaderOpts)
 	if err != nil {
 		return "", nil, err
 	}
@@ -147,8 +241,11 @@ func writeToFile(target, name string, content []byte) error {
 	ffn := swag.ToFileName(name) + ".go"
 	res, err := formatGoFile(ffn, content)
 	if err != nil {
-		log.Println(err)
-		return writeFile(target, ffn, content)
+		// keep the unformatted source around, it's what's needed to track the problem down
+		if werr := writeFile(target, ffn, content); werr != nil {
+			return werr
+		}
+		return fmt.Errorf("formatting %s: %v", filepath.Join(target, ffn), err)
 	}
 
 	return writeFile(target, ffn, res)
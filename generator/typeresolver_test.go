@@ -1,6 +1,7 @@
 package generator
 
 import (
+	"encoding/json"
 	"fmt"
 	"testing"
 
@@ -312,15 +313,22 @@ func TestTypeResolver_AdditionalProperties(t *testing.T) {
 	}
 }
 
+// taskList has the definitions the refs of the resolver tests point to
+const taskList = `{
+  "swagger": "2.0",
+  "info": {"title": "task list", "version": "1.0"},
+  "paths": {},
+  "definitions": {
+    "Comment": {"type": "object", "properties": {"content": {"type": "string"}}},
+    "UserCard": {"type": "object", "x-go-name": "UserItem", "properties": {"login": {"type": "string"}}}
+  }
+}`
+
 func basicTaskListResolver(t testing.TB) (*spec.Document, *typeResolver, error) {
-	tlb, err := spec.Load("../fixtures/codegen/tasklist.basic.yml")
+	tlb, err := spec.New(json.RawMessage(taskList), "")
 	if err != nil {
 		return nil, nil, err
 	}
-	swsp := tlb.Spec()
-	uc := swsp.Definitions["UserCard"]
-	uc.AddExtension("x-go-name", "UserItem")
-	swsp.Definitions["UserCard"] = uc
 	return tlb, &typeResolver{
 		Doc:           tlb,
 		ModelsPackage: "models",
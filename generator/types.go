@@ -200,6 +200,9 @@ type typeResolver struct {
 	Doc           *spec.Document
 	ModelsPackage string
 	ModelName     string
+	// TypeMapping maps a format, or a primitive type without a format, to the go type
+	// the values are rendered with, it takes precedence over the built-in types
+	TypeMapping map[string]string
 }
 
 func (t *typeResolver) resolveSchemaRef(schema *spec.Schema) (returns bool, result resolvedType, err error) {
@@ -254,10 +257,37 @@ func (t *typeResolver) resolveFormat(schema *spec.Schema) (returns bool, result
 	return
 }
 
+// resolveMapped resolves the schemas whose format, or primitive type when they have no
+// format, is mapped to a go type in the options
+func (t *typeResolver) resolveMapped(schema *spec.Schema) (returns bool, result resolvedType) {
+	tpe := t.firstType(schema)
+	key := schema.Format
+	if key == "" {
+		if tpe == "object" || tpe == "array" {
+			return
+		}
+		key = tpe
+	}
+	goType, ok := t.TypeMapping[key]
+	if !ok {
+		return
+	}
+	returns = true
+	result.SwaggerType = tpe
+	result.SwaggerFormat = schema.Format
+	result.GoType = goType
+	result.IsMapped = true
+	result.IsNullable = t.isNullable(schema)
+	return
+}
+
 func (t *typeResolver) isNullable(schema *spec.Schema) bool {
-	v, found := schema.Extensions["x-isnullable"]
-	nullable, cast := v.(bool)
-	return found && cast && nullable
+	for _, k := range []string{"x-isnullable", "x-nullable"} {
+		if nullable, ok := schema.Extensions[k].(bool); ok && nullable {
+			return true
+		}
+	}
+	return false
 }
 
 func (t *typeResolver) firstType(schema *spec.Schema) string {
@@ -296,6 +326,10 @@ func (t *typeResolver) resolveArray(schema *spec.Schema, isAnonymous bool) (resu
 
 func (t *typeResolver) resolveObject(schema *spec.Schema, isAnonymous bool) (result resolvedType, err error) {
 	result.IsAnonymous = isAnonymous
+	if !isAnonymous && schema.Discriminator != "" {
+		result.IsBaseType = true
+		result.DiscriminatorField = schema.Discriminator
+	}
 
 	if !isAnonymous {
 		result.SwaggerType = "object"
@@ -372,6 +406,11 @@ func (t *typeResolver) ResolveSchema(schema *spec.Schema, isAnonymous bool) (res
 		return
 	}
 
+	returns, result = t.resolveMapped(schema)
+	if returns {
+		return
+	}
+
 	returns, result, err = t.resolveFormat(schema)
 	if returns {
 		return
@@ -423,6 +462,15 @@ type resolvedType struct {
 	HasAdditionalItems bool
 	IsComplexObject    bool
 
+	// A base type has a discriminator, it gets rendered as an interface implemented by
+	// the models extending it and DiscriminatorField is the property telling them apart
+	IsBaseType         bool
+	DiscriminatorField string
+
+	// A mapped type is a go type given in the options, it's used as is and its values
+	// are checked by the type when it's unmarshalled
+	IsMapped bool
+
 	GoType        string
 	SwaggerType   string
 	SwaggerFormat string
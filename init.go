@@ -0,0 +1,69 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+
+	"github.com/aiyi/swagger-gin/generator"
+)
+
+// initCmd scaffolds a runnable service for a spec: the go.mod, a main.go serving
+// the API and the API packages, which are generated in the server package of the
+// target and can be regenerated later on their own
+func initCmd(args []string) int {
+	flags := flag.NewFlagSet("init", flag.ExitOnError)
+	opts, err := loadOpts(args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	bindGenFlags(flags, &opts)
+	module := flags.String("module", "", "the module path of the new service, like github.com/acme/pets")
+	tidy := flags.Bool("tidy", true, "run go mod tidy once the files are written, to add the requirements to the go.mod")
+	flags.Parse(args)
+
+	if *module == "" {
+		fmt.Fprintln(os.Stderr, "a -module is required")
+		flags.Usage()
+		return 2
+	}
+
+	apiOpts := opts
+	apiOpts.Target = filepath.Join(opts.Target, opts.ServerPackage)
+	apiOpts.ImportPath = path.Join(*module, opts.ServerPackage)
+	if !generate(&apiOpts) {
+		return 1
+	}
+
+	serviceOpts := opts
+	serviceOpts.Files = apiOpts.Files
+	if err := generator.GenerateService(*module, serviceOpts); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	written, err := apiOpts.Files.Write()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	for _, file := range written {
+		fmt.Println("wrote", file)
+	}
+
+	if *tidy {
+		cmd := exec.Command("go", "mod", "tidy")
+		cmd.Dir = opts.Target
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			fmt.Fprintf(os.Stderr, "go mod tidy failed: %v, run it in %s once the dependencies can be fetched\n", err, opts.Target)
+			return 1
+		}
+	}
+	return 0
+}
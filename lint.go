@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/aiyi/swagger-gin/lint"
+	"github.com/aiyi/swagger-gin/spec"
+)
+
+// lintCmd checks a spec with the lint rules, the severities of the rules are set in the
+// configuration file or with -rule, and it fails when a finding is serious enough
+func lintCmd(args []string) int {
+	flags := flag.NewFlagSet("lint", flag.ExitOnError)
+	cfg, err := loadConfig(args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	opts := &cfg.GenOpts
+	bindLoaderFlags(flags, opts)
+	flags.Var(&mapFlag{values: &cfg.LintRules}, "rule", "set the severity of a rule, as rule=error|warning|info|off, can be repeated")
+	format := flags.String("format", "text", "the output format, text or json")
+	failOn := flags.String("fail-on", "error", "the least severity failing the command, error, warning or info")
+	list := flags.Bool("list", false, "list the rules with their severity instead of linting")
+	flags.Parse(args)
+
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "unknown format %q, expected text or json\n", *format)
+		return 2
+	}
+	threshold, err := lint.ParseSeverity(*failOn)
+	if err != nil || threshold == lint.Off {
+		fmt.Fprintf(os.Stderr, "unknown -fail-on %q, expected error, warning or info\n", *failOn)
+		return 2
+	}
+	rules, err := lint.Configure(lint.Rules(), cfg.LintRules)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if *list {
+		for _, r := range rules {
+			fmt.Printf("%-20s %-8s %s\n", r.Name, r.Severity, r.Description)
+		}
+		return 0
+	}
+
+	doc, err := spec.LoadWithOptions(opts.Spec, spec.LoaderOptions{BaseDir: opts.BaseDir, URLMappings: opts.URLMappings})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	findings, err := lint.Lint(doc.Spec(), rules)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	failed := 0
+	for _, f := range findings {
+		if f.Severity.AtLeast(threshold) {
+			failed++
+		}
+	}
+	if *format == "json" {
+		if findings == nil {
+			findings = []lint.Finding{}
+		}
+		data, err := json.MarshalIndent(findings, "", "  ")
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		fmt.Println(string(data))
+	} else {
+		for _, f := range findings {
+			fmt.Printf("%-7s %s\n", f.Severity, f)
+		}
+		fmt.Fprintf(os.Stderr, "%d problem(s) found in %s\n", len(findings), opts.Spec)
+	}
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
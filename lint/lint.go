@@ -0,0 +1,181 @@
+// Package lint checks a spec for the practices the generator relies on, like an
+// operationId and tags on every operation and the body schemas in the definitions.
+//
+// Every rule has a severity which can be changed or turned off, and the findings under
+// a node with an x-lint-ignore extension are suppressed. The extension is true to ignore
+// every rule, or the name or the list of the names of the rules ignored.
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aiyi/swagger-gin/jsonpointer"
+	"github.com/aiyi/swagger-gin/spec"
+)
+
+// Severity is how serious a finding is
+type Severity string
+
+// The severities, from the most serious, Off disables a rule
+const (
+	Error   Severity = "error"
+	Warning Severity = "warning"
+	Info    Severity = "info"
+	Off     Severity = "off"
+)
+
+var severityLevels = map[Severity]int{Error: 3, Warning: 2, Info: 1, Off: 0}
+
+// ParseSeverity parses the name of a severity
+func ParseSeverity(name string) (Severity, error) {
+	s := Severity(strings.ToLower(name))
+	if _, ok := severityLevels[s]; !ok {
+		return "", fmt.Errorf("unknown severity %q, expected error, warning, info or off", name)
+	}
+	return s, nil
+}
+
+// AtLeast tells whether the severity is as serious as another one
+func (s Severity) AtLeast(other Severity) bool {
+	return severityLevels[s] >= severityLevels[other]
+}
+
+// IgnoreExtension is the vendor extension suppressing the findings of a node and its children
+const IgnoreExtension = "x-lint-ignore"
+
+// Reporter reports a finding of a rule at the node of a json pointer
+type Reporter func(ptr string, format string, args ...interface{})
+
+// Rule is a check of the spec
+type Rule struct {
+	Name        string
+	Description string
+	Severity    Severity
+	Check       func(sw *spec.Swagger, report Reporter)
+}
+
+// Finding is a problem found by a rule
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	// Path is the json pointer to the node of the finding
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (f Finding) String() string {
+	path := f.Path
+	if path == "" {
+		path = "/"
+	}
+	return fmt.Sprintf("%s: %s (%s)", path, f.Message, f.Rule)
+}
+
+// Configure returns a copy of the rules with the severities set by the name of the rules
+func Configure(rules []Rule, severities map[string]string) ([]Rule, error) {
+	byName := make(map[string]int, len(rules))
+	result := make([]Rule, len(rules))
+	for i, r := range rules {
+		result[i] = r
+		byName[r.Name] = i
+	}
+	names := make([]string, 0, len(severities))
+	for name := range severities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		i, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown lint rule %q", name)
+		}
+		severity, err := ParseSeverity(severities[name])
+		if err != nil {
+			return nil, fmt.Errorf("lint rule %s: %v", name, err)
+		}
+		result[i].Severity = severity
+	}
+	return result, nil
+}
+
+// Lint runs the rules which aren't off on a spec, the findings are sorted by path
+func Lint(sw *spec.Swagger, rules []Rule) ([]Finding, error) {
+	data, err := json.Marshal(sw)
+	if err != nil {
+		return nil, err
+	}
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	var result []Finding
+	for _, rule := range rules {
+		if rule.Severity == Off || rule.Severity == "" {
+			continue
+		}
+		rule := rule
+		rule.Check(sw, func(ptr string, format string, args ...interface{}) {
+			if ignored(raw, ptr, rule.Name) {
+				return
+			}
+			result = append(result, Finding{
+				Rule:     rule.Name,
+				Severity: rule.Severity,
+				Path:     ptr,
+				Message:  fmt.Sprintf(format, args...),
+			})
+		})
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Path < result[j].Path
+	})
+	return result, nil
+}
+
+// ignored tells whether the node of a pointer or one of its parents ignores a rule
+func ignored(raw interface{}, ptr, rule string) bool {
+	node := raw
+	tokens := strings.Split(ptr, "/")[1:]
+	for i := 0; ; i++ {
+		if obj, ok := node.(map[string]interface{}); ok && ignores(obj[IgnoreExtension], rule) {
+			return true
+		}
+		if i == len(tokens) {
+			return false
+		}
+		token := jsonpointer.Unescape(tokens[i])
+		switch value := node.(type) {
+		case map[string]interface{}:
+			node = value[token]
+		case []interface{}:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(value) {
+				return false
+			}
+			node = value[index]
+		default:
+			return false
+		}
+	}
+}
+
+func ignores(ext interface{}, rule string) bool {
+	switch value := ext.(type) {
+	case bool:
+		return value
+	case string:
+		return value == rule
+	case []interface{}:
+		for _, v := range value {
+			if v == rule {
+				return true
+			}
+		}
+	}
+	return false
+}
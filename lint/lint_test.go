@@ -0,0 +1,126 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/aiyi/swagger-gin/spec"
+	"github.com/aiyi/swagger-gin/swag"
+	"github.com/stretchr/testify/assert"
+)
+
+const sloppyPets = `
+swagger: "2.0"
+info: {title: pets, version: "1.0"}
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      tags: [pets]
+      summary: lists the pets
+      responses:
+        200: {description: the pets, schema: {type: array, items: {$ref: "#/definitions/Pet"}}}
+    post:
+      operationId: add_pet
+      parameters:
+        - name: body
+          in: body
+          schema:
+            type: object
+            properties:
+              name: {type: string}
+      responses:
+        default: {description: added}
+  /pets/{id}:
+    delete:
+      x-lint-ignore: [operation-id, operation-summary]
+      parameters:
+        - {name: id, in: path, required: true, type: string}
+      tags: [pets]
+      responses:
+        204: {description: deleted}
+definitions:
+  Pet:
+    properties:
+      name: {type: string}
+      owner_name: {type: string}
+  pet_list:
+    type: array
+    items: {$ref: "#/definitions/Pet"}
+  Legacy:
+    x-lint-ignore: true
+    properties:
+      Old_Name: {type: string}
+`
+
+func load(t *testing.T) *spec.Swagger {
+	data, err := swag.YAMLToJSON([]byte(sloppyPets))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	doc, err := spec.New(data, "")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return doc.Spec()
+}
+
+func rulesOf(findings []Finding, path string) []string {
+	var result []string
+	for _, f := range findings {
+		if f.Path == path {
+			result = append(result, f.Rule)
+		}
+	}
+	return result
+}
+
+func TestLint(t *testing.T) {
+	findings, err := Lint(load(t), Rules())
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Empty(t, rulesOf(findings, "/paths/~1pets/get"))
+	assert.Equal(t, []string{"operation-tags", "operation-summary"}, rulesOf(findings, "/paths/~1pets/post"))
+	assert.Equal(t, []string{"operation-id-case"}, rulesOf(findings, "/paths/~1pets/post/operationId"))
+	assert.Equal(t, []string{"success-response"}, rulesOf(findings, "/paths/~1pets/post/responses"))
+	assert.Equal(t, []string{"inline-body-schema"}, rulesOf(findings, "/paths/~1pets/post/parameters/0/schema"))
+	assert.Equal(t, []string{"property-case"}, rulesOf(findings, "/definitions/Pet/properties/owner_name"))
+	assert.Equal(t, []string{"definition-case", "unused-definition"}, rulesOf(findings, "/definitions/pet_list"))
+
+	// ignored by the extensions
+	assert.Empty(t, rulesOf(findings, "/paths/~1pets~1{id}/delete"))
+	for _, f := range findings {
+		assert.NotContains(t, f.Path, "/definitions/Legacy", f.String())
+	}
+
+	for _, f := range findings {
+		if f.Rule == "success-response" {
+			assert.Equal(t, Error, f.Severity)
+		}
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	rules, err := Configure(Rules(), map[string]string{"success-response": "warning", "property-case": "off"})
+	if !assert.NoError(t, err) {
+		return
+	}
+	findings, err := Lint(load(t), rules)
+	if !assert.NoError(t, err) {
+		return
+	}
+	for _, f := range findings {
+		assert.NotEqual(t, "property-case", f.Rule)
+		if f.Rule == "success-response" {
+			assert.Equal(t, Warning, f.Severity)
+		}
+	}
+	// the defaults are left alone
+	assert.Equal(t, Error, Rules()[4].Severity)
+
+	_, err = Configure(Rules(), map[string]string{"no-such-rule": "error"})
+	assert.Error(t, err)
+	_, err = Configure(Rules(), map[string]string{"operation-id": "fatal"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,248 @@
+package lint
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/aiyi/swagger-gin/jsonpointer"
+	"github.com/aiyi/swagger-gin/spec"
+)
+
+var (
+	lowerCamelRx = regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`)
+	upperCamelRx = regexp.MustCompile(`^[A-Z][a-zA-Z0-9]*$`)
+)
+
+// Rules returns the built-in rules with their default severities
+func Rules() []Rule {
+	return []Rule{
+		{
+			Name:        "operation-id",
+			Description: "every operation has an operationId, the handlers and the operations are named after it",
+			Severity:    Error,
+			Check:       checkOperationID,
+		},
+		{
+			Name:        "operation-id-case",
+			Description: "the operationIds are lowerCamelCase",
+			Severity:    Warning,
+			Check:       checkOperationIDCase,
+		},
+		{
+			Name:        "operation-tags",
+			Description: "every operation has a tag, the routes are grouped by the first one",
+			Severity:    Warning,
+			Check:       checkOperationTags,
+		},
+		{
+			Name:        "operation-summary",
+			Description: "every operation has a summary or a description",
+			Severity:    Info,
+			Check:       checkOperationSummary,
+		},
+		{
+			Name:        "success-response",
+			Description: "every operation has a 2xx response",
+			Severity:    Error,
+			Check:       checkSuccessResponse,
+		},
+		{
+			Name:        "inline-body-schema",
+			Description: "the objects of the bodies are definitions, the inline ones become anonymous types",
+			Severity:    Warning,
+			Check:       checkInlineBodySchema,
+		},
+		{
+			Name:        "definition-case",
+			Description: "the definitions are UpperCamelCase, like the models generated for them",
+			Severity:    Warning,
+			Check:       checkDefinitionCase,
+		},
+		{
+			Name:        "property-case",
+			Description: "the properties of the definitions are lowerCamelCase",
+			Severity:    Warning,
+			Check:       checkPropertyCase,
+		},
+		{
+			Name:        "unused-definition",
+			Description: "every definition is referenced",
+			Severity:    Warning,
+			Check:       checkUnusedDefinition,
+		},
+	}
+}
+
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch"}
+
+func operationOf(item *spec.PathItem, method string) *spec.Operation {
+	switch method {
+	case "get":
+		return item.Get
+	case "put":
+		return item.Put
+	case "post":
+		return item.Post
+	case "delete":
+		return item.Delete
+	case "options":
+		return item.Options
+	case "head":
+		return item.Head
+	case "patch":
+		return item.Patch
+	}
+	return nil
+}
+
+// eachOperation calls fn with every operation and its pointer, sorted by path and method
+func eachOperation(sw *spec.Swagger, fn func(ptr string, op *spec.Operation)) {
+	if sw.Paths == nil {
+		return
+	}
+	paths := make([]string, 0, len(sw.Paths.Paths))
+	for path := range sw.Paths.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		item := sw.Paths.Paths[path]
+		for _, method := range methods {
+			if op := operationOf(&item, method); op != nil {
+				fn("/paths/"+jsonpointer.Escape(path)+"/"+method, op)
+			}
+		}
+	}
+}
+
+func sortedDefinitions(sw *spec.Swagger) []string {
+	names := make([]string, 0, len(sw.Definitions))
+	for name := range sw.Definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func checkOperationID(sw *spec.Swagger, report Reporter) {
+	eachOperation(sw, func(ptr string, op *spec.Operation) {
+		if op.ID == "" {
+			report(ptr, "the operation has no operationId")
+		}
+	})
+}
+
+func checkOperationIDCase(sw *spec.Swagger, report Reporter) {
+	eachOperation(sw, func(ptr string, op *spec.Operation) {
+		if op.ID != "" && !lowerCamelRx.MatchString(op.ID) {
+			report(ptr+"/operationId", "the operationId %q isn't lowerCamelCase", op.ID)
+		}
+	})
+}
+
+func checkOperationTags(sw *spec.Swagger, report Reporter) {
+	eachOperation(sw, func(ptr string, op *spec.Operation) {
+		if len(op.Tags) == 0 {
+			report(ptr, "the operation has no tags")
+		}
+	})
+}
+
+func checkOperationSummary(sw *spec.Swagger, report Reporter) {
+	eachOperation(sw, func(ptr string, op *spec.Operation) {
+		if op.Summary == "" && op.Description == "" {
+			report(ptr, "the operation has no summary or description")
+		}
+	})
+}
+
+func checkSuccessResponse(sw *spec.Swagger, report Reporter) {
+	eachOperation(sw, func(ptr string, op *spec.Operation) {
+		if op.Responses != nil {
+			for code := range op.Responses.StatusCodeResponses {
+				if code >= 200 && code < 300 {
+					return
+				}
+			}
+		}
+		report(ptr+"/responses", "the operation has no 2xx response")
+	})
+}
+
+func checkInlineBodySchema(sw *spec.Swagger, report Reporter) {
+	eachOperation(sw, func(ptr string, op *spec.Operation) {
+		for i, p := range op.Parameters {
+			if p.In != "body" || p.Schema == nil {
+				continue
+			}
+			schemaPtr := ptr + "/parameters/" + strconv.Itoa(i) + "/schema"
+			schema := p.Schema
+			if schema.Items != nil && schema.Items.Schema != nil {
+				schema = schema.Items.Schema
+				schemaPtr += "/items"
+			}
+			if schema.Ref.String() == "" && (len(schema.Properties) > 0 || len(schema.AllOf) > 0) {
+				report(schemaPtr, "the schema of the body is inline, move it to the definitions and reference it")
+			}
+		}
+	})
+}
+
+func checkDefinitionCase(sw *spec.Swagger, report Reporter) {
+	for _, name := range sortedDefinitions(sw) {
+		if !upperCamelRx.MatchString(name) {
+			report("/definitions/"+jsonpointer.Escape(name), "the definition %q isn't UpperCamelCase", name)
+		}
+	}
+}
+
+func checkPropertyCase(sw *spec.Swagger, report Reporter) {
+	for _, name := range sortedDefinitions(sw) {
+		schema := sw.Definitions[name]
+		checkProperties("/definitions/"+jsonpointer.Escape(name), &schema, report)
+	}
+}
+
+// checkProperties checks the names of the properties of a schema and of its inline schemas
+func checkProperties(ptr string, schema *spec.Schema, report Reporter) {
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		propPtr := ptr + "/properties/" + jsonpointer.Escape(name)
+		if !lowerCamelRx.MatchString(name) {
+			report(propPtr, "the property %q isn't lowerCamelCase", name)
+		}
+		prop := schema.Properties[name]
+		checkProperties(propPtr, &prop, report)
+	}
+	for i := range schema.AllOf {
+		checkProperties(ptr+"/allOf/"+strconv.Itoa(i), &schema.AllOf[i], report)
+	}
+	if schema.Items != nil && schema.Items.Schema != nil {
+		checkProperties(ptr+"/items", schema.Items.Schema, report)
+	}
+}
+
+func checkUnusedDefinition(sw *spec.Swagger, report Reporter) {
+	data, err := json.Marshal(sw)
+	if err != nil {
+		return
+	}
+	used := make(map[string]bool)
+	for _, match := range definitionRefRx.FindAllSubmatch(data, -1) {
+		used[jsonpointer.Unescape(string(match[1]))] = true
+	}
+	for _, name := range sortedDefinitions(sw) {
+		if !used[name] {
+			report("/definitions/"+jsonpointer.Escape(name), "the definition %q isn't referenced", name)
+		}
+	}
+}
+
+// definitionRefRx matches the local references to the definitions in the marshaled spec
+var definitionRefRx = regexp.MustCompile(`"\$ref":"#/definitions/([^"/]+)"`)
@@ -2,31 +2,100 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"os"
+	"time"
 
 	"github.com/aiyi/swagger-gin/generator"
 )
 
+// commands are the subcommands, the code generation runs when none is given
+var commands = map[string]func(args []string) int{
+	"bundle":   bundleCmd,
+	"convert":  convertCmd,
+	"diff":     diffCmd,
+	"init":     initCmd,
+	"lint":     lintCmd,
+	"scan":     scanCmd,
+	"validate": validateCmd,
+}
+
 func main() {
-	spec := flag.String("spec", "./swagger.json", "the spec file to use")
-	target := flag.String("target", "./", "the directory for generating the files")
+	if len(os.Args) > 1 {
+		if cmd, ok := commands[os.Args[1]]; ok {
+			os.Exit(cmd(os.Args[2:]))
+		}
+	}
+
+	genOpts, err := loadOpts(os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	bindGenFlags(flag.CommandLine, &genOpts)
+	dryRun := flag.Bool("dry-run", false, "list the files that would be created or changed without writing them")
+	diff := flag.Bool("diff", false, "print the changes to the files in the target directory without writing them")
+	watchSpec := flag.Bool("watch", false, "regenerate whenever the spec or the local files it references change")
+	interval := flag.Duration("interval", time.Second, "how often the spec is polled in watch mode")
 
 	flag.Parse()
 
-	genOpts := generator.GenOpts{
-		Spec:         *spec,
-		Target:       *target,
-		APIPackage:   "operations",
-		ModelPackage: "models",
+	if *watchSpec {
+		watch(genOpts, *interval)
+		return
 	}
 
-	if err := generator.GenerateDefinition(true, true, genOpts); err != nil {
-		panic(err)
+	if !generate(&genOpts) {
 		os.Exit(1)
 	}
 
-	if err := generator.GenerateServerOperation(true, true, genOpts); err != nil {
-		panic(err)
+	if *dryRun || *diff {
+		if err := report(genOpts.Files, *diff); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if _, err := genOpts.Files.Write(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
 }
+
+// generate renders the models and the server into a new file set, both steps
+// run so all the problems in the spec are reported at once
+func generate(opts *generator.GenOpts) bool {
+	opts.Files = generator.NewFileSet()
+	ok := true
+	if err := generator.GenerateDefinition(true, true, *opts); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		ok = false
+	}
+
+	if err := generator.GenerateServerOperation(true, true, *opts); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		ok = false
+	}
+	return ok
+}
+
+// report prints the status of every generated file, or the diffs when asked to
+func report(files *generator.FileSet, diff bool) error {
+	for _, path := range files.Paths() {
+		if diff {
+			d, err := files.Diff(path)
+			if err != nil {
+				return err
+			}
+			fmt.Print(d)
+			continue
+		}
+		status, err := files.Status(path)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%-9s %s\n", status, path)
+	}
+	return nil
+}
@@ -0,0 +1,262 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/aiyi/swagger-gin/errors"
+	"github.com/aiyi/swagger-gin/spec"
+	"github.com/aiyi/swagger-gin/swag"
+	"github.com/aiyi/swagger-gin/validate"
+	"github.com/gin-gonic/gin"
+)
+
+const defaultMemory = 32 << 20
+
+// Validator returns a middleware that validates the incoming requests against the
+// operations of the spec, before they reach the handlers.
+//
+// The content type, the path, query, header and form parameters and the body are
+// checked and all the failures are reported at once through errors.ServeError.
+// Requests that don't match any operation of the spec are passed on untouched.
+func Validator(doc *spec.Document) gin.HandlerFunc {
+	r := newRouter(doc)
+	return func(c *gin.Context) {
+		match, ok := r.Lookup(c.Request.Method, c.Request.URL.Path)
+		if !ok {
+			c.Next()
+			return
+		}
+		if err := validateRequest(doc.Spec(), match, c.Request); err != nil {
+			errors.ServeError(c, err)
+			return
+		}
+		c.Next()
+	}
+}
+
+func validateRequest(root *spec.Swagger, match *routeMatch, req *http.Request) error {
+	hasBody := req.ContentLength != 0 || len(req.TransferEncoding) > 0
+	if hasBody && len(match.Consumes) > 0 {
+		if err := validateContentType(req.Header.Get("Content-Type"), match.Consumes); err != nil {
+			return err
+		}
+	}
+
+	var result []error
+	for i := range match.Parameters {
+		param := &match.Parameters[i]
+		var err error
+		switch param.In {
+		case "body":
+			err = validateBody(root, param, req)
+		case "path":
+			value, ok := match.Params[param.Name]
+			err = validateSimpleParam(param, []string{value}, ok)
+		case "query":
+			values, ok := req.URL.Query()[param.Name]
+			err = validateSimpleParam(param, values, ok)
+		case "header":
+			values, ok := req.Header[http.CanonicalHeaderKey(param.Name)]
+			err = validateSimpleParam(param, values, ok)
+		case "formData":
+			err = validateFormParam(param, req)
+		}
+		if err != nil {
+			result = append(result, err)
+		}
+	}
+	if len(result) == 0 {
+		return nil
+	}
+
+	ce := errors.CompositeValidationError(result...)
+	for _, body := range errors.Flatten(ce) {
+		if body.Code == http.StatusBadRequest {
+			ce.Code = http.StatusBadRequest
+			break
+		}
+	}
+	return ce
+}
+
+func validateContentType(value string, allowed []string) error {
+	mt, _, err := mime.ParseMediaType(value)
+	if err != nil {
+		return errors.InvalidContentType(value, allowed)
+	}
+	for _, a := range allowed {
+		if amt, _, err := mime.ParseMediaType(a); err == nil && amt == mt {
+			return nil
+		}
+	}
+	return errors.InvalidContentType(value, allowed)
+}
+
+func validateBody(root *spec.Swagger, param *spec.Parameter, req *http.Request) error {
+	var raw []byte
+	if req.Body != nil {
+		b, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			return errors.ParseError(param.Name, param.In, "", err)
+		}
+		req.Body.Close()
+		// put the body back for the handlers down the chain
+		req.Body = ioutil.NopCloser(bytes.NewReader(b))
+		raw = b
+	}
+	if len(bytes.TrimSpace(raw)) == 0 {
+		if param.Required {
+			return errors.Required(param.Name, param.In)
+		}
+		return nil
+	}
+
+	var data interface{}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&data); err != nil {
+		return errors.ParseError(param.Name, param.In, "", err)
+	}
+	return validate.AgainstSchema("", param.In, param.Schema, root, data)
+}
+
+func validateFormParam(param *spec.Parameter, req *http.Request) error {
+	if req.MultipartForm == nil && req.PostForm == nil {
+		if strings.HasPrefix(req.Header.Get("Content-Type"), "multipart/") {
+			req.ParseMultipartForm(defaultMemory)
+		} else {
+			req.ParseForm()
+		}
+	}
+
+	if param.Type == "file" {
+		if req.MultipartForm != nil && len(req.MultipartForm.File[param.Name]) > 0 {
+			return nil
+		}
+		if param.Required {
+			return errors.Required(param.Name, param.In)
+		}
+		return nil
+	}
+	values, ok := req.PostForm[param.Name]
+	if !ok && req.MultipartForm != nil {
+		values, ok = req.MultipartForm.Value[param.Name]
+	}
+	return validateSimpleParam(param, values, ok)
+}
+
+// validateSimpleParam converts the raw values of a non-body parameter to the type
+// declared in the spec and validates them against the parameter constraints
+func validateSimpleParam(param *spec.Parameter, values []string, ok bool) error {
+	if !ok || len(values) == 0 || (len(values) == 1 && values[0] == "" && param.Type != "string") {
+		if param.Required {
+			return errors.Required(param.Name, param.In)
+		}
+		return nil
+	}
+
+	var raw interface{}
+	if param.Type == "array" && param.CollectionFormat == "multi" {
+		raw = values
+	} else {
+		raw = values[0]
+	}
+	data, err := convertSimple(param.Name, param.In, raw, &param.SimpleSchema, param.Items)
+	if err != nil {
+		return err
+	}
+	schema := schemaFromParam(param)
+	return validate.AgainstSchema(param.Name, param.In, schema, schema, data)
+}
+
+// convertSimple turns a raw string, or a list of them for multi collections,
+// into the value described by the simple schema
+func convertSimple(name, in string, raw interface{}, sch *spec.SimpleSchema, items *spec.Items) (interface{}, error) {
+	if sch.Type == "array" {
+		var parts []string
+		switch value := raw.(type) {
+		case []string:
+			parts = value
+		case string:
+			parts = swag.SplitByFormat(value, sch.CollectionFormat)
+		}
+		result := make([]interface{}, 0, len(parts))
+		for _, part := range parts {
+			if items == nil {
+				result = append(result, part)
+				continue
+			}
+			item, err := convertSimple(name, in, part, &items.SimpleSchema, items.Items)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, item)
+		}
+		return result, nil
+	}
+
+	str, _ := raw.(string)
+	switch sch.Type {
+	case "integer", "number":
+		f, err := strconv.ParseFloat(str, 64)
+		if err != nil {
+			return nil, errors.ParseError(name, in, str, err)
+		}
+		return f, nil
+	case "boolean":
+		b, err := strconv.ParseBool(str)
+		if err != nil {
+			return nil, errors.ParseError(name, in, str, err)
+		}
+		return b, nil
+	}
+	return str, nil
+}
+
+func schemaFromParam(param *spec.Parameter) *spec.Schema {
+	schema := schemaFromSimple(&param.SimpleSchema, param.Items)
+	copyValidations(schema, param.Maximum, param.ExclusiveMaximum, param.Minimum, param.ExclusiveMinimum,
+		param.MaxLength, param.MinLength, param.Pattern, param.MaxItems, param.MinItems,
+		param.UniqueItems, param.MultipleOf, param.Enum)
+	return schema
+}
+
+func schemaFromSimple(sch *spec.SimpleSchema, items *spec.Items) *spec.Schema {
+	schema := new(spec.Schema)
+	if sch.Type != "" {
+		schema.Type = spec.StringOrArray{sch.Type}
+	}
+	schema.Format = sch.Format
+	if sch.Type == "array" && items != nil {
+		item := schemaFromSimple(&items.SimpleSchema, items.Items)
+		copyValidations(item, items.Maximum, items.ExclusiveMaximum, items.Minimum, items.ExclusiveMinimum,
+			items.MaxLength, items.MinLength, items.Pattern, items.MaxItems, items.MinItems,
+			items.UniqueItems, items.MultipleOf, items.Enum)
+		schema.Items = &spec.SchemaOrArray{Schema: item}
+	}
+	return schema
+}
+
+func copyValidations(schema *spec.Schema, max *float64, exclusiveMax bool, min *float64, exclusiveMin bool,
+	maxLength, minLength *int64, pattern string, maxItems, minItems *int64,
+	uniqueItems bool, multipleOf *float64, enum []interface{}) {
+
+	schema.Maximum = max
+	schema.ExclusiveMaximum = exclusiveMax
+	schema.Minimum = min
+	schema.ExclusiveMinimum = exclusiveMin
+	schema.MaxLength = maxLength
+	schema.MinLength = minLength
+	schema.Pattern = pattern
+	schema.MaxItems = maxItems
+	schema.MinItems = minItems
+	schema.UniqueItems = uniqueItems
+	schema.MultipleOf = multipleOf
+	schema.Enum = enum
+}
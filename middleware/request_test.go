@@ -0,0 +1,163 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aiyi/swagger-gin/errors"
+	"github.com/aiyi/swagger-gin/spec"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+const petStore = `{
+  "swagger": "2.0",
+  "info": {"title": "pets", "version": "1.0"},
+  "basePath": "/v2",
+  "consumes": ["application/json"],
+  "produces": ["application/json"],
+  "parameters": {
+    "limit": {"name": "limit", "in": "query", "type": "integer", "maximum": 100}
+  },
+  "paths": {
+    "/pets": {
+      "get": {
+        "operationId": "findPets",
+        "parameters": [
+          {"$ref": "#/parameters/limit"},
+          {"name": "tags", "in": "query", "type": "array", "items": {"type": "string"}, "maxItems": 2},
+          {"name": "X-Request-Id", "in": "header", "type": "string", "required": true}
+        ],
+        "responses": {"200": {"description": "ok"}}
+      },
+      "post": {
+        "operationId": "addPet",
+        "parameters": [
+          {"name": "body", "in": "body", "required": true, "schema": {"$ref": "#/definitions/Pet"}}
+        ],
+        "responses": {"200": {"description": "ok"}}
+      }
+    },
+    "/pets/{id}": {
+      "parameters": [
+        {"name": "id", "in": "path", "type": "integer", "required": true, "minimum": 1}
+      ],
+      "get": {
+        "operationId": "getPet",
+        "responses": {"200": {"description": "ok"}}
+      }
+    },
+    "/pets/mine": {
+      "get": {
+        "operationId": "myPets",
+        "responses": {"200": {"description": "ok"}}
+      }
+    }
+  },
+  "definitions": {
+    "Pet": {
+      "type": "object",
+      "required": ["name"],
+      "properties": {
+        "name": {"type": "string", "minLength": 1},
+        "tag": {"type": "string"}
+      }
+    }
+  }
+}`
+
+func testEngine(t *testing.T) *gin.Engine {
+	doc, err := spec.New(json.RawMessage(petStore), "")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(Validator(doc))
+	ok := func(c *gin.Context) { c.String(http.StatusOK, "ok") }
+	engine.GET("/v2/pets", ok)
+	engine.POST("/v2/pets", ok)
+	engine.GET("/v2/pets/:id", ok)
+	engine.GET("/health", ok)
+	return engine
+}
+
+func perform(engine *gin.Engine, req *http.Request) (*httptest.ResponseRecorder, errors.ErrorEnvelope) {
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+	var body errors.ErrorEnvelope
+	json.Unmarshal(rec.Body.Bytes(), &body)
+	return rec, body
+}
+
+func TestValidator_PassesValidRequests(t *testing.T) {
+	engine := testEngine(t)
+
+	req := httptest.NewRequest("GET", "/v2/pets?limit=10&tags=a,b", nil)
+	req.Header.Set("X-Request-Id", "abc")
+	rec, _ := perform(engine, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	req = httptest.NewRequest("POST", "/v2/pets", strings.NewReader(`{"name": "kitty"}`))
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	rec, _ = perform(engine, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec, _ = perform(engine, httptest.NewRequest("GET", "/v2/pets/mine", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec, _ = perform(engine, httptest.NewRequest("GET", "/health", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestValidator_CollectsParameterErrors(t *testing.T) {
+	engine := testEngine(t)
+
+	rec, body := perform(engine, httptest.NewRequest("GET", "/v2/pets?limit=500&tags=a,b,c", nil))
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	if assert.Len(t, body.Errors, 3) {
+		assert.Equal(t, "limit", body.Errors[0].Name)
+		assert.Equal(t, "tags", body.Errors[1].Name)
+		assert.Equal(t, "X-Request-Id", body.Errors[2].Name)
+	}
+
+	rec, body = perform(engine, httptest.NewRequest("GET", "/v2/pets/abc", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	if assert.Len(t, body.Errors, 1) {
+		assert.Equal(t, "id", body.Errors[0].Name)
+		assert.Equal(t, "path", body.Errors[0].In)
+	}
+
+	rec, _ = perform(engine, httptest.NewRequest("GET", "/v2/pets/0", nil))
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+}
+
+func TestValidator_Body(t *testing.T) {
+	engine := testEngine(t)
+
+	req := httptest.NewRequest("POST", "/v2/pets", strings.NewReader(`{"name": "", "tag": 1}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec, body := perform(engine, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	if assert.Len(t, body.Errors, 2) {
+		assert.Equal(t, "name", body.Errors[0].Name)
+		assert.Equal(t, "tag", body.Errors[1].Name)
+		assert.Equal(t, "body", body.Errors[1].In)
+	}
+
+	req = httptest.NewRequest("POST", "/v2/pets", strings.NewReader(`{"name": `))
+	req.Header.Set("Content-Type", "application/json")
+	rec, _ = perform(engine, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	req = httptest.NewRequest("POST", "/v2/pets", strings.NewReader(`name=kitty`))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec, body = perform(engine, req)
+	assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+	if assert.Len(t, body.Errors, 1) {
+		assert.Equal(t, "Content-Type", body.Errors[0].Name)
+	}
+}
@@ -0,0 +1,200 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/aiyi/swagger-gin/errors"
+	"github.com/aiyi/swagger-gin/spec"
+	"github.com/aiyi/swagger-gin/validate"
+	"github.com/gin-gonic/gin"
+)
+
+// ResponseOpts configures the response validation middleware
+type ResponseOpts struct {
+	// Fail replaces a response that doesn't match the spec with a 500 error,
+	// by default the mismatch is only logged and the response is sent as is
+	Fail bool
+	// Logf reports the mismatches, it defaults to log.Printf
+	Logf func(format string, args ...interface{})
+	// EnableInRelease turns the validation on when gin runs in release mode,
+	// where it is disabled by default
+	EnableInRelease bool
+}
+
+// ResponseValidator returns a middleware that buffers the responses written by the
+// handlers and validates their status code, headers and body against the responses
+// declared for the operation in the spec.
+//
+// It is meant for development and CI, the buffering has a cost, so when gin runs
+// in release mode the middleware does nothing unless opts.EnableInRelease is set.
+func ResponseValidator(doc *spec.Document, opts ResponseOpts) gin.HandlerFunc {
+	if gin.Mode() == gin.ReleaseMode && !opts.EnableInRelease {
+		return func(c *gin.Context) { c.Next() }
+	}
+	if opts.Logf == nil {
+		opts.Logf = log.Printf
+	}
+
+	r := newRouter(doc)
+	return func(c *gin.Context) {
+		match, ok := r.Lookup(c.Request.Method, c.Request.URL.Path)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		before := c.Writer.Header().Clone()
+		w := &bufferedWriter{ResponseWriter: c.Writer, status: c.Writer.Status()}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		err := r.validateResponse(match, c.Request.Method, w.status, w.Header(), w.body.Bytes())
+		if err != nil {
+			opts.Logf("response of %s %s doesn't match the spec: %v", c.Request.Method, c.Request.URL.Path, err)
+			if opts.Fail {
+				// the headers set by the handler describe the response the error replaces
+				header := w.ResponseWriter.Header()
+				for k := range header {
+					delete(header, k)
+				}
+				for k, v := range before {
+					header[k] = v
+				}
+				errors.ServeError(c, err)
+				return
+			}
+		}
+		w.ResponseWriter.WriteHeader(w.status)
+		w.ResponseWriter.Write(w.body.Bytes())
+	}
+}
+
+// bufferedWriter holds the response back until it has been validated
+type bufferedWriter struct {
+	gin.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *bufferedWriter) WriteHeader(code int) {
+	if code > 0 {
+		w.status = code
+	}
+}
+
+func (w *bufferedWriter) WriteHeaderNow() {}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+func (w *bufferedWriter) Status() int {
+	return w.status
+}
+
+func (w *bufferedWriter) Size() int {
+	return w.body.Len()
+}
+
+func (w *bufferedWriter) Written() bool {
+	return w.body.Len() > 0
+}
+
+func (w *bufferedWriter) Flush() {}
+
+// responseFor finds the response declared for a status code, falling back to the default one
+func (r *router) responseFor(op *spec.Operation, status int) (*spec.Response, bool) {
+	if op.Responses == nil {
+		return nil, false
+	}
+	if resp, ok := op.Responses.StatusCodeResponses[status]; ok {
+		resolved := r.resolveResponse(resp)
+		return &resolved, true
+	}
+	if op.Responses.Default != nil {
+		resolved := r.resolveResponse(*op.Responses.Default)
+		return &resolved, true
+	}
+	return nil, false
+}
+
+func (r *router) validateResponse(match *routeMatch, method string, status int, header http.Header, body []byte) error {
+	response, ok := r.responseFor(match.Operation, status)
+	if !ok {
+		name := match.Operation.ID
+		if name == "" {
+			name = match.Method + " " + match.Path
+		}
+		return errors.CompositeResponseError(errors.UndocumentedStatus(name, status))
+	}
+
+	var result []error
+	names := make([]string, 0, len(response.Headers))
+	for name := range response.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		values, ok := header[http.CanonicalHeaderKey(name)]
+		if !ok || len(values) == 0 {
+			continue
+		}
+		h := response.Headers[name]
+		data, err := convertSimple(name, "header", values[0], &h.SimpleSchema, h.Items)
+		if err == nil {
+			schema := schemaFromHeader(&h)
+			err = validate.AgainstSchema(name, "header", schema, schema, data)
+		}
+		if err != nil {
+			result = append(result, err)
+		}
+	}
+
+	if response.Schema != nil && method != http.MethodHead {
+		if err := validateResponseBody(r.doc.Spec(), response.Schema, header.Get("Content-Type"), body); err != nil {
+			result = append(result, err)
+		}
+	}
+
+	if len(result) > 0 {
+		return errors.CompositeResponseError(result...)
+	}
+	return nil
+}
+
+func validateResponseBody(root *spec.Swagger, schema *spec.Schema, contentType string, body []byte) error {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return errors.Required("body", "response")
+	}
+	// only json payloads can be checked against the schema
+	if mt, _, err := mime.ParseMediaType(contentType); err == nil && !strings.Contains(mt, "json") {
+		return nil
+	}
+
+	var data interface{}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&data); err != nil {
+		return errors.ParseError("body", "response", "", err)
+	}
+	return validate.AgainstSchema("", "response", schema, root, data)
+}
+
+func schemaFromHeader(h *spec.Header) *spec.Schema {
+	schema := schemaFromSimple(&h.SimpleSchema, h.Items)
+	copyValidations(schema, h.Maximum, h.ExclusiveMaximum, h.Minimum, h.ExclusiveMinimum,
+		h.MaxLength, h.MinLength, h.Pattern, h.MaxItems, h.MinItems,
+		h.UniqueItems, h.MultipleOf, h.Enum)
+	return schema
+}
@@ -0,0 +1,165 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aiyi/swagger-gin/errors"
+	"github.com/aiyi/swagger-gin/spec"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+const petResponses = `{
+  "swagger": "2.0",
+  "info": {"title": "pets", "version": "1.0"},
+  "paths": {
+    "/pets/{id}": {
+      "get": {
+        "operationId": "getPet",
+        "parameters": [{"name": "id", "in": "path", "type": "integer", "required": true}],
+        "responses": {
+          "200": {
+            "description": "ok",
+            "headers": {"X-Rate-Limit": {"type": "integer", "maximum": 100}},
+            "schema": {"$ref": "#/definitions/Pet"}
+          },
+          "404": {"$ref": "#/responses/NotFound"}
+        }
+      }
+    }
+  },
+  "responses": {
+    "NotFound": {"description": "not found", "schema": {"type": "object", "required": ["message"]}}
+  },
+  "definitions": {
+    "Pet": {
+      "type": "object",
+      "required": ["name"],
+      "properties": {
+        "name": {"type": "string"},
+        "status": {"type": "string", "enum": ["available", "sold"]}
+      }
+    }
+  }
+}`
+
+func responseEngine(t *testing.T, opts ResponseOpts, handler gin.HandlerFunc) *gin.Engine {
+	doc, err := spec.New(json.RawMessage(petResponses), "")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(ResponseValidator(doc, opts))
+	engine.GET("/pets/:id", handler)
+	return engine
+}
+
+func TestResponseValidator_LogsMismatches(t *testing.T) {
+	var logged []string
+	opts := ResponseOpts{Logf: func(format string, args ...interface{}) {
+		logged = append(logged, fmt.Sprintf(format, args...))
+	}}
+	engine := responseEngine(t, opts, func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "lost"})
+	})
+
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, httptest.NewRequest("GET", "/pets/1", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"status": "lost"}`, rec.Body.String())
+	assert.Len(t, logged, 1)
+}
+
+func TestResponseValidator_Fail(t *testing.T) {
+	opts := ResponseOpts{Fail: true, Logf: func(string, ...interface{}) {}}
+
+	engine := responseEngine(t, opts, func(c *gin.Context) {
+		c.Header("X-Rate-Limit", "500")
+		c.JSON(http.StatusOK, gin.H{"status": "lost"})
+	})
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, httptest.NewRequest("GET", "/pets/1", nil))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	var body errors.ErrorEnvelope
+	if assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body)) && assert.Len(t, body.Errors, 3) {
+		assert.Equal(t, "X-Rate-Limit", body.Errors[0].Name)
+		assert.Equal(t, "name", body.Errors[1].Name)
+		assert.Equal(t, "status", body.Errors[2].Name)
+	}
+
+	engine = responseEngine(t, opts, func(c *gin.Context) {
+		c.JSON(http.StatusNotFound, gin.H{})
+	})
+	rec = httptest.NewRecorder()
+	engine.ServeHTTP(rec, httptest.NewRequest("GET", "/pets/1", nil))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+
+	engine = responseEngine(t, opts, func(c *gin.Context) {
+		c.JSON(http.StatusConflict, gin.H{"message": "taken"})
+	})
+	rec = httptest.NewRecorder()
+	engine.ServeHTTP(rec, httptest.NewRequest("GET", "/pets/1", nil))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+
+	engine = responseEngine(t, opts, func(c *gin.Context) {
+		c.Header("X-Rate-Limit", "10")
+		c.JSON(http.StatusOK, gin.H{"name": "kitty"})
+	})
+	rec = httptest.NewRecorder()
+	engine.ServeHTTP(rec, httptest.NewRequest("GET", "/pets/1", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "10", rec.Header().Get("X-Rate-Limit"))
+	assert.JSONEq(t, `{"name": "kitty"}`, rec.Body.String())
+}
+
+func TestResponseValidator_FailResetsHeaders(t *testing.T) {
+	doc, err := spec.New(json.RawMessage(petResponses), "")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(func(c *gin.Context) {
+		c.Header("X-Request-Id", "42")
+	})
+	engine.Use(ResponseValidator(doc, ResponseOpts{Fail: true, Logf: func(string, ...interface{}) {}}))
+	engine.GET("/pets/:id", func(c *gin.Context) {
+		c.Header("X-Rate-Limit", "10")
+		c.Header("Content-Length", "17")
+		c.Data(http.StatusOK, "application/json", []byte(`{"status":"lost"}`))
+	})
+
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, httptest.NewRequest("GET", "/pets/1", nil))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, "application/json; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.NotContains(t, rec.Body.String(), `{"status":"lost"}`)
+	assert.Empty(t, rec.Header().Get("Content-Length"))
+	assert.Empty(t, rec.Header().Get("X-Rate-Limit"))
+	assert.Equal(t, "42", rec.Header().Get("X-Request-Id"))
+	var body errors.ErrorEnvelope
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+}
+
+func TestResponseValidator_DisabledInRelease(t *testing.T) {
+	doc, err := spec.New(json.RawMessage(petResponses), "")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	gin.SetMode(gin.ReleaseMode)
+	defer gin.SetMode(gin.TestMode)
+
+	engine := gin.New()
+	engine.Use(ResponseValidator(doc, ResponseOpts{Fail: true}))
+	engine.GET("/pets/:id", func(c *gin.Context) {
+		c.JSON(http.StatusTeapot, gin.H{})
+	})
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, httptest.NewRequest("GET", "/pets/1", nil))
+	assert.Equal(t, http.StatusTeapot, rec.Code)
+}
@@ -0,0 +1,198 @@
+package middleware
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aiyi/swagger-gin/jsonpointer"
+	"github.com/aiyi/swagger-gin/spec"
+)
+
+// the maximum number of $ref hops followed when resolving parameters and responses
+const maxRefChain = 32
+
+var pathParamRx = regexp.MustCompile(`\{([^{}/]+)\}`)
+
+// route is a single operation from the spec together with the matcher for its path
+type route struct {
+	Method     string
+	Path       string
+	Operation  *spec.Operation
+	Parameters []spec.Parameter
+	Consumes   []string
+	Produces   []string
+
+	matcher  *regexp.Regexp
+	names    []string
+	literals int
+}
+
+// routeMatch is the result of matching a request against the routes of a spec
+type routeMatch struct {
+	*route
+	Params map[string]string
+}
+
+// router matches requests to the operations described in a spec document,
+// independently of the way the handlers were registered on the gin engine
+type router struct {
+	doc    *spec.Document
+	routes []*route
+}
+
+func newRouter(doc *spec.Document) *router {
+	r := &router{doc: doc}
+	basePath := strings.TrimSuffix(doc.BasePath(), "/")
+
+	for path, item := range doc.AllPaths() {
+		for method, op := range operationsOf(item) {
+			rt := &route{
+				Method:     method,
+				Path:       path,
+				Operation:  op,
+				Parameters: r.mergeParams(item.Parameters, op.Parameters),
+				Consumes:   mediaTypes(op.Consumes, doc.Spec().Consumes),
+				Produces:   mediaTypes(op.Produces, doc.Spec().Produces),
+			}
+			rt.compile(basePath + path)
+			r.routes = append(r.routes, rt)
+		}
+	}
+
+	// the most specific paths are tried first, so /pets/mine wins over /pets/{id}
+	sort.Slice(r.routes, func(i, j int) bool {
+		if r.routes[i].literals != r.routes[j].literals {
+			return r.routes[i].literals > r.routes[j].literals
+		}
+		return r.routes[i].Path < r.routes[j].Path
+	})
+	return r
+}
+
+func operationsOf(item spec.PathItem) map[string]*spec.Operation {
+	ops := map[string]*spec.Operation{
+		http.MethodGet:     item.Get,
+		http.MethodPut:     item.Put,
+		http.MethodPost:    item.Post,
+		http.MethodPatch:   item.Patch,
+		http.MethodDelete:  item.Delete,
+		http.MethodHead:    item.Head,
+		http.MethodOptions: item.Options,
+	}
+	for k, v := range ops {
+		if v == nil {
+			delete(ops, k)
+		}
+	}
+	return ops
+}
+
+// mediaTypes returns the media types declared on the operation, which replace the global ones
+func mediaTypes(operation, global []string) []string {
+	if len(operation) > 0 {
+		return operation
+	}
+	return global
+}
+
+func (rt *route) compile(path string) {
+	var expr strings.Builder
+	expr.WriteString("^")
+	last := 0
+	for _, loc := range pathParamRx.FindAllStringSubmatchIndex(path, -1) {
+		literal := path[last:loc[0]]
+		rt.literals += len(literal)
+		expr.WriteString(regexp.QuoteMeta(literal))
+		expr.WriteString("([^/]+)")
+		rt.names = append(rt.names, path[loc[2]:loc[3]])
+		last = loc[1]
+	}
+	rt.literals += len(path) - last
+	expr.WriteString(regexp.QuoteMeta(path[last:]))
+	expr.WriteString("/?$")
+	rt.matcher = regexp.MustCompile(expr.String())
+}
+
+// mergeParams resolves the parameter references and lets the operation parameters
+// override the path item parameters with the same name and location
+func (r *router) mergeParams(pathParams, opParams []spec.Parameter) []spec.Parameter {
+	var result []spec.Parameter
+	index := make(map[string]int)
+	for _, params := range [][]spec.Parameter{pathParams, opParams} {
+		for _, p := range params {
+			param := r.resolveParam(p)
+			key := param.In + "#" + param.Name
+			if i, ok := index[key]; ok {
+				result[i] = param
+				continue
+			}
+			index[key] = len(result)
+			result = append(result, param)
+		}
+	}
+	return result
+}
+
+func (r *router) resolveParam(param spec.Parameter) spec.Parameter {
+	for i := 0; param.Ref.String() != "" && i < maxRefChain; i++ {
+		name, ok := localRef(&param.Ref, "parameters")
+		if !ok {
+			return param
+		}
+		resolved, ok := r.doc.Spec().Parameters[name]
+		if !ok {
+			return param
+		}
+		param = resolved
+	}
+	return param
+}
+
+func (r *router) resolveResponse(response spec.Response) spec.Response {
+	for i := 0; response.Ref.String() != "" && i < maxRefChain; i++ {
+		name, ok := localRef(&response.Ref, "responses")
+		if !ok {
+			return response
+		}
+		resolved, ok := r.doc.Spec().Responses[name]
+		if !ok {
+			return response
+		}
+		response = resolved
+	}
+	return response
+}
+
+// localRef returns the name of the entry a reference like #/parameters/name points to
+func localRef(ref *spec.Ref, section string) (string, bool) {
+	ptr, err := jsonpointer.New(ref.GetURL().Fragment)
+	if err != nil {
+		return "", false
+	}
+	tokens := ptr.DecodedTokens()
+	if len(tokens) != 2 || tokens[0] != section {
+		return "", false
+	}
+	return tokens[1], true
+}
+
+// Lookup finds the operation for the method and path of a request
+func (r *router) Lookup(method, path string) (*routeMatch, bool) {
+	for _, rt := range r.routes {
+		if rt.Method != method {
+			continue
+		}
+		values := rt.matcher.FindStringSubmatch(path)
+		if values == nil {
+			continue
+		}
+		params := make(map[string]string, len(rt.names))
+		for i, name := range rt.names {
+			params[name] = values[i+1]
+		}
+		return &routeMatch{route: rt, Params: params}, true
+	}
+	return nil, false
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/aiyi/swagger-gin/scan"
+)
+
+// scanCmd writes the spec of a gin application, scanned from its routes, handlers and types
+func scanCmd(args []string) int {
+	flags := flag.NewFlagSet("scan", flag.ExitOnError)
+	var opts scan.Opts
+	var packages []string
+	flags.StringVar(&opts.Dir, "dir", "", "the directory the packages are loaded from, the current directory by default")
+	flags.Var(&listFlag{values: &packages}, "package", "a pattern of the packages to scan, ./... by default, can be repeated")
+	flags.StringVar(&opts.BasePath, "base-path", "", "the base path of the API, stripped from the routes")
+	flags.StringVar(&opts.Title, "title", "", "the title of the API")
+	flags.StringVar(&opts.Version, "version", "1.0.0", "the version of the API")
+	output := flags.String("output", "swagger.json", "the file the spec is written to, - for the standard output")
+	flags.Parse(args)
+	opts.Packages = packages
+
+	sw, err := scan.Application(opts)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	data, err := json.MarshalIndent(sw, "", "  ")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	data = append(data, '\n')
+	if *output == "-" {
+		os.Stdout.Write(data)
+		return 0
+	}
+	if err := ioutil.WriteFile(*output, data, 0644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	fmt.Println("wrote", *output)
+	return 0
+}
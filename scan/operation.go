@@ -0,0 +1,551 @@
+package scan
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/aiyi/swagger-gin/spec"
+	"github.com/aiyi/swagger-gin/swag"
+	"golang.org/x/tools/go/packages"
+)
+
+// the methods of gin.Context reading a parameter, and where the parameter is
+var contextParams = map[string]string{
+	"Param":           "path",
+	"Query":           "query",
+	"DefaultQuery":    "query",
+	"GetQuery":        "query",
+	"QueryArray":      "query",
+	"GetQueryArray":   "query",
+	"PostForm":        "formData",
+	"DefaultPostForm": "formData",
+	"GetPostForm":     "formData",
+	"PostFormArray":   "formData",
+	"FormFile":        "formData",
+	"GetHeader":       "header",
+}
+
+// the methods of gin.Context binding the body, and the media type they consume
+var bodyBindings = map[string]string{
+	"Bind":               "",
+	"ShouldBind":         "",
+	"BindJSON":           "application/json",
+	"ShouldBindJSON":     "application/json",
+	"BindXML":            "application/xml",
+	"ShouldBindXML":      "application/xml",
+	"BindYAML":           "application/x-yaml",
+	"ShouldBindYAML":     "application/x-yaml",
+	"BindWith":           "",
+	"MustBindWith":       "",
+	"ShouldBindWith":     "",
+	"ShouldBindBodyWith": "",
+}
+
+// the methods of gin.Context binding parameters into a struct, the location and the tag naming them
+var paramBindings = map[string][2]string{
+	"BindQuery":        {"query", "form"},
+	"ShouldBindQuery":  {"query", "form"},
+	"BindUri":          {"path", "uri"},
+	"ShouldBindUri":    {"path", "uri"},
+	"BindHeader":       {"header", "header"},
+	"ShouldBindHeader": {"header", "header"},
+}
+
+// the methods of gin.Context rendering a response, and the media type they produce
+var renderers = map[string]string{
+	"JSON":                "application/json",
+	"IndentedJSON":        "application/json",
+	"SecureJSON":          "application/json",
+	"PureJSON":            "application/json",
+	"AsciiJSON":           "application/json",
+	"JSONP":               "application/json",
+	"AbortWithStatusJSON": "application/json",
+	"XML":                 "application/xml",
+	"YAML":                "application/x-yaml",
+	"String":              "text/plain",
+	"Data":                "",
+	"Status":              "",
+	"AbortWithStatus":     "",
+	"AbortWithError":      "",
+	"Redirect":            "",
+}
+
+// the functions converting a parameter from a string, and the type and format they convert to,
+// the format of the strconv functions depends on the bit size they are called with
+var converters = map[string][2]string{
+	"strconv.Atoi":        {"integer", "int64"},
+	"strconv.ParseInt":    {"integer", "int"},
+	"strconv.ParseUint":   {"integer", "uint"},
+	"strconv.ParseFloat":  {"number", ""},
+	"strconv.ParseBool":   {"boolean", ""},
+	"time.ParseDuration":  {"string", "duration"},
+	"swag.ConvertBool":    {"boolean", ""},
+	"swag.ConvertFloat32": {"number", "float"},
+	"swag.ConvertFloat64": {"number", "double"},
+	"swag.ConvertInt8":    {"integer", "int8"},
+	"swag.ConvertInt16":   {"integer", "int16"},
+	"swag.ConvertInt32":   {"integer", "int32"},
+	"swag.ConvertInt64":   {"integer", "int64"},
+	"swag.ConvertUint8":   {"integer", "uint8"},
+	"swag.ConvertUint16":  {"integer", "uint16"},
+	"swag.ConvertUint32":  {"integer", "uint32"},
+	"swag.ConvertUint64":  {"integer", "uint64"},
+}
+
+// operationBuilder collects the parameters and responses of a handler
+type operationBuilder struct {
+	s        *scanner
+	op       *spec.Operation
+	params   map[string]*spec.Parameter
+	order    []string
+	vars     map[types.Object]*spec.Parameter
+	seen     map[*ast.CallExpr]*spec.Parameter
+	visited  map[ast.Node]bool
+	consumes map[string]bool
+	produces map[string]bool
+}
+
+// operation builds the operation of a route from its handler and doc comment
+func (s *scanner) operation(r route, path string, pathParams []string) *spec.Operation {
+	op := new(spec.Operation)
+	op.Responses = &spec.Responses{ResponsesProps: spec.ResponsesProps{StatusCodeResponses: make(map[int]spec.Response)}}
+
+	b := &operationBuilder{
+		s:        s,
+		op:       op,
+		params:   make(map[string]*spec.Parameter),
+		vars:     make(map[types.Object]*spec.Parameter),
+		seen:     make(map[*ast.CallExpr]*spec.Parameter),
+		visited:  make(map[ast.Node]bool),
+		consumes: make(map[string]bool),
+		produces: make(map[string]bool),
+	}
+	for _, name := range pathParams {
+		b.param(name, "path")
+	}
+	if r.handler != nil {
+		b.inspect(r.handler, r.pkg)
+	}
+
+	for _, key := range b.order {
+		p := b.params[key]
+		op.Parameters = append(op.Parameters, *p)
+		if p.In == "formData" && !b.consumes["multipart/form-data"] {
+			if p.Type == "file" {
+				delete(b.consumes, "application/x-www-form-urlencoded")
+				b.consumes["multipart/form-data"] = true
+			} else {
+				b.consumes["application/x-www-form-urlencoded"] = true
+			}
+		}
+	}
+	op.Consumes = sortedKeys(b.consumes)
+	op.Produces = sortedKeys(b.produces)
+	if len(op.Responses.StatusCodeResponses) == 0 && op.Responses.Default == nil {
+		var resp spec.Response
+		resp.Description = http.StatusText(http.StatusOK)
+		op.Responses.StatusCodeResponses[http.StatusOK] = resp
+	}
+
+	op.Summary, op.Description, op.Deprecated = docText(r.doc, r.name)
+	if fields := annotation(r.doc, "swagger:route"); len(fields) > 2 {
+		op.Tags = fields[2 : len(fields)-1]
+		op.ID = fields[len(fields)-1]
+	}
+	if op.ID == "" {
+		if r.name != "" {
+			name := strings.TrimSuffix(r.name, "Handler")
+			op.ID = strings.ToLower(name[:1]) + name[1:]
+		} else {
+			op.ID = strings.ToLower(r.method) + swag.ToGoName(strings.NewReplacer("{", "", "}", "").Replace(path))
+		}
+	}
+	if len(op.Tags) == 0 {
+		if segment := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 2)[0]; segment != "" && !strings.HasPrefix(segment, "{") {
+			op.Tags = []string{segment}
+		}
+	}
+	return op
+}
+
+// docText splits the doc comment of a handler into the summary and the description, the
+// name of the handler starting the comment is left out and a paragraph starting with
+// Deprecated: marks the operation deprecated. The annotations aren't part of the text.
+func docText(doc *ast.CommentGroup, name string) (summary, description string, deprecated bool) {
+	var paragraphs []string
+	for _, p := range strings.Split(strings.TrimSpace(doc.Text()), "\n\n") {
+		var lines []string
+		for _, line := range strings.Split(p, "\n") {
+			if !strings.HasPrefix(strings.TrimSpace(line), "swagger:") {
+				lines = append(lines, line)
+			}
+		}
+		p = strings.TrimSpace(strings.Join(lines, "\n"))
+		if strings.HasPrefix(p, "Deprecated:") {
+			deprecated = true
+			continue
+		}
+		if p != "" {
+			paragraphs = append(paragraphs, p)
+		}
+	}
+	if len(paragraphs) == 0 {
+		return "", "", deprecated
+	}
+	summary = paragraphs[0]
+	if name != "" && strings.HasPrefix(summary, name+" ") {
+		summary = strings.TrimPrefix(summary, name+" ")
+		r := []rune(summary)
+		r[0] = unicode.ToUpper(r[0])
+		summary = string(r)
+	}
+	return summary, strings.Join(paragraphs[1:], "\n\n"), deprecated
+}
+
+// param returns the parameter of a handler with a name and location, adding it when it's new
+func (b *operationBuilder) param(name, in string) *spec.Parameter {
+	key := in + ":" + name
+	if p, ok := b.params[key]; ok {
+		return p
+	}
+	var p *spec.Parameter
+	switch in {
+	case "path":
+		p = spec.PathParam(name)
+	case "header":
+		p = spec.HeaderParam(name).AsOptional()
+	case "formData":
+		p = spec.FormDataParam(name)
+	default:
+		p = spec.QueryParam(name)
+	}
+	p.Typed("string", "")
+	b.params[key] = p
+	b.order = append(b.order, key)
+	return p
+}
+
+// inspect walks a handler, and the functions of the scanned packages it calls, for
+// the parameters it reads, the body it binds and the responses it renders
+func (b *operationBuilder) inspect(node ast.Node, pkg *packages.Package) {
+	if b.visited[node] {
+		return
+	}
+	b.visited[node] = true
+	info := pkg.TypesInfo
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.AssignStmt:
+			// the variables holding a parameter, to find the conversions of the parameter
+			for i, rhs := range x.Rhs {
+				call, ok := ast.Unparen(rhs).(*ast.CallExpr)
+				if !ok {
+					continue
+				}
+				if p := b.call(call, pkg); p != nil {
+					if obj := objectOf(info, x.Lhs[i]); obj != nil {
+						b.vars[obj] = p
+					}
+				}
+			}
+		case *ast.ValueSpec:
+			for i, value := range x.Values {
+				call, ok := ast.Unparen(value).(*ast.CallExpr)
+				if !ok {
+					continue
+				}
+				if p := b.call(call, pkg); p != nil && i < len(x.Names) {
+					b.vars[info.Defs[x.Names[i]]] = p
+				}
+			}
+		case *ast.CallExpr:
+			b.call(x, pkg)
+		}
+		return true
+	})
+}
+
+// call handles a call of a handler, and returns the parameter it reads if any
+func (b *operationBuilder) call(call *ast.CallExpr, pkg *packages.Package) *spec.Parameter {
+	if p, ok := b.seen[call]; ok {
+		return p
+	}
+	b.seen[call] = nil
+	info := pkg.TypesInfo
+	fn, ok := calledFunc(info, call)
+	if !ok {
+		return nil
+	}
+	arg := func(i int) (string, bool) {
+		if i >= len(call.Args) {
+			return "", false
+		}
+		return constString(info, call.Args[i])
+	}
+
+	var p *spec.Parameter
+	switch {
+	case isMethodOf(fn, ginPath, "Context"):
+		p = b.contextCall(fn.Name(), call, pkg)
+	case isMethodOf(fn, "net/url", "Values") && fn.Name() == "Get":
+		if name, ok := arg(0); ok {
+			p = b.param(name, "query")
+		}
+	case isMethodOf(fn, "net/http", "Request") && (fn.Name() == "PostFormValue" || fn.Name() == "FormValue"):
+		if name, ok := arg(0); ok {
+			p = b.param(name, "formData")
+		}
+	case isMethodOf(fn, "net/http", "Request") && fn.Name() == "FormFile":
+		if name, ok := arg(0); ok {
+			p = b.param(name, "formData").Typed("file", "")
+		}
+	case isMethodOf(fn, "net/http", "Header") && fn.Name() == "Get":
+		if name, ok := arg(0); ok {
+			p = b.param(name, "header")
+		}
+	case isFuncOf(fn, errorsPath) && fn.Name() == "Required":
+		// the generated handlers report the missing required parameters with errors.Required
+		name, ok1 := arg(0)
+		in, ok2 := arg(1)
+		if ok1 && ok2 {
+			if param, ok := b.params[in+":"+name]; ok {
+				param.AsRequired()
+			}
+		}
+	case fn.Pkg() != nil && len(call.Args) > 0:
+		key := fn.Pkg().Name() + "." + fn.Name()
+		if fn.Pkg().Path() == swagPath {
+			key = "swag." + fn.Name()
+		}
+		if tpe, ok := converters[key]; ok {
+			if param := b.argParam(call.Args[0], pkg); param != nil && param.Type == "string" && param.Format == "" {
+				param.Typed(tpe[0], convertedFormat(key, tpe[1], call, info))
+			}
+		}
+	}
+	if decl, ok := b.s.funcs[fn]; ok {
+		b.inspect(decl.FuncDecl, decl.pkg)
+	}
+	b.seen[call] = p
+	return p
+}
+
+// argParam returns the parameter an argument holds, read into a variable or read by the argument itself
+func (b *operationBuilder) argParam(arg ast.Expr, pkg *packages.Package) *spec.Parameter {
+	if call, ok := ast.Unparen(arg).(*ast.CallExpr); ok {
+		return b.call(call, pkg)
+	}
+	return b.vars[objectOf(pkg.TypesInfo, arg)]
+}
+
+// convertedFormat is the format of a parameter converted with a function, the
+// format of the strconv functions depends on the bit size they are called with
+func convertedFormat(key, format string, call *ast.CallExpr, info *types.Info) string {
+	bits := int64(64)
+	if tv, ok := info.Types[call.Args[len(call.Args)-1]]; ok && tv.Value != nil && tv.Value.Kind() == constant.Int {
+		if b, ok := constant.Int64Val(tv.Value); ok && b > 0 {
+			bits = b
+		}
+	}
+	switch key {
+	case "strconv.ParseInt", "strconv.ParseUint":
+		return format + strconv.FormatInt(bits, 10)
+	case "strconv.ParseFloat":
+		if bits == 32 {
+			return "float"
+		}
+		return "double"
+	}
+	return format
+}
+
+// contextCall handles a call of a method of gin.Context
+func (b *operationBuilder) contextCall(method string, call *ast.CallExpr, pkg *packages.Package) *spec.Parameter {
+	info := pkg.TypesInfo
+	if in, ok := contextParams[method]; ok {
+		if len(call.Args) == 0 {
+			return nil
+		}
+		name, ok := constString(info, call.Args[0])
+		if !ok {
+			return nil
+		}
+		p := b.param(name, in)
+		switch method {
+		case "DefaultQuery", "DefaultPostForm":
+			if def, ok := constString(info, call.Args[len(call.Args)-1]); ok && len(call.Args) > 1 {
+				p.WithDefault(def)
+			}
+		case "QueryArray", "GetQueryArray", "PostFormArray":
+			p.CollectionOf(spec.NewItems().Typed("string", ""), "multi")
+		case "FormFile":
+			p.Typed("file", "")
+		}
+		return p
+	}
+
+	if mediaType, ok := bodyBindings[method]; ok && len(call.Args) > 0 {
+		schema := b.s.schemaFor(indirect(info.TypeOf(call.Args[0])))
+		if schema != nil {
+			key := "body:body"
+			if _, exists := b.params[key]; !exists {
+				p := spec.BodyParam("body", schema).AsRequired()
+				// the body has a schema instead of a type
+				p.Type = ""
+				b.params[key] = p
+				b.order = append(b.order, key)
+			}
+		}
+		if mediaType == "" {
+			mediaType = "application/json"
+		}
+		b.consumes[mediaType] = true
+		return nil
+	}
+
+	if binding, ok := paramBindings[method]; ok && len(call.Args) > 0 {
+		b.bindParams(indirect(info.TypeOf(call.Args[0])), binding[0], binding[1])
+		return nil
+	}
+
+	if mediaType, ok := renderers[method]; ok {
+		b.response(method, mediaType, call, info)
+	}
+	return nil
+}
+
+// bindParams adds a parameter for every field of a struct bound from the query, the path or the headers
+func (b *operationBuilder) bindParams(t types.Type, in, tagName string) {
+	st, ok := t.Underlying().(*types.Struct)
+	if !ok {
+		return
+	}
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		tag := reflect.StructTag(st.Tag(i))
+		if field.Embedded() {
+			b.bindParams(indirect(field.Type()), in, tagName)
+			continue
+		}
+		if !field.Exported() {
+			continue
+		}
+		name := strings.Split(tag.Get(tagName), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name()
+		}
+		schema := b.s.schemaFor(field.Type())
+		if schema == nil {
+			continue
+		}
+		required := applyRules(schema, tag.Get("binding")) || applyRules(schema, tag.Get("validate"))
+		p := b.param(name, in)
+		simpleSchema(p, schema)
+		if required || in == "path" {
+			p.AsRequired()
+		}
+		if def, ok := tag.Lookup("default"); ok {
+			p.WithDefault(def)
+		}
+		p.Description = b.s.docs[field.Pos()]
+	}
+}
+
+// simpleSchema sets the type and validations of a parameter from a schema, a schema
+// that isn't a primitive or an array of primitives is read as a string
+func simpleSchema(p *spec.Parameter, schema *spec.Schema) {
+	if len(schema.Type) == 0 || schema.Type[0] == "object" {
+		p.Typed("string", "")
+		return
+	}
+	p.Typed(schema.Type[0], schema.Format)
+	p.Enum = schema.Enum
+	p.Maximum, p.ExclusiveMaximum = schema.Maximum, schema.ExclusiveMaximum
+	p.Minimum, p.ExclusiveMinimum = schema.Minimum, schema.ExclusiveMinimum
+	p.MaxLength, p.MinLength, p.Pattern = schema.MaxLength, schema.MinLength, schema.Pattern
+	p.MaxItems, p.MinItems, p.UniqueItems = schema.MaxItems, schema.MinItems, schema.UniqueItems
+	if schema.Type[0] == "array" && schema.Items != nil && schema.Items.Schema != nil {
+		items := schema.Items.Schema
+		tpe, format := "string", ""
+		if len(items.Type) > 0 && items.Type[0] != "object" && items.Type[0] != "array" {
+			tpe, format = items.Type[0], items.Format
+		}
+		collectionFormat := "multi"
+		if p.In == "path" || p.In == "header" {
+			collectionFormat = "csv"
+		}
+		p.CollectionOf(spec.NewItems().Typed(tpe, format), collectionFormat)
+	}
+}
+
+// response adds the response a render call of gin.Context writes
+func (b *operationBuilder) response(method, mediaType string, call *ast.CallExpr, info *types.Info) {
+	if len(call.Args) == 0 {
+		return
+	}
+	code := -1
+	if tv, ok := info.Types[call.Args[0]]; ok && tv.Value != nil && tv.Value.Kind() == constant.Int {
+		if c, ok := constant.Int64Val(tv.Value); ok {
+			code = int(c)
+		}
+	}
+
+	var schema *spec.Schema
+	if mediaType != "" && mediaType != "text/plain" && len(call.Args) > 1 {
+		if t := info.TypeOf(call.Args[len(call.Args)-1]); t != nil {
+			if basic, ok := t.(*types.Basic); !ok || basic.Kind() != types.UntypedNil {
+				schema = b.s.schemaFor(t)
+			}
+		}
+	}
+	if method == "Data" && len(call.Args) > 1 {
+		mediaType, _ = constString(info, call.Args[1])
+	}
+	if mediaType != "" {
+		b.produces[mediaType] = true
+	}
+
+	responses := b.op.Responses
+	if code < 0 {
+		if responses.Default == nil {
+			responses.Default = &spec.Response{}
+			responses.Default.Description = "unexpected error"
+			responses.Default.Schema = schema
+		}
+		return
+	}
+	resp, exists := responses.StatusCodeResponses[code]
+	if !exists {
+		resp.Description = http.StatusText(code)
+	}
+	if resp.Schema == nil {
+		resp.Schema = schema
+	}
+	responses.StatusCodeResponses[code] = resp
+}
+
+func indirect(t types.Type) types.Type {
+	if p, ok := t.(*types.Pointer); ok {
+		return p.Elem()
+	}
+	return t
+}
+
+func sortedKeys(m map[string]bool) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,496 @@
+// Package scan builds a swagger spec from the code of a gin application, for the
+// teams who write the handlers first.
+//
+// The packages are loaded with go/packages and walked for the routes registered on
+// gin engines and router groups. The handlers of the routes are analyzed for the
+// parameters they read from the context, the bodies they bind and the responses they
+// render, and the Go types of the bodies and responses become definitions.
+//
+// The doc comment of a handler gives the summary and description of its operation,
+// a paragraph starting with Deprecated: marks it deprecated and a line
+//
+//	swagger:route METHOD /path [tag...] operationId
+//
+// sets the tags and operationId, or registers a handler the scanner can't find
+// a registration for. By default the operationId is the name of the handler,
+// without a Handler suffix, and the tag is the first segment of the path.
+package scan
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/aiyi/swagger-gin/spec"
+	"golang.org/x/tools/go/packages"
+)
+
+const (
+	ginPath    = "github.com/gin-gonic/gin"
+	errorsPath = "github.com/aiyi/swagger-gin/errors"
+	swagPath   = "github.com/aiyi/swagger-gin/swag"
+)
+
+// the methods of gin routers which register a route for a single http method
+var routeMethods = map[string]string{
+	"GET":     http.MethodGet,
+	"POST":    http.MethodPost,
+	"PUT":     http.MethodPut,
+	"DELETE":  http.MethodDelete,
+	"PATCH":   http.MethodPatch,
+	"HEAD":    http.MethodHead,
+	"OPTIONS": http.MethodOptions,
+}
+
+// Opts are the options of a scan
+type Opts struct {
+	// Dir is the directory the packages are loaded from, the current directory by default
+	Dir string
+	// Packages are the patterns of the packages to scan, ./... by default
+	Packages []string
+	// BasePath is stripped from the routes and set as the base path of the spec
+	BasePath string
+	// Title and Version are the title and version of the API
+	Title   string
+	Version string
+}
+
+// Application scans the packages for the routes of a gin application and returns their spec
+func Application(opts Opts) (*spec.Swagger, error) {
+	patterns := opts.Packages
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedSyntax |
+			packages.NeedTypes | packages.NeedTypesInfo | packages.NeedImports | packages.NeedDeps,
+		Dir: opts.Dir,
+	}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, err
+	}
+	var errs []string
+	for _, pkg := range pkgs {
+		for _, e := range pkg.Errors {
+			errs = append(errs, e.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("loading the packages failed:\n%s", strings.Join(errs, "\n"))
+	}
+
+	s := newScanner(pkgs)
+	s.collect()
+	routes := s.routes()
+
+	sw := &spec.Swagger{}
+	sw.Swagger = "2.0"
+	sw.Info = new(spec.Info)
+	sw.Info.Title, sw.Info.Version = opts.Title, opts.Version
+	sw.BasePath = opts.BasePath
+	sw.Paths = &spec.Paths{Paths: make(map[string]spec.PathItem)}
+	basePath := strings.TrimSuffix(opts.BasePath, "/")
+	for _, r := range routes {
+		path := r.path
+		// the paths of the annotations are relative to the base path already
+		if basePath != "" && !r.annotated {
+			if path != basePath && !strings.HasPrefix(path, basePath+"/") {
+				continue
+			}
+			path = joinPaths("", strings.TrimPrefix(path, basePath))
+		}
+		path, pathParams := specPath(path)
+		op := s.operation(r, path, pathParams)
+		item := sw.Paths.Paths[path]
+		switch r.method {
+		case http.MethodGet:
+			item.Get = op
+		case http.MethodPost:
+			item.Post = op
+		case http.MethodPut:
+			item.Put = op
+		case http.MethodDelete:
+			item.Delete = op
+		case http.MethodPatch:
+			item.Patch = op
+		case http.MethodHead:
+			item.Head = op
+		case http.MethodOptions:
+			item.Options = op
+		}
+		sw.Paths.Paths[path] = item
+	}
+	if len(s.definitions) > 0 {
+		sw.Definitions = s.definitions
+	}
+	return sw, nil
+}
+
+// expr is an expression with the type information of its package
+type expr struct {
+	ast.Expr
+	pkg *packages.Package
+}
+
+// funcDecl is a function declaration with the package declaring it
+type funcDecl struct {
+	*ast.FuncDecl
+	pkg *packages.Package
+}
+
+// route is a route registration, or a swagger:route annotation
+type route struct {
+	method  string
+	path    string
+	handler ast.Node
+	doc     *ast.CommentGroup
+	name    string
+	pkg     *packages.Package
+	pos     token.Pos
+	// annotated routes come from a swagger:route annotation, not from a registration
+	annotated bool
+}
+
+type scanner struct {
+	pkgs []*packages.Package
+	// funcs are the declarations of the functions of the scanned packages
+	funcs map[*types.Func]funcDecl
+	// values are the values assigned to the variables, fields and parameters holding routers
+	values map[types.Object]expr
+	// docs are the doc comments of the struct fields and named types
+	docs map[token.Pos]string
+
+	definitions spec.Definitions
+	names       map[*types.TypeName]string
+}
+
+func newScanner(pkgs []*packages.Package) *scanner {
+	return &scanner{
+		pkgs:        pkgs,
+		funcs:       make(map[*types.Func]funcDecl),
+		values:      make(map[types.Object]expr),
+		docs:        make(map[token.Pos]string),
+		definitions: make(spec.Definitions),
+		names:       make(map[*types.TypeName]string),
+	}
+}
+
+// collect indexes the function declarations, the docs and the values assigned to routers
+func (s *scanner) collect() {
+	for _, pkg := range s.pkgs {
+		for _, file := range pkg.Syntax {
+			for _, decl := range file.Decls {
+				if fd, ok := decl.(*ast.FuncDecl); ok {
+					if fn, ok := pkg.TypesInfo.Defs[fd.Name].(*types.Func); ok {
+						s.funcs[fn] = funcDecl{fd, pkg}
+					}
+				}
+			}
+		}
+	}
+
+	for _, pkg := range s.pkgs {
+		info := pkg.TypesInfo
+		for _, file := range pkg.Syntax {
+			ast.Inspect(file, func(n ast.Node) bool {
+				switch node := n.(type) {
+				case *ast.GenDecl:
+					for _, sp := range node.Specs {
+						switch sp := sp.(type) {
+						case *ast.TypeSpec:
+							doc := sp.Doc
+							if doc == nil && len(node.Specs) == 1 {
+								doc = node.Doc
+							}
+							s.docs[sp.Name.Pos()] = doc.Text()
+						case *ast.ValueSpec:
+							for i, name := range sp.Names {
+								if i < len(sp.Values) && isRouter(info.TypeOf(sp.Values[i])) {
+									s.values[info.Defs[name]] = expr{sp.Values[i], pkg}
+								}
+							}
+						}
+					}
+				case *ast.Field:
+					doc := node.Doc
+					if doc == nil {
+						doc = node.Comment
+					}
+					for _, name := range node.Names {
+						s.docs[name.Pos()] = strings.TrimSpace(doc.Text())
+					}
+				case *ast.AssignStmt:
+					if len(node.Lhs) != len(node.Rhs) {
+						break
+					}
+					for i, lhs := range node.Lhs {
+						if obj := objectOf(info, lhs); obj != nil && isRouter(info.TypeOf(node.Rhs[i])) {
+							if _, seen := s.values[obj]; !seen {
+								s.values[obj] = expr{node.Rhs[i], pkg}
+							}
+						}
+					}
+				case *ast.CallExpr:
+					// the routers passed to the functions which register routes on them
+					fn, ok := calledFunc(info, node)
+					if !ok {
+						break
+					}
+					sig := fn.Type().(*types.Signature)
+					for i, arg := range node.Args {
+						if i < sig.Params().Len() && isRouter(info.TypeOf(arg)) {
+							if _, seen := s.values[sig.Params().At(i)]; !seen {
+								s.values[sig.Params().At(i)] = expr{arg, pkg}
+							}
+						}
+					}
+				}
+				return true
+			})
+		}
+	}
+}
+
+// routes finds the route registrations and the swagger:route annotations, sorted by their position
+func (s *scanner) routes() []route {
+	var routes []route
+	registered := make(map[*types.Func]bool)
+	for _, pkg := range s.pkgs {
+		info := pkg.TypesInfo
+		for _, file := range pkg.Syntax {
+			ast.Inspect(file, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				sel, ok := call.Fun.(*ast.SelectorExpr)
+				if !ok || !isGinMethod(info, sel) {
+					return true
+				}
+				var method string
+				var args []ast.Expr
+				if m, ok := routeMethods[sel.Sel.Name]; ok && len(call.Args) >= 2 {
+					method, args = m, call.Args
+				} else if sel.Sel.Name == "Handle" && len(call.Args) >= 3 {
+					m, ok := constString(info, call.Args[0])
+					if !ok {
+						return true
+					}
+					method, args = strings.ToUpper(m), call.Args[1:]
+				} else {
+					return true
+				}
+				path, ok := constString(info, args[0])
+				if !ok {
+					return true
+				}
+
+				r := route{
+					method: method,
+					path:   joinPaths(s.prefix(expr{sel.X, pkg}, nil), path),
+					pkg:    pkg,
+					pos:    call.Pos(),
+				}
+				handler := ast.Unparen(args[len(args)-1])
+				if lit, ok := handler.(*ast.FuncLit); ok {
+					r.handler = lit
+				} else if fn, ok := s.handlerFunc(info, handler); ok {
+					registered[fn] = true
+					decl := s.funcs[fn]
+					r.handler, r.doc, r.name, r.pkg = decl.FuncDecl, decl.Doc, decl.Name.Name, decl.pkg
+				}
+				routes = append(routes, r)
+				return true
+			})
+		}
+	}
+
+	for fn, decl := range s.funcs {
+		if registered[fn] {
+			continue
+		}
+		if method, path, ok := routeAnnotation(decl.Doc); ok {
+			routes = append(routes, route{
+				method:  method,
+				path:    path,
+				handler: decl.FuncDecl,
+				doc:     decl.Doc,
+				name:    decl.Name.Name,
+				pkg:     decl.pkg,
+				pos:     decl.Pos(),
+
+				annotated: true,
+			})
+		}
+	}
+
+	sort.SliceStable(routes, func(i, j int) bool {
+		pi := routes[i].pkg.Fset.Position(routes[i].pos)
+		pj := routes[j].pkg.Fset.Position(routes[j].pos)
+		if pi.Filename != pj.Filename {
+			return pi.Filename < pj.Filename
+		}
+		return pi.Offset < pj.Offset
+	})
+	return routes
+}
+
+// handlerFunc returns the function a handler refers to, or the function returning it
+func (s *scanner) handlerFunc(info *types.Info, handler ast.Expr) (*types.Func, bool) {
+	var fn *types.Func
+	switch h := handler.(type) {
+	case *ast.Ident, *ast.SelectorExpr:
+		fn, _ = objectOf(info, h).(*types.Func)
+	case *ast.CallExpr:
+		fn, _ = calledFunc(info, h)
+	}
+	if fn == nil {
+		return nil, false
+	}
+	_, ok := s.funcs[fn]
+	return fn, ok
+}
+
+// prefix evaluates the path prefix of a router, the engine has none
+func (s *scanner) prefix(e expr, seen map[types.Object]bool) string {
+	info := e.pkg.TypesInfo
+	switch x := ast.Unparen(e.Expr).(type) {
+	case *ast.CallExpr:
+		sel, ok := x.Fun.(*ast.SelectorExpr)
+		if !ok || !isGinMethod(info, sel) || sel.Sel.Name != "Group" || len(x.Args) == 0 {
+			return ""
+		}
+		path, _ := constString(info, x.Args[0])
+		return joinPaths(s.prefix(expr{sel.X, e.pkg}, seen), path)
+	case *ast.UnaryExpr:
+		return s.prefix(expr{x.X, e.pkg}, seen)
+	case *ast.Ident, *ast.SelectorExpr:
+		obj := objectOf(info, x)
+		value, ok := s.values[obj]
+		if !ok || seen[obj] {
+			return ""
+		}
+		if seen == nil {
+			seen = make(map[types.Object]bool)
+		}
+		seen[obj] = true
+		return s.prefix(value, seen)
+	}
+	return ""
+}
+
+func joinPaths(prefix, path string) string {
+	if path == "" || path == "/" {
+		if prefix == "" {
+			return "/"
+		}
+		return prefix
+	}
+	return strings.TrimSuffix(prefix, "/") + "/" + strings.TrimPrefix(path, "/")
+}
+
+// specPath turns the parameters of a gin path into path templates, :id and *rest become {id} and {rest}
+func specPath(path string) (string, []string) {
+	segments := strings.Split(path, "/")
+	var params []string
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			params = append(params, segment[1:])
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
+
+// routeAnnotation reads the method and path of a swagger:route annotation
+func routeAnnotation(doc *ast.CommentGroup) (string, string, bool) {
+	fields := annotation(doc, "swagger:route")
+	if len(fields) < 2 {
+		return "", "", false
+	}
+	return strings.ToUpper(fields[0]), fields[1], true
+}
+
+// annotation returns the fields following an annotation in a doc comment
+func annotation(doc *ast.CommentGroup, name string) []string {
+	for _, line := range strings.Split(doc.Text(), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) > 0 && fields[0] == name {
+			return fields[1:]
+		}
+	}
+	return nil
+}
+
+func objectOf(info *types.Info, e ast.Expr) types.Object {
+	switch x := ast.Unparen(e).(type) {
+	case *ast.Ident:
+		if obj := info.Uses[x]; obj != nil {
+			return obj
+		}
+		return info.Defs[x]
+	case *ast.SelectorExpr:
+		return info.Uses[x.Sel]
+	}
+	return nil
+}
+
+func calledFunc(info *types.Info, call *ast.CallExpr) (*types.Func, bool) {
+	fn, ok := objectOf(info, call.Fun).(*types.Func)
+	return fn, ok
+}
+
+func constString(info *types.Info, e ast.Expr) (string, bool) {
+	tv, ok := info.Types[e]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(tv.Value), true
+}
+
+// isRouter tells whether values of a type can register routes, like gin engines and router groups
+func isRouter(t types.Type) bool {
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil || named.Obj().Pkg().Path() != ginPath {
+		return false
+	}
+	switch named.Obj().Name() {
+	case "Engine", "RouterGroup", "IRouter", "IRoutes":
+		return true
+	}
+	return false
+}
+
+// isGinMethod tells whether a selector is a method of the gin package
+func isGinMethod(info *types.Info, sel *ast.SelectorExpr) bool {
+	fn, ok := info.Uses[sel.Sel].(*types.Func)
+	return ok && fn.Pkg() != nil && fn.Pkg().Path() == ginPath && fn.Type().(*types.Signature).Recv() != nil
+}
+
+// isMethodOf tells whether a function is a method of a type of a package
+func isMethodOf(fn *types.Func, pkgPath, typeName string) bool {
+	recv := fn.Type().(*types.Signature).Recv()
+	if recv == nil || fn.Pkg() == nil || fn.Pkg().Path() != pkgPath {
+		return false
+	}
+	t := recv.Type()
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	named, ok := t.(*types.Named)
+	return ok && named.Obj().Name() == typeName
+}
+
+// isFuncOf tells whether a function is a function of a package
+func isFuncOf(fn *types.Func, pkgPath string) bool {
+	return fn.Pkg() != nil && fn.Pkg().Path() == pkgPath && fn.Type().(*types.Signature).Recv() == nil
+}
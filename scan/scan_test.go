@@ -0,0 +1,161 @@
+package scan
+
+import (
+	"testing"
+
+	"github.com/aiyi/swagger-gin/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplication(t *testing.T) {
+	sw, err := Application(Opts{Dir: "testdata/app", Packages: []string{"."}, BasePath: "/api", Title: "pets", Version: "1.0"})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "/api", sw.BasePath)
+	assert.Len(t, sw.Paths.Paths, 4)
+
+	list := sw.Paths.Paths["/pets"].Get
+	if assert.NotNil(t, list) {
+		assert.Equal(t, "listAllPets", list.ID)
+		assert.Equal(t, []string{"pets", "store"}, list.Tags)
+		assert.Equal(t, "Lists the pets", list.Summary)
+		assert.Equal(t, "The pets are sorted by name.", list.Description)
+		if assert.Len(t, list.Parameters, 2) {
+			assert.Equal(t, "limit", list.Parameters[0].Name)
+			assert.Equal(t, "integer", list.Parameters[0].Type)
+			assert.Equal(t, float64(100), *list.Parameters[0].Maximum)
+			assert.Equal(t, "array", list.Parameters[1].Type)
+			assert.Equal(t, "multi", list.Parameters[1].CollectionFormat)
+		}
+		ok := list.Responses.StatusCodeResponses[200]
+		assert.Equal(t, "#/definitions/Pet", ok.Schema.Items.Schema.Ref.String())
+		assert.Equal(t, "#/definitions/apiError", list.Responses.StatusCodeResponses[400].Schema.Ref.String())
+	}
+
+	add := sw.Paths.Paths["/pets"].Post
+	if assert.NotNil(t, add) {
+		assert.Equal(t, "addPet", add.ID)
+		assert.Equal(t, "Adds a pet to the store", add.Summary)
+		assert.True(t, add.Deprecated)
+		assert.Equal(t, []string{"pets"}, add.Tags)
+		assert.Equal(t, []string{"application/json"}, add.Consumes)
+		if assert.Len(t, add.Parameters, 1) {
+			assert.Equal(t, "body", add.Parameters[0].In)
+			assert.Equal(t, "#/definitions/Pet", add.Parameters[0].Schema.Ref.String())
+		}
+		assert.Contains(t, add.Responses.StatusCodeResponses, 201)
+	}
+
+	get := sw.Paths.Paths["/pets/{id}"].Get
+	if assert.NotNil(t, get) && assert.Len(t, get.Parameters, 3) {
+		assert.Equal(t, "getPet", get.ID)
+		id := get.Parameters[0]
+		assert.Equal(t, []string{"path", "id", "integer", "int32"}, []string{id.In, id.Name, id.Type, id.Format})
+		trace := get.Parameters[1]
+		assert.Equal(t, []string{"header", "X-Trace"}, []string{trace.In, trace.Name})
+		assert.False(t, trace.Required)
+		verbose := get.Parameters[2]
+		assert.Equal(t, "boolean", verbose.Type)
+		assert.Equal(t, "false", verbose.Default)
+		// the responses of the functions the handler calls
+		assert.Contains(t, get.Responses.StatusCodeResponses, 400)
+	}
+
+	health := sw.Paths.Paths["/health"].Get
+	if assert.NotNil(t, health) {
+		assert.Equal(t, "getHealth", health.ID)
+		assert.Contains(t, health.Responses.StatusCodeResponses, 204)
+	}
+
+	imp := sw.Paths.Paths["/pets/import"].Post
+	if assert.NotNil(t, imp) && assert.Len(t, imp.Parameters, 1) {
+		assert.Equal(t, "importPets", imp.ID)
+		assert.Equal(t, "file", imp.Parameters[0].Type)
+		assert.Equal(t, []string{"multipart/form-data"}, imp.Consumes)
+	}
+
+	pet := sw.Definitions["Pet"]
+	assert.Equal(t, "Pet is a pet of the store", pet.Description)
+	if assert.Len(t, pet.AllOf, 2) {
+		assert.Equal(t, "#/definitions/Entity", pet.AllOf[0].Ref.String())
+		props := pet.AllOf[1]
+		assert.Equal(t, []string{"name"}, props.Required)
+		assert.NotContains(t, props.Properties, "secret")
+		name := props.Properties["name"]
+		assert.Equal(t, "Name is how the pet is called", name.Description)
+		assert.Equal(t, int64(1), *name.MinLength)
+		assert.Equal(t, int64(30), *name.MaxLength)
+		assert.Equal(t, []interface{}{"available", "sold"}, props.Properties["status"].Enum)
+		tags := props.Properties["tags"]
+		assert.Equal(t, int64(5), *tags.MaxItems)
+		assert.Equal(t, int64(2), *tags.Items.Schema.MinLength)
+		assert.Equal(t, true, props.Properties["age"].Extensions["x-isnullable"])
+		ownerRef := props.Properties["owner"]
+		assert.Equal(t, "#/definitions/Owner", ownerRef.Ref.String())
+	}
+	owner := sw.Definitions["Owner"]
+	assert.Equal(t, "#/definitions/Pet", owner.Properties["pets"].Items.Schema.Ref.String())
+}
+
+func TestApplication_RoundTrip(t *testing.T) {
+	// the example is generated from example/petstore.json
+	sw, err := Application(Opts{Dir: "../example", BasePath: "/api"})
+	if !assert.NoError(t, err) {
+		return
+	}
+	doc, err := spec.Load("../example/petstore.json")
+	if !assert.NoError(t, err) {
+		return
+	}
+	orig := doc.Spec()
+
+	for path, item := range orig.Paths.Paths {
+		scanned, ok := sw.Paths.Paths[path]
+		if !assert.True(t, ok, path) {
+			continue
+		}
+		for _, ops := range [][2]*spec.Operation{
+			{item.Get, scanned.Get}, {item.Post, scanned.Post}, {item.Put, scanned.Put}, {item.Delete, scanned.Delete},
+		} {
+			if ops[0] == nil {
+				assert.Nil(t, ops[1])
+				continue
+			}
+			if !assert.NotNil(t, ops[1], path) {
+				continue
+			}
+			assert.Equal(t, ops[0].ID, ops[1].ID)
+			assert.Equal(t, ops[0].Tags[:1], ops[1].Tags)
+			// the generated handlers don't read the headers
+			var params []spec.Parameter
+			for _, p := range ops[0].Parameters {
+				if p.In != "header" {
+					params = append(params, p)
+				}
+			}
+			if assert.Len(t, ops[1].Parameters, len(params), ops[0].ID) {
+				for i, p := range params {
+					s := ops[1].Parameters[i]
+					assert.Equal(t, []string{p.Name, p.In, p.Type, p.Format}, []string{s.Name, s.In, s.Type, s.Format}, ops[0].ID)
+					if p.Schema != nil {
+						assert.Equal(t, p.Schema.Ref.String(), s.Schema.Ref.String())
+					}
+				}
+			}
+		}
+	}
+
+	for name, def := range orig.Definitions {
+		scanned, ok := sw.Definitions[name]
+		if !assert.True(t, ok, name) {
+			continue
+		}
+		assert.Len(t, scanned.Properties, len(def.Properties), name)
+		for prop, schema := range def.Properties {
+			scannedProp := scanned.Properties[prop]
+			assert.Equal(t, schema.Type, scannedProp.Type, name+"."+prop)
+			assert.Equal(t, schema.Ref.String(), scannedProp.Ref.String(), name+"."+prop)
+		}
+	}
+}
@@ -0,0 +1,356 @@
+package scan
+
+import (
+	"go/types"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/aiyi/swagger-gin/spec"
+	"github.com/aiyi/swagger-gin/swag"
+)
+
+// the formats of the types of the strfmt packages, the other types are named after their format
+var strfmtFormats = map[string]string{
+	"DateTime": "date-time",
+	"Base64":   "byte",
+}
+
+// the validation rules of the binding tags which set a format
+var ruleFormats = map[string]string{
+	"email":    "email",
+	"url":      "uri",
+	"uri":      "uri",
+	"uuid":     "uuid",
+	"uuid3":    "uuid3",
+	"uuid4":    "uuid4",
+	"uuid5":    "uuid5",
+	"ipv4":     "ipv4",
+	"ipv6":     "ipv6",
+	"hostname": "hostname",
+	"datetime": "date-time",
+}
+
+// schemaFor returns the schema of a Go type, the named struct types become definitions
+// and are referenced. Nil is returned for the types json can't encode, like functions.
+func (s *scanner) schemaFor(t types.Type) *spec.Schema {
+	switch tpe := t.(type) {
+	case *types.Pointer:
+		schema := s.schemaFor(tpe.Elem())
+		if schema != nil && schema.Ref.String() == "" && len(schema.Type) > 0 && schema.Type[0] != "object" && schema.Type[0] != "array" {
+			schema.AddExtension("x-isnullable", true)
+		}
+		return schema
+	case *types.Named:
+		return s.namedSchema(tpe)
+	case *types.Alias:
+		return s.schemaFor(types.Unalias(tpe))
+	case *types.Basic:
+		return basicSchema(tpe)
+	case *types.Slice:
+		if b, ok := tpe.Elem().(*types.Basic); ok && b.Kind() == types.Byte {
+			return new(spec.Schema).Typed("string", "byte")
+		}
+		items := s.schemaFor(tpe.Elem())
+		if items == nil {
+			return nil
+		}
+		return spec.ArrayProperty(items)
+	case *types.Array:
+		items := s.schemaFor(tpe.Elem())
+		if items == nil {
+			return nil
+		}
+		return spec.ArrayProperty(items).WithMinItems(tpe.Len()).WithMaxItems(tpe.Len())
+	case *types.Map:
+		values := s.schemaFor(tpe.Elem())
+		if values == nil {
+			return nil
+		}
+		return spec.MapProperty(values)
+	case *types.Interface:
+		return new(spec.Schema)
+	case *types.Struct:
+		return s.structSchema(tpe)
+	}
+	return nil
+}
+
+// namedSchema returns the schema of a named type, the well known types have their format
+func (s *scanner) namedSchema(t *types.Named) *spec.Schema {
+	obj := t.Obj()
+	if obj.Pkg() != nil {
+		switch obj.Pkg().Path() + "." + obj.Name() {
+		case "time.Time":
+			return spec.DateTimeProperty()
+		case "time.Duration":
+			return new(spec.Schema).Typed("integer", "int64")
+		case "encoding/json.RawMessage":
+			return new(spec.Schema)
+		case ginPath + ".H":
+			return spec.MapProperty(new(spec.Schema))
+		}
+		if obj.Pkg().Name() == "strfmt" {
+			format, ok := strfmtFormats[obj.Name()]
+			if !ok {
+				format = strings.ToLower(obj.Name())
+			}
+			return spec.StrFmtProperty(format)
+		}
+	}
+
+	st, ok := t.Underlying().(*types.Struct)
+	if !ok {
+		return s.schemaFor(t.Underlying())
+	}
+	name, exists := s.names[obj]
+	if !exists {
+		name = s.definitionName(t)
+		s.names[obj] = name
+		// the definition is added before it's built so recursive types reference it
+		s.definitions[name] = spec.Schema{}
+		schema := s.structSchema(st)
+		schema.Description = strings.TrimSpace(s.docs[obj.Pos()])
+		s.definitions[name] = *schema
+	}
+	return spec.RefProperty("#/definitions/" + name)
+}
+
+// definitionName names the definition of a type after the type, the types with the same
+// name in other packages are prefixed with their package
+func (s *scanner) definitionName(t *types.Named) string {
+	obj := t.Obj()
+	name := obj.Name()
+	if args := t.TypeArgs(); args != nil {
+		for i := 0; i < args.Len(); i++ {
+			if named, ok := args.At(i).(*types.Named); ok {
+				name += swag.ToGoName(named.Obj().Name())
+			} else {
+				name += swag.ToGoName(args.At(i).String())
+			}
+		}
+	}
+	if _, taken := s.definitions[name]; taken && obj.Pkg() != nil {
+		name = swag.ToGoName(obj.Pkg().Name()) + name
+	}
+	base := name
+	for i := 2; ; i++ {
+		if _, taken := s.definitions[name]; !taken {
+			return name
+		}
+		name = base + strconv.Itoa(i)
+	}
+}
+
+func basicSchema(t *types.Basic) *spec.Schema {
+	switch t.Kind() {
+	case types.Bool, types.UntypedBool:
+		return spec.BooleanProperty()
+	case types.String, types.UntypedString:
+		return spec.StringProperty()
+	case types.Int, types.Int64, types.UntypedInt:
+		return spec.Int64Property()
+	case types.Int8:
+		return spec.Int8Property()
+	case types.Int16:
+		return spec.Int16Property()
+	case types.Int32, types.UntypedRune:
+		return spec.Int32Property()
+	case types.Uint, types.Uint64, types.Uintptr:
+		return new(spec.Schema).Typed("integer", "uint64")
+	case types.Uint8:
+		return new(spec.Schema).Typed("integer", "uint8")
+	case types.Uint16:
+		return new(spec.Schema).Typed("integer", "uint16")
+	case types.Uint32:
+		return new(spec.Schema).Typed("integer", "uint32")
+	case types.Float32:
+		return spec.Float32Property()
+	case types.Float64, types.UntypedFloat:
+		return spec.Float64Property()
+	}
+	return nil
+}
+
+// structSchema returns the object schema of a struct, the properties are named by the
+// json tags and required by the binding or validate tags. The embedded structs are
+// flattened the way encoding/json does, the named ones become allOf members.
+func (s *scanner) structSchema(st *types.Struct) *spec.Schema {
+	schema := new(spec.Schema).Typed("object", "")
+	var allOf []spec.Schema
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		tag := reflect.StructTag(st.Tag(i))
+		name, opts := jsonName(field, tag)
+		if name == "-" {
+			continue
+		}
+		if field.Embedded() && name == "" {
+			embedded := s.schemaFor(field.Type())
+			switch {
+			case embedded == nil:
+			case embedded.Ref.String() != "":
+				allOf = append(allOf, *embedded)
+			default:
+				for k, v := range embedded.Properties {
+					if _, exists := schema.Properties[k]; !exists {
+						schema.SetProperty(k, v)
+					}
+				}
+				schema.Required = append(schema.Required, embedded.Required...)
+			}
+			continue
+		}
+		if !field.Exported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name()
+		}
+
+		prop := s.schemaFor(field.Type())
+		if prop == nil {
+			continue
+		}
+		if strings.Contains(opts, "string") && len(prop.Type) > 0 && prop.Type[0] != "object" && prop.Type[0] != "array" {
+			prop.Typed("string", "")
+		}
+		required := applyRules(prop, tag.Get("binding"))
+		required = applyRules(prop, tag.Get("validate")) || required
+		if required {
+			schema.Required = append(schema.Required, name)
+		}
+		if doc := s.docs[field.Pos()]; doc != "" && prop.Ref.String() == "" {
+			prop.Description = doc
+		}
+		schema.SetProperty(name, *prop)
+	}
+	if len(allOf) == 0 {
+		return schema
+	}
+	if len(schema.Properties) > 0 {
+		allOf = append(allOf, *schema)
+	}
+	return new(spec.Schema).WithAllOf(allOf...)
+}
+
+// jsonName returns the name and options of the json tag of a field
+func jsonName(field *types.Var, tag reflect.StructTag) (string, string) {
+	value, ok := tag.Lookup("json")
+	if !ok {
+		return "", ""
+	}
+	if value == "-" {
+		return "-", ""
+	}
+	parts := strings.SplitN(value, ",", 2)
+	if len(parts) == 1 {
+		return parts[0], ""
+	}
+	return parts[0], parts[1]
+}
+
+// applyRules applies the validation rules of a binding or validate tag to a schema, and
+// tells whether the rules require the value. The rules after dive apply to the items.
+func applyRules(schema *spec.Schema, rules string) bool {
+	required := false
+	target := schema
+	for _, rule := range strings.Split(rules, ",") {
+		name, arg := rule, ""
+		if i := strings.Index(rule, "="); i >= 0 {
+			name, arg = rule[:i], rule[i+1:]
+		}
+		switch name {
+		case "required":
+			if target == schema {
+				required = true
+			}
+		case "dive":
+			if target.Items == nil || target.Items.Schema == nil {
+				return required
+			}
+			target = target.Items.Schema
+		case "min", "gte":
+			bound(target, arg, true, false)
+		case "max", "lte":
+			bound(target, arg, false, false)
+		case "gt":
+			bound(target, arg, true, true)
+		case "lt":
+			bound(target, arg, false, true)
+		case "len":
+			bound(target, arg, true, false)
+			bound(target, arg, false, false)
+		case "oneof":
+			var values []interface{}
+			for _, v := range strings.Fields(arg) {
+				values = append(values, typedValue(target, v))
+			}
+			target.WithEnum(values...)
+		default:
+			if format, ok := ruleFormats[name]; ok && target.Ref.String() == "" {
+				target.Format = format
+			}
+		}
+	}
+	return required
+}
+
+// bound sets a lower or upper bound, which is a length for strings and arrays
+func bound(schema *spec.Schema, arg string, lower, exclusive bool) {
+	if schema.Ref.String() != "" || len(schema.Type) == 0 {
+		return
+	}
+	value, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		return
+	}
+	switch schema.Type[0] {
+	case "string":
+		if lower {
+			schema.WithMinLength(int64(value))
+		} else {
+			schema.WithMaxLength(int64(value))
+		}
+	case "array":
+		if lower {
+			schema.WithMinItems(int64(value))
+		} else {
+			schema.WithMaxItems(int64(value))
+		}
+	case "object":
+		if lower {
+			schema.WithMinProperties(int64(value))
+		} else {
+			schema.WithMaxProperties(int64(value))
+		}
+	case "integer", "number":
+		if lower {
+			schema.WithMinimum(value, exclusive)
+		} else {
+			schema.WithMaximum(value, exclusive)
+		}
+	}
+}
+
+// typedValue parses an enum value of a binding tag for the type of the schema
+func typedValue(schema *spec.Schema, value string) interface{} {
+	if len(schema.Type) == 0 {
+		return value
+	}
+	switch schema.Type[0] {
+	case "integer":
+		if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return i
+		}
+	case "number":
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return value
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Entity has the fields every stored object has
+type Entity struct {
+	ID int64 `json:"id"`
+}
+
+// Pet is a pet of the store
+type Pet struct {
+	Entity
+	// Name is how the pet is called
+	Name   string   `json:"name" binding:"required,min=1,max=30"`
+	Status string   `json:"status,omitempty" binding:"omitempty,oneof=available sold"`
+	Tags   []string `json:"tags,omitempty" binding:"max=5,dive,min=2"`
+	Age    *int32   `json:"age,omitempty"`
+	Owner  *Owner   `json:"owner,omitempty"`
+	secret string
+}
+
+type Owner struct {
+	Name string `json:"name"`
+	Pets []Pet  `json:"pets,omitempty"`
+}
+
+type listQuery struct {
+	Limit  int      `form:"limit" binding:"max=100"`
+	Status []string `form:"status"`
+}
+
+type apiError struct {
+	Message string `json:"message"`
+}
+
+func main() {
+	r := gin.New()
+	api := r.Group("/api")
+	registerPets(api.Group("/pets"))
+	api.GET("/health", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	r.Run()
+}
+
+func registerPets(g *gin.RouterGroup) {
+	g.GET("", listPets)
+	g.POST("", AddPetHandler)
+	g.GET("/:id", getPet)
+}
+
+// listPets lists the pets
+//
+// The pets are sorted by name.
+//
+// swagger:route GET /pets pets store listAllPets
+func listPets(c *gin.Context) {
+	var q listQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, apiError{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, []Pet{})
+}
+
+// AddPetHandler adds a pet to the store
+//
+// Deprecated: pets are added by the import job.
+func AddPetHandler(c *gin.Context) {
+	var pet Pet
+	if err := c.ShouldBindJSON(&pet); err != nil {
+		c.JSON(http.StatusBadRequest, apiError{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, pet)
+}
+
+func getPet(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		abort(c, err)
+		return
+	}
+	_ = id
+	if c.GetHeader("X-Trace") != "" {
+		c.Header("X-Traced", "true")
+	}
+	verbose := c.DefaultQuery("verbose", "false")
+	if _, err := strconv.ParseBool(verbose); err != nil {
+		abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, &Pet{})
+}
+
+func abort(c *gin.Context, err error) {
+	c.AbortWithStatusJSON(http.StatusBadRequest, apiError{Message: err.Error()})
+}
+
+// importPets is registered by a router the scanner doesn't see
+//
+// swagger:route POST /pets/import pets importPets
+func importPets(c *gin.Context) {
+	file, _ := c.FormFile("file")
+	_ = file
+	c.String(http.StatusAccepted, "importing")
+}
@@ -0,0 +1,171 @@
+package spec
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuilders_Swagger(t *testing.T) {
+	pet := new(Schema).Typed("object", "").WithRequired("name")
+	pet.SetProperty("name", *StringProperty())
+
+	sw := NewSwagger("pets", "1.0").
+		WithBasePath("/api").
+		WithConsumes("application/json").
+		WithProduces("application/json").
+		AddDefinition("Pet", pet).
+		AddParameter("id", PathParam("id").Typed("integer", "int64")).
+		AddSecurityDefinition("key", APIKeyAuth("X-Key", "header")).
+		AddTag("pets", "the pets of the store").
+		AddPath("/pets/{id}", NewPathItem().
+			AddParam(ParamRef("#/parameters/id")).
+			WithOperation("GET", NewOperation("getPet").
+				WithTags("pets").
+				WithSummary("gets a pet").
+				SecuredWith("key").
+				RespondsWith(200, RefProperty("#/definitions/Pet")).
+				WithResponse(404, NewResponse().WithDescription("no such pet")))).
+		AddOperation("post", "/pets", NewOperation("addPet").
+			WithTags("pets").
+			AddParam(BodyParam("pet", RefProperty("#/definitions/Pet")).AsRequired()).
+			RespondsWith(201, nil))
+
+	data, err := json.Marshal(sw)
+	if !assert.NoError(t, err) {
+		return
+	}
+	// the body parameter has a schema and no type
+	assert.JSONEq(t, `{
+  "swagger": "2.0",
+  "info": {"title": "pets", "version": "1.0"},
+  "basePath": "/api",
+  "consumes": ["application/json"],
+  "produces": ["application/json"],
+  "paths": {
+    "/pets": {
+      "post": {
+        "operationId": "addPet",
+        "tags": ["pets"],
+        "parameters": [{"name": "pet", "in": "body", "required": true, "schema": {"$ref": "#/definitions/Pet"}}],
+        "responses": {"201": {"description": "Created"}}
+      }
+    },
+    "/pets/{id}": {
+      "parameters": [{"$ref": "#/parameters/id"}],
+      "get": {
+        "operationId": "getPet",
+        "tags": ["pets"],
+        "summary": "gets a pet",
+        "security": [{"key": []}],
+        "responses": {
+          "200": {"description": "OK", "schema": {"$ref": "#/definitions/Pet"}},
+          "404": {"description": "no such pet"}
+        }
+      }
+    }
+  },
+  "definitions": {
+    "Pet": {"type": "object", "required": ["name"], "properties": {"name": {"type": "string"}}}
+  },
+  "parameters": {
+    "id": {"name": "id", "in": "path", "required": true, "type": "integer", "format": "int64"}
+  },
+  "securityDefinitions": {
+    "key": {"type": "apiKey", "name": "X-Key", "in": "header"}
+  },
+  "tags": [{"name": "pets", "description": "the pets of the store"}]
+}`, string(data))
+
+	doc, err := New(data, "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	op, ok := doc.OperationFor("POST", "/pets")
+	if assert.True(t, ok) && assert.Len(t, op.Parameters, 1) {
+		assert.Equal(t, "body", op.Parameters[0].In)
+		assert.Empty(t, op.Parameters[0].Type)
+		assert.Nil(t, op.Responses.StatusCodeResponses[201].Schema)
+	}
+}
+
+func TestBuilders_EmptySwagger(t *testing.T) {
+	data, err := json.Marshal(NewSwagger("pets", "1.0"))
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{"swagger": "2.0", "info": {"title": "pets", "version": "1.0"}, "paths": {}}`, string(data))
+	}
+}
+
+func TestBuilders_Operation(t *testing.T) {
+	op := NewOperation("listPets").
+		AddParam(QueryParam("limit").Typed("integer", "int32")).
+		AddParam(QueryParam("tag")).
+		AddParam(QueryParam("limit").Typed("integer", "int64")).
+		AddParam(nil).
+		WithExternalDocs("the docs", "https://example.com/docs").
+		Deprecate()
+	if assert.Len(t, op.Parameters, 2) {
+		// the parameter with the same name and location is replaced
+		assert.Equal(t, "int64", op.Parameters[0].Format)
+	}
+	assert.True(t, op.Deprecated)
+	assert.Equal(t, "https://example.com/docs", op.ExternalDocs.URL)
+
+	op.RemoveParam("limit", "query").
+		RemoveParam("limit", "header").
+		WithExternalDocs("", "").
+		Undeprecate().
+		RespondsWith(200, ArrayProperty(RefProperty("#/definitions/Pet"))).
+		WithResponse(404, NewResponse().WithDescription("none")).
+		WithResponse(404, nil).
+		WithDefaultResponse(ResponseRef("#/responses/error"))
+	if assert.Len(t, op.Parameters, 1) {
+		assert.Equal(t, "tag", op.Parameters[0].Name)
+	}
+	assert.False(t, op.Deprecated)
+	assert.Nil(t, op.ExternalDocs)
+	assert.Len(t, op.Responses.StatusCodeResponses, 1)
+	assert.Equal(t, "OK", op.Responses.StatusCodeResponses[200].Description)
+	assert.Equal(t, "#/responses/error", op.Responses.Default.Ref.String())
+}
+
+func TestBuilders_Response(t *testing.T) {
+	resp := NewResponse().
+		WithDescription("the pet").
+		AddHeader("X-Rate-Limit", ResponseHeader().Typed("integer", "int32").WithDescription("calls left")).
+		AddHeader("X-Expires", ResponseHeader().Typed("string", "date-time")).
+		AddHeader("X-Expires", nil).
+		AddExample("application/json", map[string]interface{}{"name": "rex"})
+
+	data, err := json.Marshal(resp)
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{
+  "description": "the pet",
+  "headers": {"X-Rate-Limit": {"description": "calls left", "type": "integer", "format": "int32"}},
+  "examples": {"application/json": {"name": "rex"}}
+}`, string(data))
+	}
+}
+
+func TestBuilders_PathItem(t *testing.T) {
+	sw := NewSwagger("pets", "1.0").
+		AddOperation("GET", "/pets", NewOperation("listPets")).
+		AddOperation("post", "/pets", NewOperation("addPet"))
+	item := sw.Paths.Paths["/pets"]
+	// adding an operation keeps the other operations of the path
+	if assert.NotNil(t, item.Get) && assert.NotNil(t, item.Post) {
+		assert.Equal(t, "listPets", item.Get.ID)
+		assert.Equal(t, "addPet", item.Post.ID)
+	}
+
+	item.WithOperation("Get", nil).
+		WithOperation("PATCH", NewOperation("updatePet")).
+		AddParam(HeaderParam("X-Trace")).
+		AddParam(HeaderParam("X-Trace").Typed("string", "uuid"))
+	assert.Nil(t, item.Get)
+	assert.Equal(t, "updatePet", item.Patch.ID)
+	if assert.Len(t, item.Parameters, 1) {
+		assert.Equal(t, "uuid", item.Parameters[0].Format)
+	}
+}
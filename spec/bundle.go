@@ -0,0 +1,329 @@
+package spec
+
+import (
+	"encoding/json"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/aiyi/swagger-gin/jsonpointer"
+	"github.com/aiyi/swagger-gin/swag"
+)
+
+// BundleOptions tell how a spec is bundled
+type BundleOptions struct {
+	// LiftInline moves the inline object schemas of the definitions, the bodies
+	// and the responses to definitions of their own, named after where they are
+	LiftInline bool
+}
+
+// Bundled returns a self-contained copy of the spec document. Unlike Expanded the
+// references are kept: what a reference to another document points to is copied
+// to the definitions, or to the parameters and responses for the parameters and
+// responses of the operations, and the reference is rewritten to the local copy.
+// The names of the copies are taken from the references and made unique.
+func (d *Document) Bundled(opts BundleOptions) (*Document, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(d.raw, &raw); err != nil {
+		return nil, err
+	}
+	loader := d.spec.loader
+	if loader == nil {
+		loader = defaultDocLoader()
+	}
+	b := &bundler{loader: loader, root: raw, hoisted: make(map[string]string)}
+	if err := b.bundle(); err != nil {
+		return nil, err
+	}
+	if opts.LiftInline {
+		b.liftInline()
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	return d.Derive(data)
+}
+
+type bundler struct {
+	loader *docLoader
+	root   map[string]interface{}
+	// hoisted are the local references of the copies by the location they're copied from
+	hoisted map[string]string
+}
+
+// section returns a section of the spec, created when it's missing
+func (b *bundler) section(name string) map[string]interface{} {
+	section, ok := b.root[name].(map[string]interface{})
+	if !ok {
+		section = make(map[string]interface{})
+		b.root[name] = section
+	}
+	return section
+}
+
+func (b *bundler) bundle() error {
+	if paths, ok := b.root["paths"].(map[string]interface{}); ok {
+		for _, key := range sortedKeys(paths) {
+			item, ok := paths[key].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if err := b.walkOperation(item); err != nil {
+				return err
+			}
+			for _, method := range openAPI3Methods {
+				if op, ok := item[method].(map[string]interface{}); ok {
+					if err := b.walkOperation(op); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	for _, name := range []string{"definitions", "parameters", "responses"} {
+		section, ok := b.root[name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, key := range sortedKeys(section) {
+			if err := b.walk(section[key]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// walkOperation bundles the parameters and responses of an operation or a path item,
+// their references to other documents are copied to the parameters and responses
+func (b *bundler) walkOperation(op map[string]interface{}) error {
+	for key, value := range op {
+		if strings.HasPrefix(key, "x-") {
+			continue
+		}
+		switch key {
+		case "parameters":
+			params, _ := value.([]interface{})
+			for _, p := range params {
+				if err := b.walkIn(p, "parameters"); err != nil {
+					return err
+				}
+			}
+		case "responses":
+			responses, _ := value.(map[string]interface{})
+			for _, code := range sortedKeys(responses) {
+				if err := b.walkIn(responses[code], "responses"); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// walk bundles the references of a node to the definitions
+func (b *bundler) walk(node interface{}) error {
+	return b.walkIn(node, "definitions")
+}
+
+// walkIn bundles the references of a node, its own reference is copied to a section
+// and the references of its children to the definitions
+func (b *bundler) walkIn(node interface{}, section string) error {
+	switch value := node.(type) {
+	case map[string]interface{}:
+		if ref, ok := value["$ref"].(string); ok {
+			if strings.HasPrefix(ref, "#") {
+				return nil
+			}
+			local, err := b.hoist(ref, section)
+			if err != nil {
+				return err
+			}
+			value["$ref"] = local
+			return nil
+		}
+		for _, key := range sortedKeys(value) {
+			if strings.HasPrefix(key, "x-") || key == "example" || key == "examples" {
+				continue
+			}
+			if err := b.walk(value[key]); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, v := range value {
+			if err := b.walk(v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// hoist copies what a reference to another document points to into a section of the
+// spec, once by location, and returns the local reference to the copy
+func (b *bundler) hoist(ref, section string) (string, error) {
+	target, err := b.loader.base.Parse(ref)
+	if err != nil {
+		return "", err
+	}
+	key := section + " " + target.String()
+	if local, ok := b.hoisted[key]; ok {
+		return local, nil
+	}
+
+	entries := b.section(section)
+	name := uniqueName(entries, refName(target), docName(target))
+	local := "#/" + section + "/" + jsonpointer.Escape(name)
+	b.hoisted[key] = local
+	// the name is taken before the copy is bundled so the recursive references resolve to it
+	entries[name] = nil
+
+	value, docURL, err := b.loader.fetch(target)
+	if err != nil {
+		return "", err
+	}
+	value, _ = b.loader.rebase(value, docURL, docURL)
+	entries[name] = value
+	return local, b.walkIn(value, section)
+}
+
+// refName names the copy of what a reference points to after the last token of its
+// fragment, or after the document when it points to the whole document
+func refName(target *url.URL) string {
+	if tokens := strings.Split(target.Fragment, "/"); len(tokens) > 1 && tokens[len(tokens)-1] != "" {
+		return jsonpointer.Unescape(tokens[len(tokens)-1])
+	}
+	return docName(target)
+}
+
+// docName names a document after its file, without the extension
+func docName(target *url.URL) string {
+	base := path.Base(target.Path)
+	return swag.ToGoName(strings.TrimSuffix(base, path.Ext(base)))
+}
+
+// uniqueName returns a name no entry of a section has, the name is prefixed with
+// the document it comes from and then numbered on collisions
+func uniqueName(entries map[string]interface{}, name, doc string) string {
+	if _, taken := entries[name]; !taken {
+		return name
+	}
+	if doc != "" && doc != name {
+		name = doc + swag.ToGoName(name)
+	}
+	candidate := name
+	for i := 2; ; i++ {
+		if _, taken := entries[candidate]; !taken {
+			return candidate
+		}
+		candidate = name + strconv.Itoa(i)
+	}
+}
+
+// liftInline moves the inline object schemas to definitions, the properties become
+// definitions named after their parent and the property
+func (b *bundler) liftInline() {
+	definitions := b.section("definitions")
+	if paths, ok := b.root["paths"].(map[string]interface{}); ok {
+		for _, key := range sortedKeys(paths) {
+			item, _ := paths[key].(map[string]interface{})
+			for _, method := range openAPI3Methods {
+				op, ok := item[method].(map[string]interface{})
+				if !ok {
+					continue
+				}
+				id, _ := op["operationId"].(string)
+				if id == "" {
+					id = method + " " + key
+				}
+				id = swag.ToGoName(id)
+				params, _ := op["parameters"].([]interface{})
+				for _, p := range params {
+					if param, ok := p.(map[string]interface{}); ok && param["in"] == "body" {
+						param["schema"] = b.lift(definitions, param["schema"], id+"Body")
+					}
+				}
+				responses, _ := op["responses"].(map[string]interface{})
+				for _, code := range sortedKeys(responses) {
+					if resp, ok := responses[code].(map[string]interface{}); ok && resp["schema"] != nil {
+						resp["schema"] = b.lift(definitions, resp["schema"], id+swag.ToGoName(code)+"Response")
+					}
+				}
+			}
+		}
+	}
+
+	// the lifted definitions are lifted in turn, until no inline object is left
+	done := make(map[string]bool)
+	for {
+		var pending []string
+		for _, name := range sortedKeys(definitions) {
+			if !done[name] {
+				pending = append(pending, name)
+			}
+		}
+		if len(pending) == 0 {
+			return
+		}
+		for _, name := range pending {
+			done[name] = true
+			if schema, ok := definitions[name].(map[string]interface{}); ok {
+				b.liftProperties(definitions, schema, name)
+			}
+		}
+	}
+}
+
+// lift replaces an inline object schema, or the inline object items of an array,
+// by a reference to a new definition
+func (b *bundler) lift(definitions map[string]interface{}, node interface{}, name string) interface{} {
+	schema, ok := node.(map[string]interface{})
+	if !ok {
+		return node
+	}
+	if items, ok := schema["items"].(map[string]interface{}); ok && schema["type"] == "array" {
+		schema["items"] = b.lift(definitions, items, name+"Items")
+		return schema
+	}
+	if !isInlineObject(schema) {
+		return node
+	}
+	name = uniqueName(definitions, name, "")
+	definitions[name] = schema
+	return map[string]interface{}{"$ref": "#/definitions/" + jsonpointer.Escape(name)}
+}
+
+func (b *bundler) liftProperties(definitions map[string]interface{}, schema map[string]interface{}, parent string) {
+	if props, ok := schema["properties"].(map[string]interface{}); ok {
+		for _, name := range sortedKeys(props) {
+			props[name] = b.lift(definitions, props[name], parent+swag.ToGoName(name))
+		}
+	}
+	if allOf, ok := schema["allOf"].([]interface{}); ok {
+		for _, member := range allOf {
+			if m, ok := member.(map[string]interface{}); ok {
+				b.liftProperties(definitions, m, parent)
+			}
+		}
+	}
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		schema["items"] = b.lift(definitions, items, parent+"Items")
+	}
+}
+
+// isInlineObject tells whether a schema is an object with properties and no reference
+func isInlineObject(schema map[string]interface{}) bool {
+	if _, ok := schema["$ref"]; ok {
+		return false
+	}
+	props, _ := schema["properties"].(map[string]interface{})
+	if len(props) == 0 {
+		return false
+	}
+	tpe, ok := schema["type"]
+	return !ok || tpe == "object"
+}
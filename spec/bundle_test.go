@@ -0,0 +1,127 @@
+package spec
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBundled(t *testing.T) {
+	dir := tempSpecDir(t)
+	defer os.RemoveAll(dir)
+	writeSpecFiles(t, dir, map[string]string{
+		"swagger.yml": `swagger: "2.0"
+info: {title: pets, version: "1.0"}
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      parameters:
+        - $ref: common.yml#/parameters/limit
+      responses:
+        200:
+          description: ok
+          schema: {type: array, items: {$ref: pet.json}}
+        default:
+          $ref: common.yml#/responses/error
+    post:
+      operationId: addPet
+      parameters:
+        - name: body
+          in: body
+          schema:
+            type: object
+            properties:
+              name: {type: string}
+              owner:
+                type: object
+                properties:
+                  address:
+                    type: object
+                    properties:
+                      city: {type: string}
+      responses:
+        201: {description: added}
+definitions:
+  Tag: {type: integer}
+`,
+		"pet.json": `{"type": "object", "properties": {
+  "name": {"type": "string"},
+  "tag": {"$ref": "#/definitions/Tag"},
+  "owner": {"$ref": "owner.json"}
+}, "definitions": {"Tag": {"type": "string"}}}`,
+		"owner.json": `{"type": "object", "properties": {"pets": {"type": "array", "items": {"$ref": "pet.json"}}}}`,
+		"common.yml": `parameters:
+  limit: {name: limit, in: query, type: integer}
+responses:
+  error:
+    description: an error
+    schema: {$ref: "#/definitions/Error"}
+definitions:
+  Error:
+    type: object
+    properties:
+      message: {type: string}
+`,
+	})
+
+	doc, err := Load(filepath.Join(dir, "swagger.yml"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	bundled, err := doc.Bundled(BundleOptions{})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NotContains(t, string(bundled.Raw()), "file://")
+	sw := bundled.Spec()
+
+	list := sw.Paths.Paths["/pets"].Get
+	if assert.NotNil(t, list) {
+		assert.Equal(t, "#/parameters/limit", list.Parameters[0].Ref.String())
+		assert.Equal(t, "#/definitions/Pet", list.Responses.StatusCodeResponses[200].Schema.Items.Schema.Ref.String())
+		assert.Equal(t, "#/responses/error", list.Responses.Default.Ref.String())
+	}
+	assert.Equal(t, "limit", sw.Parameters["limit"].Name)
+	assert.Equal(t, "#/definitions/Error", sw.Responses["error"].Schema.Ref.String())
+	assert.Contains(t, sw.Definitions["Error"].Properties, "message")
+
+	// the recursive references are kept and the names don't collide
+	pet := sw.Definitions["Pet"]
+	tag, owner := pet.Properties["tag"], pet.Properties["owner"]
+	assert.Equal(t, "#/definitions/PetTag", tag.Ref.String())
+	assert.Equal(t, "string", sw.Definitions["PetTag"].Type[0])
+	assert.Equal(t, "integer", sw.Definitions["Tag"].Type[0])
+	assert.Equal(t, "#/definitions/Owner", owner.Ref.String())
+	pets := sw.Definitions["Owner"].Properties["pets"]
+	assert.Equal(t, "#/definitions/Pet", pets.Items.Schema.Ref.String())
+
+	// the bundled spec stands on its own
+	self, err := New(bundled.Raw(), "")
+	if assert.NoError(t, err) {
+		_, err = self.Expanded()
+		assert.NoError(t, err)
+	}
+
+	// the inline objects are only lifted when asked to
+	body := sw.Paths.Paths["/pets"].Post.Parameters[0]
+	assert.Contains(t, body.Schema.Properties, "owner")
+	lifted, err := doc.Bundled(BundleOptions{LiftInline: true})
+	if !assert.NoError(t, err) {
+		return
+	}
+	sw = lifted.Spec()
+	body = sw.Paths.Paths["/pets"].Post.Parameters[0]
+	assert.Equal(t, "#/definitions/AddPetBody", body.Schema.Ref.String())
+	ownerProp := sw.Definitions["AddPetBody"].Properties["owner"]
+	assert.Equal(t, "#/definitions/AddPetBodyOwner", ownerProp.Ref.String())
+	address := sw.Definitions["AddPetBodyOwner"].Properties["address"]
+	assert.Equal(t, "#/definitions/AddPetBodyOwnerAddress", address.Ref.String())
+	assert.Contains(t, sw.Definitions["AddPetBodyOwnerAddress"].Properties, "city")
+	for name := range sw.Definitions {
+		assert.False(t, strings.HasPrefix(name, "ListPets"), name)
+	}
+}
@@ -66,6 +66,7 @@ type schemaLoader struct {
 	cache       ResolutionCache
 	loadDoc     DocLoader
 	schemaRef   *Ref
+	docs        *docLoader
 }
 
 var idPtr, _ = jsonpointer.New("/id")
@@ -84,13 +85,19 @@ func defaultSchemaLoader(root interface{}, ref *Ref, cache ResolutionCache) (*sc
 
 	currentRef := nextRef(root, ref, ptr)
 
+	docs := defaultDocLoader()
+	if sw, ok := root.(*Swagger); ok && sw.loader != nil {
+		docs = sw.loader
+	}
+
 	return &schemaLoader{
 		root:        root,
 		loadingRef:  ref,
 		startingRef: ref,
 		cache:       cache,
-		loadDoc:     swag.JSONDoc,
+		loadDoc:     docs.loadDoc,
 		currentRef:  currentRef,
+		docs:        docs,
 	}, nil
 }
 
@@ -197,6 +204,14 @@ func (r *schemaLoader) resolveRef(currentRef, ref *Ref, node, target interface{}
 		return nil
 	}
 
+	if isFileRef(refURL) {
+		res, err := r.docs.resolveFile(refURL)
+		if err != nil {
+			return err
+		}
+		return swag.DynamicJSONToStruct(res, target)
+	}
+
 	if refURL.Scheme != "" && refURL.Host != "" {
 		// most definitely take the red pill
 		data, _, _, err := r.load(refURL)
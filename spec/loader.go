@@ -0,0 +1,300 @@
+package spec
+
+import (
+	"encoding/json"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/aiyi/swagger-gin/jsonpointer"
+	"github.com/aiyi/swagger-gin/swag"
+)
+
+// LoaderOptions tell where the documents referenced by a spec are loaded from
+type LoaderOptions struct {
+	// BaseDir is the directory the relative references of the spec are resolved
+	// against, it defaults to the directory of the spec
+	BaseDir string
+	// URLMappings map url prefixes to local files or directories, a reference
+	// to a url with one of the prefixes is loaded from the local copy instead.
+	// This allows specs referencing remote documents to be loaded offline.
+	URLMappings map[string]string
+}
+
+// LoadWithOptions loads a spec document, json or yaml, that can be split across
+// several files. The path items and the definitions, parameters and responses
+// that reference another document are replaced by what they reference, the
+// other references are made absolute so they resolve from anywhere.
+func LoadWithOptions(path string, opts LoaderOptions) (*Document, error) {
+	loader, err := newDocLoader(path, opts)
+	if err != nil {
+		return nil, err
+	}
+	data, err := loader.loadDoc(loader.root.String())
+	if err != nil {
+		return nil, err
+	}
+
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	inlined, err := loader.inline(raw)
+	if err != nil {
+		return nil, err
+	}
+	rebased, changed := loader.rebase(raw, loader.base, loader.root)
+	if inlined || changed {
+		// the document is only reencoded when it changed, to keep the order of the keys otherwise
+		if data, err = json.Marshal(rebased); err != nil {
+			return nil, err
+		}
+	}
+
+	doc, err := New(data, "")
+	if err != nil {
+		return nil, err
+	}
+	doc.spec.loader = loader
+	return doc, nil
+}
+
+// docLoader loads the documents referenced by a spec
+type docLoader struct {
+	// root is the location of the spec
+	root *url.URL
+	// base is what the relative references of the spec resolve against
+	base     *url.URL
+	mappings map[string]string
+	// aliases are the documents inlined in the spec, by location, with the
+	// local reference that replaces them
+	aliases map[string]string
+}
+
+func newDocLoader(path string, opts LoaderOptions) (*docLoader, error) {
+	root, err := location(path)
+	if err != nil {
+		return nil, err
+	}
+	base := root
+	if opts.BaseDir != "" {
+		if base, err = location(opts.BaseDir + string(filepath.Separator)); err != nil {
+			return nil, err
+		}
+	}
+	return &docLoader{root: root, base: base, mappings: opts.URLMappings, aliases: make(map[string]string)}, nil
+}
+
+// defaultDocLoader is used for documents that weren't loaded from a location,
+// their relative references are resolved against the current directory
+func defaultDocLoader() *docLoader {
+	base, err := location("." + string(filepath.Separator))
+	if err != nil {
+		base = &url.URL{Scheme: "file", Path: "/"}
+	}
+	return &docLoader{root: base, base: base, aliases: make(map[string]string)}
+}
+
+// location turns a url or a file path into an absolute url
+func location(path string) (*url.URL, error) {
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") || strings.HasPrefix(path, "file://") {
+		return url.Parse(path)
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasSuffix(path, string(filepath.Separator)) {
+		abs += string(filepath.Separator)
+	}
+	abs = filepath.ToSlash(abs)
+	if !strings.HasPrefix(abs, "/") {
+		abs = "/" + abs
+	}
+	return &url.URL{Scheme: "file", Path: abs}, nil
+}
+
+// localPath is the file or url a document is read from, after the url mappings
+func (l *docLoader) localPath(loc string) string {
+	var prefixes []string
+	for prefix := range l.mappings {
+		if strings.HasPrefix(loc, prefix) {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	if len(prefixes) > 0 {
+		// the longest prefix is the most specific mapping
+		sort.Slice(prefixes, func(i, j int) bool { return len(prefixes[i]) > len(prefixes[j]) })
+		return l.mappings[prefixes[0]] + strings.TrimPrefix(loc, prefixes[0])
+	}
+	if u, err := url.Parse(loc); err == nil && u.Scheme == "file" {
+		return filepath.FromSlash(u.Path)
+	}
+	return loc
+}
+
+func (l *docLoader) loadDoc(loc string) (json.RawMessage, error) {
+	return swag.JSONOrYAMLDoc(l.localPath(loc))
+}
+
+// inlinedSections are the sections of a spec whose entries are inlined when they reference
+// another document, the components are the sections of the OpenAPI 3.0 documents
+var inlinedSections = []string{
+	"definitions", "parameters", "responses", "paths",
+	"components/schemas", "components/parameters", "components/responses", "components/requestBodies",
+}
+
+// inline replaces the path items and the definitions, parameters and responses
+// that reference another document by the part of the document they reference
+func (l *docLoader) inline(raw interface{}) (bool, error) {
+	doc, ok := raw.(map[string]interface{})
+	if !ok {
+		return false, nil
+	}
+
+	type entry struct {
+		section map[string]interface{}
+		name    string
+		target  *url.URL
+	}
+	var entries []entry
+	for _, name := range inlinedSections {
+		var section map[string]interface{}
+		for i, token := range strings.Split(name, "/") {
+			if i == 0 {
+				section, _ = doc[token].(map[string]interface{})
+			} else {
+				section, _ = section[token].(map[string]interface{})
+			}
+		}
+		if section == nil {
+			continue
+		}
+		for key, value := range section {
+			obj, ok := value.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			ref, ok := obj["$ref"].(string)
+			if !ok || strings.HasPrefix(ref, "#") {
+				continue
+			}
+			target, err := l.base.Parse(ref)
+			if err != nil {
+				return false, err
+			}
+			if name != "paths" {
+				l.aliases[target.String()] = "#/" + name + "/" + jsonpointer.Escape(key)
+			}
+			entries = append(entries, entry{section: section, name: key, target: target})
+		}
+	}
+
+	for _, e := range entries {
+		value, docURL, err := l.fetch(e.target)
+		if err != nil {
+			return false, err
+		}
+		e.section[e.name], _ = l.rebase(value, docURL, docURL)
+	}
+	return len(entries) > 0, nil
+}
+
+// fetch loads the document a reference points to and returns the value the
+// fragment of the reference points to, together with the location of the document
+func (l *docLoader) fetch(target *url.URL) (interface{}, *url.URL, error) {
+	docURL := *target
+	docURL.Fragment = ""
+	data, err := l.loadDoc(docURL.String())
+	if err != nil {
+		return nil, nil, err
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, nil, err
+	}
+	if target.Fragment != "" {
+		ptr, err := jsonpointer.New(target.Fragment)
+		if err != nil {
+			return nil, nil, err
+		}
+		if value, _, err = ptr.Get(value); err != nil {
+			return nil, nil, err
+		}
+	}
+	return value, &docURL, nil
+}
+
+// rebase returns a copy of a node taken from the document at doc with its references
+// made absolute: relative references resolve against base and fragments against doc.
+// References to the spec itself or to documents inlined in it become local references.
+// The vendor extensions and examples are free form, they are left untouched.
+func (l *docLoader) rebase(node interface{}, base, doc *url.URL) (interface{}, bool) {
+	switch value := node.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(value))
+		changed := false
+		for k, v := range value {
+			if strings.HasPrefix(k, "x-") || k == "example" || k == "examples" {
+				result[k] = v
+				continue
+			}
+			if ref, ok := v.(string); ok && k == "$ref" {
+				rebased := l.rebaseRef(ref, base, doc)
+				result[k] = rebased
+				changed = changed || rebased != ref
+				continue
+			}
+			nv, ch := l.rebase(v, base, doc)
+			result[k] = nv
+			changed = changed || ch
+		}
+		return result, changed
+	case []interface{}:
+		result := make([]interface{}, len(value))
+		changed := false
+		for i, v := range value {
+			nv, ch := l.rebase(v, base, doc)
+			result[i] = nv
+			changed = changed || ch
+		}
+		return result, changed
+	}
+	return node, false
+}
+
+func (l *docLoader) rebaseRef(ref string, base, doc *url.URL) string {
+	from := base
+	if strings.HasPrefix(ref, "#") {
+		from = doc
+	}
+	target, err := from.Parse(ref)
+	if err != nil {
+		// reported when the reference is resolved
+		return ref
+	}
+	if alias, ok := l.aliases[target.String()]; ok {
+		return alias
+	}
+	docURL := *target
+	docURL.Fragment = ""
+	if docURL.String() == l.root.String() {
+		return "#" + target.EscapedFragment()
+	}
+	return target.String()
+}
+
+// resolveFile resolves a reference to another document, which can be relative to the spec
+func (l *docLoader) resolveFile(refURL *url.URL) (interface{}, error) {
+	value, docURL, err := l.fetch(l.base.ResolveReference(refURL))
+	if err != nil {
+		return nil, err
+	}
+	value, _ = l.rebase(value, docURL, docURL)
+	return value, nil
+}
+
+func isFileRef(u *url.URL) bool {
+	return u.Scheme == "file" || (u.Scheme == "" && u.Host == "" && u.Path != "")
+}
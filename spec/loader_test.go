@@ -0,0 +1,124 @@
+package spec
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func tempSpecDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "spec-loader")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return dir
+}
+
+func writeSpecFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+func TestLoadWithOptions_MultiFile(t *testing.T) {
+	dir := tempSpecDir(t)
+	defer os.RemoveAll(dir)
+	writeSpecFiles(t, dir, map[string]string{
+		"api/swagger.yml": `swagger: "2.0"
+info: {title: pets, version: "1.0"}
+paths:
+  /pets:
+    $ref: paths/pets.yml
+definitions:
+  Pet:
+    $ref: definitions/pet.json
+`,
+		"api/paths/pets.yml": `get:
+  operationId: listPets
+  responses:
+    200:
+      description: ok
+      schema:
+        type: array
+        items:
+          $ref: ../definitions/pet.json
+`,
+		"api/definitions/pet.json": `{"type": "object", "properties": {
+  "name": {"type": "string"},
+  "tag": {"$ref": "#/definitions/Tag"},
+  "owner": {"$ref": "file://` + filepath.ToSlash(filepath.Join(dir, "owner.json")) + `"}
+}, "definitions": {"Tag": {"type": "string"}}}`,
+		"owner.json": `{"type": "object", "properties": {"name": {"type": "string"}}}`,
+	})
+
+	doc, err := Load(filepath.Join(dir, "api", "swagger.yml"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	sw := doc.Spec()
+
+	get := sw.Paths.Paths["/pets"].Get
+	if assert.NotNil(t, get) {
+		items := get.Responses.StatusCodeResponses[200].Schema.Items.Schema
+		assert.Equal(t, "#/definitions/Pet", items.Ref.String())
+	}
+
+	petSchema := sw.Definitions["Pet"]
+	assert.Equal(t, "string", petSchema.Properties["name"].Type[0])
+	tag := petSchema.Properties["tag"]
+	resolved, err := ResolveRef(sw, &tag.Ref)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "string", resolved.Type[0])
+	}
+	ownerRef := petSchema.Properties["owner"]
+	resolved, err = ResolveRef(sw, &ownerRef.Ref)
+	if assert.NoError(t, err) {
+		assert.Contains(t, resolved.Properties, "name")
+	}
+
+	expanded, err := doc.Expanded()
+	if assert.NoError(t, err) {
+		assert.Contains(t, expanded.Spec().Definitions["Pet"].Properties["owner"].Properties, "name")
+	}
+}
+
+func TestLoadWithOptions_BaseDirAndMappings(t *testing.T) {
+	dir := tempSpecDir(t)
+	defer os.RemoveAll(dir)
+	writeSpecFiles(t, dir, map[string]string{
+		"specs/swagger.json": `{"swagger": "2.0", "info": {"title": "pets", "version": "1.0"}, "paths": {},
+  "definitions": {
+    "Pet": {"$ref": "models/pet.json"},
+    "Error": {"$ref": "https://example.com/schemas/error.json"}
+  }}`,
+		"shared/models/pet.json": `{"type": "object", "properties": {"error": {"$ref": "https://example.com/schemas/error.json"}}}`,
+		"mirror/error.json":      `{"type": "object", "properties": {"code": {"$ref": "code.json"}}}`,
+		"mirror/code.json":       `{"type": "integer"}`,
+	})
+
+	doc, err := LoadWithOptions(filepath.Join(dir, "specs", "swagger.json"), LoaderOptions{
+		BaseDir:     filepath.Join(dir, "shared"),
+		URLMappings: map[string]string{"https://example.com/schemas/": filepath.Join(dir, "mirror") + string(filepath.Separator)},
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	sw := doc.Spec()
+	errorRef := sw.Definitions["Pet"].Properties["error"]
+	assert.Equal(t, "#/definitions/Error", errorRef.Ref.String())
+
+	code := sw.Definitions["Error"].Properties["code"]
+	assert.Equal(t, "https://example.com/schemas/code.json", code.Ref.String())
+	resolved, err := ResolveRef(sw, &code.Ref)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "integer", resolved.Type[0])
+	}
+
+	_, err = LoadWithOptions(filepath.Join(dir, "specs", "swagger.json"), LoaderOptions{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,680 @@
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/aiyi/swagger-gin/jsonpointer"
+)
+
+// the media types whose schema becomes formData parameters instead of a body parameter
+var formMediaTypes = []string{"application/x-www-form-urlencoded", "multipart/form-data"}
+
+// the methods of an OpenAPI 3.0 path item that exist in swagger 2.0, trace is left out
+var openAPI3Methods = []string{"get", "put", "post", "delete", "options", "head", "patch"}
+
+// the $refs of OpenAPI 3.0 components that have a swagger 2.0 counterpart,
+// references to the other components are replaced by what they reference
+var openAPI3Refs = map[string]string{
+	"#/components/schemas/":    "#/definitions/",
+	"#/components/parameters/": "#/parameters/",
+	"#/components/responses/":  "#/responses/",
+}
+
+// specVersion reads the version of a spec document, from the openapi
+// property of OpenAPI 3 documents and the swagger property otherwise
+func specVersion(data json.RawMessage) string {
+	var doc struct {
+		Swagger string `json:"swagger"`
+		OpenAPI string `json:"openapi"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return ""
+	}
+	if doc.OpenAPI != "" {
+		return doc.OpenAPI
+	}
+	return doc.Swagger
+}
+
+func isOpenAPI3(version string) bool {
+	return version == "3.0" || strings.HasPrefix(version, "3.0.")
+}
+
+// ConvertOpenAPI3 converts an OpenAPI 3.0.x document to a swagger 2.0 document.
+//
+// The components become definitions, parameters, responses and security definitions,
+// request bodies become body or formData parameters, the content of the responses
+// becomes their schema and the produces of the operation, the first server becomes
+// the host, base path and schemes. Nullable schemas are marked with x-nullable,
+// oneOf and anyOf are kept as they are since the schema model supports them.
+// What swagger 2.0 can't express, like cookie parameters and callbacks, is left out.
+func ConvertOpenAPI3(data json.RawMessage) (json.RawMessage, error) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	c := &openAPI3Converter{doc: doc, components: object(doc["components"])}
+	return json.Marshal(c.convert())
+}
+
+type openAPI3Converter struct {
+	doc        map[string]interface{}
+	components map[string]interface{}
+}
+
+func object(v interface{}) map[string]interface{} {
+	m, _ := v.(map[string]interface{})
+	return m
+}
+
+func (c *openAPI3Converter) convert() map[string]interface{} {
+	result := map[string]interface{}{"swagger": "2.0"}
+	for _, k := range []string{"info", "tags", "externalDocs", "security"} {
+		if v, ok := c.doc[k]; ok {
+			result[k] = v
+		}
+	}
+	copyExtensions(result, c.doc)
+	c.convertServers(result)
+
+	if schemas := object(c.components["schemas"]); schemas != nil {
+		definitions := make(map[string]interface{}, len(schemas))
+		for name, schema := range schemas {
+			definitions[name] = c.convertSchema(schema)
+		}
+		result["definitions"] = definitions
+	}
+	if params := object(c.components["parameters"]); params != nil {
+		parameters := make(map[string]interface{}, len(params))
+		for name, param := range params {
+			if p := c.convertParameter(param); p != nil {
+				parameters[name] = p
+			}
+		}
+		result["parameters"] = parameters
+	}
+	if resps := object(c.components["responses"]); resps != nil {
+		responses := make(map[string]interface{}, len(resps))
+		for name, resp := range resps {
+			responses[name], _ = c.convertResponse(resp)
+		}
+		result["responses"] = responses
+	}
+	if schemes := object(c.components["securitySchemes"]); schemes != nil {
+		definitions := make(map[string]interface{}, len(schemes))
+		for name, scheme := range schemes {
+			if s := convertSecurityScheme(object(scheme)); s != nil {
+				definitions[name] = s
+			}
+		}
+		result["securityDefinitions"] = definitions
+	}
+
+	paths := make(map[string]interface{})
+	for path, item := range object(c.doc["paths"]) {
+		if strings.HasPrefix(path, "x-") {
+			paths[path] = item
+			continue
+		}
+		paths[path] = c.convertPathItem(object(item))
+	}
+	result["paths"] = paths
+	return result
+}
+
+// convertServers sets the host, base path and schemes from the first server,
+// the schemes of the other servers with the same host and path are added
+func (c *openAPI3Converter) convertServers(result map[string]interface{}) {
+	servers, _ := c.doc["servers"].([]interface{})
+	var host, basePath string
+	var schemes []interface{}
+	for i, s := range servers {
+		server := object(s)
+		raw, _ := server["url"].(string)
+		for name, v := range object(server["variables"]) {
+			if def, ok := object(v)["default"].(string); ok {
+				raw = strings.Replace(raw, "{"+name+"}", def, -1)
+			}
+		}
+		u, err := url.Parse(raw)
+		if err != nil {
+			continue
+		}
+		if i == 0 {
+			host, basePath = u.Host, strings.TrimSuffix(u.Path, "/")
+		} else if u.Host != host || strings.TrimSuffix(u.Path, "/") != basePath {
+			continue
+		}
+		if u.Scheme != "" {
+			schemes = append(schemes, u.Scheme)
+		}
+	}
+	if host != "" {
+		result["host"] = host
+	}
+	if basePath != "" {
+		result["basePath"] = basePath
+	}
+	if len(schemes) > 0 {
+		result["schemes"] = schemes
+	}
+}
+
+// resolve follows a reference to a component that has no swagger 2.0 counterpart,
+// like request bodies, headers and examples. Other values are returned as they are.
+func (c *openAPI3Converter) resolve(v interface{}) map[string]interface{} {
+	m := object(v)
+	for i := 0; i < maxRefDepth; i++ {
+		ref, ok := m["$ref"].(string)
+		if !ok || !strings.HasPrefix(ref, "#/components/") {
+			return m
+		}
+		if _, mapped := openAPI3RefPrefix(ref); mapped {
+			return m
+		}
+		parts := strings.SplitN(strings.TrimPrefix(ref, "#/components/"), "/", 2)
+		if len(parts) != 2 {
+			return m
+		}
+		target := object(object(c.components[parts[0]])[jsonpointer.Unescape(parts[1])])
+		if target == nil {
+			return m
+		}
+		m = target
+	}
+	return m
+}
+
+// lookup returns the component a mapped reference points to, so the type of a
+// schema can be read from it
+func (c *openAPI3Converter) lookup(v interface{}) map[string]interface{} {
+	m := object(v)
+	for i := 0; i < maxRefDepth; i++ {
+		ref, ok := m["$ref"].(string)
+		if !ok || !strings.HasPrefix(ref, "#/components/schemas/") {
+			return m
+		}
+		target := object(object(c.components["schemas"])[jsonpointer.Unescape(strings.TrimPrefix(ref, "#/components/schemas/"))])
+		if target == nil {
+			return m
+		}
+		m = target
+	}
+	return m
+}
+
+const maxRefDepth = 32
+
+func openAPI3RefPrefix(ref string) (string, bool) {
+	for from, to := range openAPI3Refs {
+		if strings.HasPrefix(ref, from) {
+			return to + strings.TrimPrefix(ref, from), true
+		}
+	}
+	return ref, false
+}
+
+// convertSchema rewrites the references of a schema and turns the OpenAPI 3.0
+// keywords into their swagger 2.0 counterparts or vendor extensions
+func (c *openAPI3Converter) convertSchema(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(value))
+		for k, item := range value {
+			switch {
+			case strings.HasPrefix(k, "x-") || k == "example" || k == "enum" || k == "default" || k == "required":
+				result[k] = item
+			case k == "$ref":
+				ref, _ := item.(string)
+				result[k], _ = openAPI3RefPrefix(ref)
+			case k == "nullable":
+				if b, _ := item.(bool); b {
+					result["x-nullable"] = true
+				}
+			case k == "readOnly":
+				result[k] = item
+			case k == "writeOnly" || k == "deprecated":
+				result["x-"+k] = item
+			case k == "discriminator":
+				if d := object(item); d != nil {
+					result[k] = d["propertyName"]
+					if mapping := object(d["mapping"]); mapping != nil {
+						converted := make(map[string]interface{}, len(mapping))
+						for value, target := range mapping {
+							ref, _ := target.(string)
+							converted[value], _ = openAPI3RefPrefix(ref)
+						}
+						result["x-discriminator-mapping"] = converted
+					}
+				}
+			case k == "properties" || k == "patternProperties":
+				props := make(map[string]interface{}, len(object(item)))
+				for name, prop := range object(item) {
+					props[name] = c.convertSchema(prop)
+				}
+				result[k] = props
+			default:
+				result[k] = c.convertSchema(item)
+			}
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(value))
+		for i, item := range value {
+			result[i] = c.convertSchema(item)
+		}
+		return result
+	}
+	return v
+}
+
+// convertParameter turns the schema of a parameter into the simple schema of a
+// swagger 2.0 parameter, nil is returned for the parameters 2.0 can't express
+func (c *openAPI3Converter) convertParameter(v interface{}) map[string]interface{} {
+	param := object(v)
+	if ref, ok := param["$ref"].(string); ok {
+		mapped, _ := openAPI3RefPrefix(ref)
+		return map[string]interface{}{"$ref": mapped}
+	}
+	in, _ := param["in"].(string)
+	if in == "cookie" {
+		return nil
+	}
+	result := make(map[string]interface{})
+	for _, k := range []string{"name", "in", "description", "required", "allowEmptyValue"} {
+		if v, ok := param[k]; ok {
+			result[k] = v
+		}
+	}
+	copyExtensions(result, param)
+
+	schema := param["schema"]
+	if schema == nil {
+		// a parameter with content is serialized as a single string
+		for _, media := range object(param["content"]) {
+			schema = object(media)["schema"]
+			break
+		}
+	}
+	simple := c.simpleSchema(schema)
+	for k, v := range simple {
+		result[k] = v
+	}
+	if simple["type"] == "array" {
+		result["collectionFormat"] = collectionFormat(param, in)
+	}
+	return result
+}
+
+// the keywords shared by schemas and the simple schemas of parameters, items and headers
+var simpleSchemaKeys = []string{
+	"format", "default", "enum", "maximum", "exclusiveMaximum", "minimum", "exclusiveMinimum",
+	"maxLength", "minLength", "pattern", "maxItems", "minItems", "uniqueItems", "multipleOf",
+}
+
+// simpleSchema reads a simple schema from a schema, a schema that isn't
+// a primitive or an array of primitives is serialized as a string
+func (c *openAPI3Converter) simpleSchema(v interface{}) map[string]interface{} {
+	schema := c.lookup(v)
+	result := make(map[string]interface{})
+	tpe, _ := schema["type"].(string)
+	switch tpe {
+	case "", "object":
+		result["type"] = "string"
+		return result
+	}
+	result["type"] = tpe
+	for _, k := range simpleSchemaKeys {
+		if v, ok := schema[k]; ok {
+			result[k] = v
+		}
+	}
+	if tpe == "array" {
+		result["items"] = c.simpleSchema(schema["items"])
+	}
+	return result
+}
+
+// collectionFormat maps the style and explode of an array parameter to a collection format
+func collectionFormat(param map[string]interface{}, in string) string {
+	style, _ := param["style"].(string)
+	if style == "" {
+		style = "simple"
+		if in == "query" {
+			style = "form"
+		}
+	}
+	explode, ok := param["explode"].(bool)
+	if !ok {
+		explode = style == "form"
+	}
+	switch style {
+	case "form":
+		if explode {
+			return "multi"
+		}
+		return "csv"
+	case "spaceDelimited":
+		return "ssv"
+	case "pipeDelimited":
+		return "pipes"
+	}
+	return "csv"
+}
+
+func (c *openAPI3Converter) convertPathItem(item map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{})
+	copyExtensions(result, item)
+	if ref, ok := item["$ref"]; ok {
+		result["$ref"] = ref
+	}
+	if params := c.convertParameters(item["parameters"]); len(params) > 0 {
+		result["parameters"] = params
+	}
+	for _, method := range openAPI3Methods {
+		if op := object(item[method]); op != nil {
+			result[method] = c.convertOperation(op)
+		}
+	}
+	return result
+}
+
+func (c *openAPI3Converter) convertParameters(v interface{}) []interface{} {
+	params, _ := v.([]interface{})
+	var result []interface{}
+	for _, p := range params {
+		if param := c.convertParameter(p); param != nil {
+			result = append(result, param)
+		}
+	}
+	return result
+}
+
+func (c *openAPI3Converter) convertOperation(op map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{})
+	for _, k := range []string{"tags", "summary", "description", "externalDocs", "operationId", "deprecated", "security"} {
+		if v, ok := op[k]; ok {
+			result[k] = v
+		}
+	}
+	copyExtensions(result, op)
+
+	params := c.convertParameters(op["parameters"])
+	if body := c.resolve(op["requestBody"]); body != nil {
+		consumes, bodyParams := c.convertRequestBody(body)
+		params = append(params, bodyParams...)
+		if len(consumes) > 0 {
+			result["consumes"] = consumes
+		}
+	}
+	if len(params) > 0 {
+		result["parameters"] = params
+	}
+
+	responses := make(map[string]interface{})
+	produces := make(map[string]bool)
+	for code, resp := range object(op["responses"]) {
+		if strings.HasPrefix(code, "x-") {
+			responses[code] = resp
+			continue
+		}
+		converted, mediaTypes := c.convertResponse(resp)
+		responses[code] = converted
+		for _, mt := range mediaTypes {
+			produces[mt] = true
+		}
+	}
+	result["responses"] = responses
+	if len(produces) > 0 {
+		result["produces"] = sortedKeys(produces)
+	}
+	return result
+}
+
+// convertRequestBody turns a request body into a body parameter, or into formData
+// parameters for form content, and returns the media types it consumes
+func (c *openAPI3Converter) convertRequestBody(body map[string]interface{}) ([]string, []interface{}) {
+	content := object(body["content"])
+	mediaTypes := make([]string, 0, len(content))
+	for mt := range content {
+		mediaTypes = append(mediaTypes, mt)
+	}
+	sort.Strings(mediaTypes)
+
+	var bodyType, formType string
+	for _, mt := range mediaTypes {
+		if isFormMediaType(mt) {
+			if formType == "" {
+				formType = mt
+			}
+		} else if bodyType == "" || strings.Contains(mt, "json") && !strings.Contains(bodyType, "json") {
+			bodyType = mt
+		}
+	}
+
+	required, _ := body["required"].(bool)
+	if bodyType != "" {
+		// swagger 2.0 can't have a body and form parameters, the body wins
+		var consumes []string
+		for _, mt := range mediaTypes {
+			if !isFormMediaType(mt) {
+				consumes = append(consumes, mt)
+			}
+		}
+		name, _ := body["x-codegen-request-body-name"].(string)
+		if name == "" {
+			name = "body"
+		}
+		param := map[string]interface{}{
+			"name":     name,
+			"in":       "body",
+			"required": required,
+			"schema":   c.convertSchema(object(content[bodyType])["schema"]),
+		}
+		if d, ok := body["description"]; ok {
+			param["description"] = d
+		}
+		return consumes, []interface{}{param}
+	}
+	if formType == "" {
+		return nil, nil
+	}
+
+	var consumes []string
+	for _, mt := range mediaTypes {
+		if isFormMediaType(mt) {
+			consumes = append(consumes, mt)
+		}
+	}
+	schema := c.lookup(object(content[formType])["schema"])
+	requiredProps := make(map[string]bool)
+	if names, ok := schema["required"].([]interface{}); ok {
+		for _, n := range names {
+			if s, ok := n.(string); ok {
+				requiredProps[s] = true
+			}
+		}
+	}
+	props := object(schema["properties"])
+	var params []interface{}
+	for _, name := range sortedKeys(props) {
+		prop := c.lookup(props[name])
+		param := map[string]interface{}{"name": name, "in": "formData", "required": requiredProps[name]}
+		if d, ok := prop["description"]; ok {
+			param["description"] = d
+		}
+		if prop["type"] == "string" && (prop["format"] == "binary" || prop["format"] == "base64") {
+			param["type"] = "file"
+		} else {
+			for k, v := range c.simpleSchema(prop) {
+				param[k] = v
+			}
+			if param["type"] == "array" {
+				param["collectionFormat"] = "multi"
+			}
+		}
+		params = append(params, param)
+	}
+	return consumes, params
+}
+
+func isFormMediaType(mt string) bool {
+	for _, form := range formMediaTypes {
+		if strings.HasPrefix(mt, form) {
+			return true
+		}
+	}
+	return false
+}
+
+// convertResponse picks the schema of a response from its json content, or from
+// the first content otherwise, and returns the media types of the content
+func (c *openAPI3Converter) convertResponse(v interface{}) (map[string]interface{}, []string) {
+	resp := object(v)
+	if ref, ok := resp["$ref"].(string); ok {
+		if mapped, ok := openAPI3RefPrefix(ref); ok {
+			// the media types of a shared response are still produced by the operation
+			return map[string]interface{}{"$ref": mapped}, c.contentTypes(c.responseComponent(ref))
+		}
+		resp = c.resolve(resp)
+	}
+
+	result := map[string]interface{}{"description": resp["description"]}
+	if result["description"] == nil {
+		result["description"] = ""
+	}
+	copyExtensions(result, resp)
+
+	content := object(resp["content"])
+	mediaTypes := c.contentTypes(resp)
+	var schemaType string
+	for _, mt := range mediaTypes {
+		if object(content[mt])["schema"] == nil {
+			continue
+		}
+		if schemaType == "" || strings.Contains(mt, "json") && !strings.Contains(schemaType, "json") {
+			schemaType = mt
+		}
+	}
+	if schemaType != "" {
+		result["schema"] = c.convertSchema(object(content[schemaType])["schema"])
+	}
+
+	examples := make(map[string]interface{})
+	for _, mt := range mediaTypes {
+		if ex, ok := object(content[mt])["example"]; ok {
+			examples[mt] = ex
+		}
+	}
+	if len(examples) > 0 {
+		result["examples"] = examples
+	}
+
+	if headers := object(resp["headers"]); headers != nil {
+		converted := make(map[string]interface{}, len(headers))
+		for name, h := range headers {
+			header := c.resolve(h)
+			simple := c.simpleSchema(header["schema"])
+			if d, ok := header["description"]; ok {
+				simple["description"] = d
+			}
+			converted[name] = simple
+		}
+		result["headers"] = converted
+	}
+	return result, mediaTypes
+}
+
+func (c *openAPI3Converter) responseComponent(ref string) map[string]interface{} {
+	return c.resolve(object(object(c.components["responses"])[jsonpointer.Unescape(strings.TrimPrefix(ref, "#/components/responses/"))]))
+}
+
+func (c *openAPI3Converter) contentTypes(resp map[string]interface{}) []string {
+	return sortedKeys(object(resp["content"]))
+}
+
+// convertSecurityScheme maps a security scheme to a security definition, the
+// bearer scheme becomes an api key in the Authorization header
+func convertSecurityScheme(scheme map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{})
+	if d, ok := scheme["description"]; ok {
+		result["description"] = d
+	}
+	copyExtensions(result, scheme)
+	switch scheme["type"] {
+	case "apiKey":
+		if scheme["in"] == "cookie" {
+			return nil
+		}
+		result["type"] = "apiKey"
+		result["name"] = scheme["name"]
+		result["in"] = scheme["in"]
+	case "http":
+		switch strings.ToLower(fmt.Sprint(scheme["scheme"])) {
+		case "basic":
+			result["type"] = "basic"
+		case "bearer":
+			result["type"] = "apiKey"
+			result["name"] = "Authorization"
+			result["in"] = "header"
+		default:
+			return nil
+		}
+	case "oauth2":
+		flows := object(scheme["flows"])
+		for _, flow := range []struct{ from, to string }{
+			{"authorizationCode", "accessCode"}, {"implicit", "implicit"},
+			{"password", "password"}, {"clientCredentials", "application"},
+		} {
+			f := object(flows[flow.from])
+			if f == nil {
+				continue
+			}
+			result["type"] = "oauth2"
+			result["flow"] = flow.to
+			for _, k := range []string{"authorizationUrl", "tokenUrl"} {
+				if v, ok := f[k]; ok {
+					result[k] = v
+				}
+			}
+			scopes := f["scopes"]
+			if scopes == nil {
+				scopes = map[string]interface{}{}
+			}
+			result["scopes"] = scopes
+			return result
+		}
+		return nil
+	default:
+		return nil
+	}
+	return result
+}
+
+func copyExtensions(to, from map[string]interface{}) {
+	for k, v := range from {
+		if strings.HasPrefix(k, "x-") {
+			to[k] = v
+		}
+	}
+}
+
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	switch value := m.(type) {
+	case map[string]interface{}:
+		for k := range value {
+			keys = append(keys, k)
+		}
+	case map[string]bool:
+		for k := range value {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,174 @@
+package spec
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aiyi/swagger-gin/swag"
+	"github.com/stretchr/testify/assert"
+)
+
+const openAPI3Pets = `openapi: 3.0.3
+info: {title: pets, version: "1.0"}
+servers:
+  - url: https://{env}.example.com/api/
+    variables:
+      env: {default: pets}
+paths:
+  /pets:
+    post:
+      operationId: addPet
+      requestBody:
+        $ref: '#/components/requestBodies/Pet'
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Pet'}
+        default:
+          $ref: '#/components/responses/Error'
+  /pets/{petId}:
+    parameters:
+      - {name: petId, in: path, required: true, schema: {type: integer, format: int64}}
+    get:
+      operationId: getPetById
+      parameters:
+        - {name: session, in: cookie, schema: {type: string}}
+        - {name: fields, in: query, explode: false, schema: {type: array, items: {type: string}}}
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Pet'}
+            application/xml:
+              schema: {$ref: '#/components/schemas/Pet'}
+    post:
+      operationId: uploadPhoto
+      requestBody:
+        content:
+          multipart/form-data:
+            schema:
+              type: object
+              required: [photo]
+              properties:
+                photo: {type: string, format: binary}
+                caption: {type: string}
+      responses:
+        "200": {description: ok}
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string}
+        tag: {type: string, nullable: true}
+        owner: {$ref: '#/components/schemas/Person'}
+    Person:
+      type: object
+      properties:
+        name: {type: string}
+  requestBodies:
+    Pet:
+      required: true
+      content:
+        application/json:
+          schema: {$ref: '#/components/schemas/Pet'}
+  responses:
+    Error:
+      description: error
+      content:
+        application/json:
+          schema: {type: object}
+  securitySchemes:
+    token: {type: http, scheme: bearer}
+    oauth:
+      type: oauth2
+      flows:
+        implicit:
+          authorizationUrl: https://example.com/auth
+          scopes: {read: read pets}
+`
+
+func TestConvertOpenAPI3(t *testing.T) {
+	data, err := swag.YAMLToJSON([]byte(openAPI3Pets))
+	if !assert.NoError(t, err) {
+		return
+	}
+	doc, err := New(data, "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	sw := doc.Spec()
+
+	assert.Equal(t, "2.0", sw.Swagger)
+	assert.Equal(t, "pets.example.com", sw.Host)
+	assert.Equal(t, "/api", sw.BasePath)
+	assert.Equal(t, []string{"https"}, sw.Schemes)
+
+	pet := sw.Definitions["Pet"]
+	owner := pet.Properties["owner"]
+	assert.Equal(t, "#/definitions/Person", owner.Ref.String())
+	assert.Equal(t, true, pet.Properties["tag"].Extensions["x-nullable"])
+
+	add := sw.Paths.Paths["/pets"].Post
+	if assert.NotNil(t, add) && assert.Len(t, add.Parameters, 1) {
+		body := add.Parameters[0]
+		assert.Equal(t, "body", body.In)
+		assert.Equal(t, "body", body.Name)
+		assert.True(t, body.Required)
+		assert.Equal(t, "#/definitions/Pet", body.Schema.Ref.String())
+		assert.Equal(t, []string{"application/json"}, add.Consumes)
+		assert.Equal(t, []string{"application/json"}, add.Produces)
+		assert.Equal(t, "#/definitions/Pet", add.Responses.StatusCodeResponses[200].Schema.Ref.String())
+		assert.Equal(t, "#/responses/Error", add.Responses.Default.Ref.String())
+	}
+	assert.Contains(t, sw.Responses, "Error")
+
+	item := sw.Paths.Paths["/pets/{petId}"]
+	if assert.Len(t, item.Parameters, 1) {
+		assert.Equal(t, "integer", item.Parameters[0].Type)
+		assert.Equal(t, "int64", item.Parameters[0].Format)
+	}
+	if assert.NotNil(t, item.Get) && assert.Len(t, item.Get.Parameters, 1) {
+		fields := item.Get.Parameters[0]
+		assert.Equal(t, "fields", fields.Name)
+		assert.Equal(t, "array", fields.Type)
+		assert.Equal(t, "csv", fields.CollectionFormat)
+		assert.Equal(t, []string{"application/json", "application/xml"}, item.Get.Produces)
+	}
+	if assert.NotNil(t, item.Post) && assert.Len(t, item.Post.Parameters, 2) {
+		assert.Equal(t, []string{"multipart/form-data"}, item.Post.Consumes)
+		for _, param := range item.Post.Parameters {
+			assert.Equal(t, "formData", param.In)
+			if param.Name == "photo" {
+				assert.Equal(t, "file", param.Type)
+				assert.True(t, param.Required)
+			}
+		}
+	}
+
+	if assert.Contains(t, sw.SecurityDefinitions, "token") {
+		token := sw.SecurityDefinitions["token"]
+		assert.Equal(t, "apiKey", token.Type)
+		assert.Equal(t, "header", token.In)
+		assert.Equal(t, "Authorization", token.Name)
+	}
+	if assert.Contains(t, sw.SecurityDefinitions, "oauth") {
+		oauth := sw.SecurityDefinitions["oauth"]
+		assert.Equal(t, "implicit", oauth.Flow)
+		assert.Equal(t, "https://example.com/auth", oauth.AuthorizationURL)
+	}
+}
+
+func TestNew_Versions(t *testing.T) {
+	_, err := New(json.RawMessage(`{"openapi": "3.1.0", "info": {"title": "pets", "version": "1.0"}, "paths": {}}`), "")
+	assert.Error(t, err)
+
+	doc, err := New(json.RawMessage(`{"openapi": "3.0.0", "info": {"title": "pets", "version": "1.0"}, "paths": {}}`), "")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "2.0", doc.Version())
+	}
+}
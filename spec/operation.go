@@ -2,6 +2,7 @@ package spec
 
 import (
 	"encoding/json"
+	"net/http"
 
 	"github.com/aiyi/swagger-gin/jsonpointer"
 	"github.com/aiyi/swagger-gin/swag"
@@ -30,6 +31,140 @@ type Operation struct {
 	OperationProps
 }
 
+// NewOperation creates a new operation instance with the given operationId
+func NewOperation(id string) *Operation {
+	op := new(Operation)
+	op.ID = id
+	return op
+}
+
+// WithID sets the operationId for this operation
+func (o *Operation) WithID(id string) *Operation {
+	o.ID = id
+	return o
+}
+
+// WithDescription sets the description on this operation, allows for chaining
+func (o *Operation) WithDescription(description string) *Operation {
+	o.Description = description
+	return o
+}
+
+// WithSummary sets the summary on this operation, allows for chaining
+func (o *Operation) WithSummary(summary string) *Operation {
+	o.Summary = summary
+	return o
+}
+
+// WithExternalDocs sets the external docs on this operation, the docs are removed when
+// both the description and the url are empty
+func (o *Operation) WithExternalDocs(description, url string) *Operation {
+	if description == "" && url == "" {
+		o.ExternalDocs = nil
+		return o
+	}
+	o.ExternalDocs = &ExternalDocumentation{Description: description, URL: url}
+	return o
+}
+
+// WithConsumes adds media types for incoming body values
+func (o *Operation) WithConsumes(mediaTypes ...string) *Operation {
+	o.Consumes = append(o.Consumes, mediaTypes...)
+	return o
+}
+
+// WithProduces adds media types for outgoing body values
+func (o *Operation) WithProduces(mediaTypes ...string) *Operation {
+	o.Produces = append(o.Produces, mediaTypes...)
+	return o
+}
+
+// WithTags adds tags for this operation
+func (o *Operation) WithTags(tags ...string) *Operation {
+	o.Tags = append(o.Tags, tags...)
+	return o
+}
+
+// Deprecate marks the operation as deprecated
+func (o *Operation) Deprecate() *Operation {
+	o.Deprecated = true
+	return o
+}
+
+// Undeprecate marks the operation as not deprecated
+func (o *Operation) Undeprecate() *Operation {
+	o.Deprecated = false
+	return o
+}
+
+// SecuredWith adds a security requirement of one scheme to this operation, the scopes
+// are the ones of an oauth2 scheme
+func (o *Operation) SecuredWith(name string, scopes ...string) *Operation {
+	if scopes == nil {
+		scopes = []string{}
+	}
+	o.Security = append(o.Security, map[string][]string{name: scopes})
+	return o
+}
+
+// AddParam adds a parameter to this operation, it replaces the parameter with the same
+// name and location. A nil parameter is ignored.
+func (o *Operation) AddParam(param *Parameter) *Operation {
+	if param == nil {
+		return o
+	}
+	for i, p := range o.Parameters {
+		if p.Name == param.Name && p.In == param.In {
+			o.Parameters[i] = *param
+			return o
+		}
+	}
+	o.Parameters = append(o.Parameters, *param)
+	return o
+}
+
+// RemoveParam removes the parameter with the name and location from this operation
+func (o *Operation) RemoveParam(name, in string) *Operation {
+	for i, p := range o.Parameters {
+		if p.Name == name && p.In == in {
+			o.Parameters = append(o.Parameters[:i], o.Parameters[i+1:]...)
+			return o
+		}
+	}
+	return o
+}
+
+// WithDefaultResponse sets the response for the status codes without a response of their own
+func (o *Operation) WithDefaultResponse(response *Response) *Operation {
+	if o.Responses == nil {
+		o.Responses = new(Responses)
+	}
+	o.Responses.Default = response
+	return o
+}
+
+// WithResponse adds a response for a status code, a nil response removes it
+func (o *Operation) WithResponse(code int, response *Response) *Operation {
+	if o.Responses == nil {
+		o.Responses = new(Responses)
+	}
+	if response == nil {
+		delete(o.Responses.StatusCodeResponses, code)
+		return o
+	}
+	if o.Responses.StatusCodeResponses == nil {
+		o.Responses.StatusCodeResponses = make(map[int]Response)
+	}
+	o.Responses.StatusCodeResponses[code] = *response
+	return o
+}
+
+// RespondsWith adds a response for a status code with a body of the schema, or without
+// a body when the schema is nil. The description of the response is the status text of the code.
+func (o *Operation) RespondsWith(code int, schema *Schema) *Operation {
+	return o.WithResponse(code, NewResponse().WithDescription(http.StatusText(code)).WithSchema(schema))
+}
+
 // SuccessResponse gets a success response model
 func (o *Operation) SuccessResponse() (*Response, int, bool) {
 	if o.Responses == nil {
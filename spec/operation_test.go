@@ -13,7 +13,7 @@ var operation = Operation{
 			"x-framework": "go-swagger",
 		},
 	},
-	operationProps: operationProps{
+	OperationProps: OperationProps{
 		Description: "operation description",
 		Consumes:    []string{"application/json", "application/x-yaml"},
 		Produces:    []string{"application/json", "application/x-yaml"},
@@ -31,7 +31,7 @@ var operation = Operation{
 			Parameter{refable: refable{Ref: MustCreateRef("Cat")}},
 		},
 		Responses: &Responses{
-			responsesProps: responsesProps{
+			ResponsesProps: ResponsesProps{
 				Default: &Response{
 					responseProps: responseProps{
 						Description: "void response",
@@ -7,6 +7,13 @@ import (
 	"github.com/aiyi/swagger-gin/swag"
 )
 
+// ParamRef creates a parameter that's a json reference
+func ParamRef(url string) *Parameter {
+	p := new(Parameter)
+	p.Ref = MustCreateRef(url)
+	return p
+}
+
 // QueryParam creates a query parameter
 func QueryParam(name string) *Parameter {
 	return &Parameter{ParamProps: ParamProps{Name: name, In: "query"}}
@@ -22,9 +29,9 @@ func PathParam(name string) *Parameter {
 	return &Parameter{ParamProps: ParamProps{Name: name, In: "path", Required: true}}
 }
 
-// BodyParam creates a body parameter
+// BodyParam creates a body parameter, its value is described by the schema instead of a type
 func BodyParam(name string, schema *Schema) *Parameter {
-	return &Parameter{ParamProps: ParamProps{Name: name, In: "body", Schema: schema}, SimpleSchema: SimpleSchema{Type: "object"}}
+	return &Parameter{ParamProps: ParamProps{Name: name, In: "body", Schema: schema}}
 }
 
 // FormDataParam creates a body parameter
@@ -35,11 +35,11 @@ var parameter = Parameter{
 		},
 		Default: "8",
 	},
-	paramProps: paramProps{
+	ParamProps: ParamProps{
 		Name:        "param-name",
 		In:          "header",
 		Required:    true,
-		Schema:      &Schema{schemaProps: schemaProps{Type: []string{"string"}}},
+		Schema:      &Schema{SchemaProps: SchemaProps{Type: []string{"string"}}},
 		Description: "the description of this parameter",
 	},
 }
@@ -168,28 +168,28 @@ func TestParameterSerialization(t *testing.T) {
 		})
 
 		Convey("a body parameter", func() {
-			schema := &Schema{schemaProps: schemaProps{
+			schema := &Schema{SchemaProps: SchemaProps{
 				Properties: map[string]Schema{
-					"name": Schema{schemaProps: schemaProps{
+					"name": Schema{SchemaProps: SchemaProps{
 						Type: []string{"string"},
 					}},
 				},
 			}}
 			param := BodyParam("", schema)
-			So(param, ShouldSerializeJSON, `{"type":"object","in":"body","schema":{"properties":{"name":{"type":"string"}}}}`)
+			So(param, ShouldSerializeJSON, `{"in":"body","schema":{"properties":{"name":{"type":"string"}}}}`)
 		})
 
 		Convey("a ref body parameter", func() {
 			schema := &Schema{
-				schemaProps: schemaProps{Ref: MustCreateRef("Cat")},
+				SchemaProps: SchemaProps{Ref: MustCreateRef("Cat")},
 			}
 			param := BodyParam("", schema)
-			So(param, ShouldSerializeJSON, `{"type":"object","in":"body","schema":{"$ref":"Cat"}}`)
+			So(param, ShouldSerializeJSON, `{"in":"body","schema":{"$ref":"Cat"}}`)
 		})
 
 		Convey("serialize an array body parameter", func() {
 			param := BodyParam("", ArrayProperty(RefProperty("Cat")))
-			So(param, ShouldSerializeJSON, `{"type":"object","in":"body","schema":{"type":"array","items":{"$ref":"Cat"}}}`)
+			So(param, ShouldSerializeJSON, `{"in":"body","schema":{"type":"array","items":{"$ref":"Cat"}}}`)
 		})
 	})
 }
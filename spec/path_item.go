@@ -2,6 +2,7 @@ package spec
 
 import (
 	"encoding/json"
+	"strings"
 
 	"github.com/aiyi/swagger-gin/jsonpointer"
 	"github.com/aiyi/swagger-gin/swag"
@@ -31,6 +32,50 @@ type PathItem struct {
 	PathItemProps
 }
 
+// NewPathItem creates a new path item instance
+func NewPathItem() *PathItem {
+	return new(PathItem)
+}
+
+// WithOperation sets the operation of a method on this path item, the method is
+// one of get, put, post, delete, options, head and patch in any case. A nil
+// operation removes the operation of the method.
+func (p *PathItem) WithOperation(method string, op *Operation) *PathItem {
+	switch strings.ToLower(method) {
+	case "get":
+		p.Get = op
+	case "put":
+		p.Put = op
+	case "post":
+		p.Post = op
+	case "delete":
+		p.Delete = op
+	case "options":
+		p.Options = op
+	case "head":
+		p.Head = op
+	case "patch":
+		p.Patch = op
+	}
+	return p
+}
+
+// AddParam adds a parameter shared by the operations of this path item, it replaces
+// the parameter with the same name and location. A nil parameter is ignored.
+func (p *PathItem) AddParam(param *Parameter) *PathItem {
+	if param == nil {
+		return p
+	}
+	for i, existing := range p.Parameters {
+		if existing.Name == param.Name && existing.In == param.In {
+			p.Parameters[i] = *param
+			return p
+		}
+	}
+	p.Parameters = append(p.Parameters, *param)
+	return p
+}
+
 // JSONLookup look up a value by the json property name
 func (p PathItem) JSONLookup(token string) (interface{}, error) {
 	if ex, ok := p.Extensions[token]; ok {
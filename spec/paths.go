@@ -76,6 +76,10 @@ func (p Paths) MarshalJSON() ([]byte, error) {
 			pths[k] = v
 		}
 	}
+	if pths == nil && len(p.Extensions) == 0 {
+		// the paths are required, an API without paths has an empty object
+		return []byte("{}"), nil
+	}
 	b2, err := json.Marshal(pths)
 	if err != nil {
 		return nil, err
@@ -92,16 +92,38 @@ func init() {
 	swaggerSchema = MustLoadSwagger20Schema()
 }
 
-// Load loads a new spec document
+// YAMLSpec loads a spec from a yaml document
+func YAMLSpec(path string) (*Document, error) {
+	data, err := swag.YAMLDoc(path)
+	if err != nil {
+		return nil, err
+	}
+	return New(data, "")
+}
+
+// Load loads a new spec document, either json or yaml. The format is picked
+// from the extension of the path and when that's inconclusive from the content.
+// References to other documents are resolved against the location of the spec.
 func Load(path string) (*Document, error) {
-	return JSONSpec(path)
+	return LoadWithOptions(path, LoaderOptions{})
 }
 
-// New creates a new shema document
+// New creates a new shema document, the version is read from the document when
+// it's empty. OpenAPI 3.0 documents are converted to swagger 2.0, see ConvertOpenAPI3.
 func New(data json.RawMessage, version string) (*Document, error) {
+	if version == "" {
+		version = specVersion(data)
+	}
 	if version == "" {
 		version = "2.0"
 	}
+	if isOpenAPI3(version) {
+		converted, err := ConvertOpenAPI3(data)
+		if err != nil {
+			return nil, err
+		}
+		data, version = converted, "2.0"
+	}
 	if version != "2.0" {
 		return nil, fmt.Errorf("spec version %q is not supported", version)
 	}
@@ -132,6 +154,7 @@ func (d *Document) Expanded() (*Document, error) {
 	if err := json.Unmarshal(d.raw, spec); err != nil {
 		return nil, err
 	}
+	spec.loader = d.spec.loader
 	if err := expandSpec(spec); err != nil {
 		return nil, err
 	}
@@ -151,6 +174,17 @@ func (d *Document) Expanded() (*Document, error) {
 	return dd, nil
 }
 
+// Derive creates a new spec document from data, a variant of this document,
+// which loads the documents it references the same way this document does
+func (d *Document) Derive(data json.RawMessage) (*Document, error) {
+	dd, err := New(data, d.Version())
+	if err != nil {
+		return nil, err
+	}
+	dd.spec.loader = d.spec.loader
+	return dd, nil
+}
+
 // BasePath the base path for this spec
 func (d *Document) BasePath() string {
 	return d.spec.BasePath
@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"testing"
 
-	testingutil "github.com/aiyi/swagger-gin/internal/testing"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -14,7 +13,7 @@ func TestUnknownSpecVersion(t *testing.T) {
 }
 
 func TestDefaultsTo20(t *testing.T) {
-	d, err := New(testingutil.PetStoreJSONMessage, "")
+	d, err := New([]byte(petstoreJSON), "")
 
 	assert.NoError(t, err)
 	assert.NotNil(t, d)
@@ -24,7 +23,7 @@ func TestDefaultsTo20(t *testing.T) {
 }
 
 // func TestValidatesValidSchema(t *testing.T) {
-// 	d, err := New(testingutil.PetStoreJSONMessage, "")
+// 	d, err := New([]byte(petstoreJSON), "")
 
 // 	assert.NoError(t, err)
 // 	assert.NotNil(t, d)
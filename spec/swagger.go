@@ -15,6 +15,123 @@ import (
 // For more information: http://goo.gl/8us55a#swagger-object-
 type Swagger struct {
 	swaggerProps
+	// loader loads the documents the spec references, it's set when the spec is loaded from a location
+	loader *docLoader
+}
+
+// NewSwagger creates a new swagger 2.0 document with the title and version of the API
+func NewSwagger(title, version string) *Swagger {
+	s := new(Swagger)
+	s.Swagger = "2.0"
+	s.Info = new(Info)
+	s.Info.Title = title
+	s.Info.Version = version
+	s.Paths = new(Paths)
+	return s
+}
+
+// WithHost sets the host serving the API
+func (s *Swagger) WithHost(host string) *Swagger {
+	s.Host = host
+	return s
+}
+
+// WithBasePath sets the path the paths of the API are relative to
+func (s *Swagger) WithBasePath(basePath string) *Swagger {
+	s.BasePath = basePath
+	return s
+}
+
+// WithSchemes adds transfer protocols of the API
+func (s *Swagger) WithSchemes(schemes ...string) *Swagger {
+	s.Schemes = append(s.Schemes, schemes...)
+	return s
+}
+
+// WithConsumes adds media types the operations consume by default
+func (s *Swagger) WithConsumes(mediaTypes ...string) *Swagger {
+	s.Consumes = append(s.Consumes, mediaTypes...)
+	return s
+}
+
+// WithProduces adds media types the operations produce by default
+func (s *Swagger) WithProduces(mediaTypes ...string) *Swagger {
+	s.Produces = append(s.Produces, mediaTypes...)
+	return s
+}
+
+// AddPath sets the path item of a path, it replaces the path item the path had
+func (s *Swagger) AddPath(path string, item *PathItem) *Swagger {
+	if s.Paths == nil {
+		s.Paths = new(Paths)
+	}
+	if s.Paths.Paths == nil {
+		s.Paths.Paths = make(map[string]PathItem)
+	}
+	s.Paths.Paths[path] = *item
+	return s
+}
+
+// AddOperation sets the operation of a method on a path, the other operations
+// of the path are kept
+func (s *Swagger) AddOperation(method, path string, op *Operation) *Swagger {
+	var item PathItem
+	if s.Paths != nil {
+		item = s.Paths.Paths[path]
+	}
+	return s.AddPath(path, item.WithOperation(method, op))
+}
+
+// AddDefinition adds a schema to the definitions
+func (s *Swagger) AddDefinition(name string, schema *Schema) *Swagger {
+	if s.Definitions == nil {
+		s.Definitions = make(Definitions)
+	}
+	s.Definitions[name] = *schema
+	return s
+}
+
+// AddParameter adds a parameter the operations can reference to the parameters
+func (s *Swagger) AddParameter(name string, param *Parameter) *Swagger {
+	if s.Parameters == nil {
+		s.Parameters = make(map[string]Parameter)
+	}
+	s.Parameters[name] = *param
+	return s
+}
+
+// AddResponse adds a response the operations can reference to the responses
+func (s *Swagger) AddResponse(name string, response *Response) *Swagger {
+	if s.Responses == nil {
+		s.Responses = make(map[string]Response)
+	}
+	s.Responses[name] = *response
+	return s
+}
+
+// AddSecurityDefinition adds a security scheme the operations can require
+func (s *Swagger) AddSecurityDefinition(name string, scheme *SecurityScheme) *Swagger {
+	if s.SecurityDefinitions == nil {
+		s.SecurityDefinitions = make(SecurityDefinitions)
+	}
+	s.SecurityDefinitions[name] = scheme
+	return s
+}
+
+// SecuredWith adds a security requirement of one scheme to every operation without
+// security requirements of its own, the scopes are the ones of an oauth2 scheme
+func (s *Swagger) SecuredWith(name string, scopes ...string) *Swagger {
+	if scopes == nil {
+		scopes = []string{}
+	}
+	s.Security = append(s.Security, map[string][]string{name: scopes})
+	return s
+}
+
+// AddTag adds a tag with its description
+func (s *Swagger) AddTag(name, description string) *Swagger {
+	s.Tags = append(s.Tags, NewTag(name, description, nil))
+	return s
 }
 
 // MarshalJSON marshals this swagger structure to json
@@ -22,6 +139,12 @@ func (s Swagger) MarshalJSON() ([]byte, error) {
 	return json.Marshal(s.swaggerProps)
 }
 
+// JSONLookup look up a value by the json property name
+func (s Swagger) JSONLookup(token string) (interface{}, error) {
+	r, _, err := jsonpointer.GetForToken(s.swaggerProps, token)
+	return r, err
+}
+
 // UnmarshalJSON unmarshals a swagger spec from json
 func (s *Swagger) UnmarshalJSON(data []byte) error {
 	var sw Swagger
@@ -88,6 +211,9 @@ func (s SchemaOrBool) MarshalJSON() ([]byte, error) {
 func (s *SchemaOrBool) UnmarshalJSON(data []byte) error {
 	var nw SchemaOrBool
 	if len(data) < 4 {
+		if data[0] == '{' { // an empty schema allows anything
+			*s = SchemaOrBool{Allows: true}
+		}
 		return nil
 	}
 	if data[0] == '{' {
@@ -96,6 +222,7 @@ func (s *SchemaOrBool) UnmarshalJSON(data []byte) error {
 			return err
 		}
 		if reflect.DeepEqual(Schema{}, sch) {
+			*s = SchemaOrBool{Allows: true}
 			return nil
 		}
 		nw.Schema = &sch
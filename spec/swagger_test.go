@@ -25,9 +25,9 @@ var spec = Swagger{
 		Host:        "some.api.out.there",
 		BasePath:    "/",
 		Paths:       &paths,
-		Definitions: map[string]Schema{"Category": Schema{schemaProps: schemaProps{Type: []string{"string"}}}},
+		Definitions: map[string]Schema{"Category": Schema{SchemaProps: SchemaProps{Type: []string{"string"}}}},
 		Parameters: map[string]Parameter{
-			"categoryParam": Parameter{paramProps: paramProps{Name: "category", In: "query"}, SimpleSchema: SimpleSchema{Type: "string"}},
+			"categoryParam": Parameter{ParamProps: ParamProps{Name: "category", In: "query"}, SimpleSchema: SimpleSchema{Type: "string"}},
 		},
 		Responses: map[string]Response{
 			"EmptyAnswer": Response{
@@ -0,0 +1,564 @@
+package spec
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/aiyi/swagger-gin/jsonpointer"
+)
+
+// the media type of the bodies and responses when neither the operation nor the spec tells
+const defaultMediaType = "application/json"
+
+// the $refs of swagger 2.0 that have an OpenAPI 3.0 counterpart, references
+// to body parameters become references to request bodies instead
+var swaggerRefs = map[string]string{
+	"#/definitions/": "#/components/schemas/",
+	"#/parameters/":  "#/components/parameters/",
+	"#/responses/":   "#/components/responses/",
+}
+
+// the swagger 2.0 oauth2 flows and the OpenAPI 3.0 flows they become
+var oauth2Flows = map[string]string{
+	"accessCode":  "authorizationCode",
+	"implicit":    "implicit",
+	"password":    "password",
+	"application": "clientCredentials",
+}
+
+// ConvertToOpenAPI3 converts a swagger 2.0 spec to an OpenAPI 3.0 document.
+//
+// The definitions, parameters, responses and security definitions become components,
+// body and formData parameters become request bodies, the schema of the responses
+// becomes their content for every media type the operation produces, the host, base
+// path and schemes become the servers. It's the reverse of ConvertOpenAPI3, the vendor
+// extensions ConvertOpenAPI3 adds, like x-nullable, are turned back into keywords.
+func ConvertToOpenAPI3(sw *Swagger) (json.RawMessage, error) {
+	data, err := json.Marshal(sw)
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	c := &swaggerConverter{
+		doc:        doc,
+		parameters: object(doc["parameters"]),
+		consumes:   stringList(doc["consumes"]),
+		produces:   stringList(doc["produces"]),
+	}
+	return json.Marshal(c.convert())
+}
+
+type swaggerConverter struct {
+	doc        map[string]interface{}
+	parameters map[string]interface{}
+	consumes   []string
+	produces   []string
+}
+
+func stringList(v interface{}) []string {
+	values, _ := v.([]interface{})
+	var result []string
+	for _, value := range values {
+		if s, ok := value.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+func (c *swaggerConverter) convert() map[string]interface{} {
+	result := map[string]interface{}{"openapi": "3.0.3"}
+	for _, k := range []string{"info", "tags", "externalDocs", "security"} {
+		if v, ok := c.doc[k]; ok {
+			result[k] = v
+		}
+	}
+	copyExtensions(result, c.doc)
+	if servers := c.convertServers(); len(servers) > 0 {
+		result["servers"] = servers
+	}
+
+	components := make(map[string]interface{})
+	if definitions := object(c.doc["definitions"]); definitions != nil {
+		schemas := make(map[string]interface{}, len(definitions))
+		for name, schema := range definitions {
+			schemas[name] = convertSwaggerSchema(schema)
+		}
+		components["schemas"] = schemas
+	}
+	parameters := make(map[string]interface{})
+	requestBodies := make(map[string]interface{})
+	for name, p := range c.parameters {
+		param := object(p)
+		switch param["in"] {
+		case "body":
+			requestBodies[name] = c.convertBody(param, c.consumes)
+		case "formData":
+			// form parameters are inlined in the request body of the operations using them
+		default:
+			parameters[name] = c.convertParameter(param)
+		}
+	}
+	if len(parameters) > 0 {
+		components["parameters"] = parameters
+	}
+	if len(requestBodies) > 0 {
+		components["requestBodies"] = requestBodies
+	}
+	if resps := object(c.doc["responses"]); resps != nil {
+		responses := make(map[string]interface{}, len(resps))
+		for name, resp := range resps {
+			responses[name] = c.convertResponse(resp, c.produces)
+		}
+		components["responses"] = responses
+	}
+	if definitions := object(c.doc["securityDefinitions"]); definitions != nil {
+		schemes := make(map[string]interface{}, len(definitions))
+		for name, def := range definitions {
+			schemes[name] = convertSecurityDefinition(object(def))
+		}
+		components["securitySchemes"] = schemes
+	}
+	if len(components) > 0 {
+		result["components"] = components
+	}
+
+	paths := make(map[string]interface{})
+	for path, item := range object(c.doc["paths"]) {
+		if strings.HasPrefix(path, "x-") {
+			paths[path] = item
+			continue
+		}
+		paths[path] = c.convertPathItem(object(item))
+	}
+	result["paths"] = paths
+	return result
+}
+
+// convertServers makes a server of every scheme, a spec without schemes gets a
+// server relative to where the document is served from, like swagger 2.0 does
+func (c *swaggerConverter) convertServers() []interface{} {
+	host, _ := c.doc["host"].(string)
+	basePath, _ := c.doc["basePath"].(string)
+	if host == "" {
+		if basePath == "" {
+			return nil
+		}
+		return []interface{}{map[string]interface{}{"url": basePath}}
+	}
+	schemes := stringList(c.doc["schemes"])
+	if len(schemes) == 0 {
+		return []interface{}{map[string]interface{}{"url": "//" + host + basePath}}
+	}
+	servers := make([]interface{}, 0, len(schemes))
+	for _, scheme := range schemes {
+		servers = append(servers, map[string]interface{}{"url": scheme + "://" + host + basePath})
+	}
+	return servers
+}
+
+func swaggerRefPrefix(ref string) string {
+	for from, to := range swaggerRefs {
+		if strings.HasPrefix(ref, from) {
+			return to + strings.TrimPrefix(ref, from)
+		}
+	}
+	return ref
+}
+
+// convertSwaggerSchema rewrites the references of a schema and turns the vendor
+// extensions standing for OpenAPI 3.0 keywords back into those keywords
+func convertSwaggerSchema(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(value))
+		for k, item := range value {
+			switch {
+			case k == "x-nullable" || k == "x-isnullable":
+				if b, _ := item.(bool); b {
+					result["nullable"] = true
+				}
+			case k == "x-writeOnly" || k == "x-deprecated":
+				result[strings.TrimPrefix(k, "x-")] = item
+			case k == "x-discriminator-mapping":
+			case strings.HasPrefix(k, "x-") || k == "example" || k == "enum" || k == "default" || k == "required":
+				result[k] = item
+			case k == "$ref":
+				ref, _ := item.(string)
+				result[k] = swaggerRefPrefix(ref)
+			case k == "type" && item == "file":
+				result[k] = "string"
+				result["format"] = "binary"
+			case k == "discriminator":
+				discriminator := map[string]interface{}{"propertyName": item}
+				if mapping := object(value["x-discriminator-mapping"]); mapping != nil {
+					converted := make(map[string]interface{}, len(mapping))
+					for name, target := range mapping {
+						ref, _ := target.(string)
+						converted[name] = swaggerRefPrefix(ref)
+					}
+					discriminator["mapping"] = converted
+				}
+				result[k] = discriminator
+			case k == "properties" || k == "patternProperties":
+				props := make(map[string]interface{}, len(object(item)))
+				for name, prop := range object(item) {
+					props[name] = convertSwaggerSchema(prop)
+				}
+				result[k] = props
+			default:
+				result[k] = convertSwaggerSchema(item)
+			}
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(value))
+		for i, item := range value {
+			result[i] = convertSwaggerSchema(item)
+		}
+		return result
+	}
+	return v
+}
+
+// lookupParameter returns the parameter a reference to the parameters of the spec points to
+func (c *swaggerConverter) lookupParameter(v interface{}) (map[string]interface{}, string) {
+	param := object(v)
+	ref, ok := param["$ref"].(string)
+	if !ok || !strings.HasPrefix(ref, "#/parameters/") {
+		return param, ""
+	}
+	name := jsonpointer.Unescape(strings.TrimPrefix(ref, "#/parameters/"))
+	if target := object(c.parameters[name]); target != nil {
+		return target, name
+	}
+	return param, ""
+}
+
+// convertParameter moves the simple schema of a parameter to its schema
+// and turns its collection format into a style
+func (c *swaggerConverter) convertParameter(param map[string]interface{}) map[string]interface{} {
+	if ref, ok := param["$ref"].(string); ok {
+		return map[string]interface{}{"$ref": swaggerRefPrefix(ref)}
+	}
+	result := make(map[string]interface{})
+	for _, k := range []string{"name", "in", "description", "required", "allowEmptyValue"} {
+		if v, ok := param[k]; ok {
+			result[k] = v
+		}
+	}
+	copyExtensions(result, param)
+	result["schema"] = convertSimpleSchema(param)
+
+	if param["type"] == "array" {
+		in, _ := param["in"].(string)
+		switch param["collectionFormat"] {
+		case "multi":
+			result["style"], result["explode"] = "form", true
+		case "ssv":
+			result["style"], result["explode"] = "spaceDelimited", false
+		case "pipes":
+			result["style"], result["explode"] = "pipeDelimited", false
+		default:
+			if in == "query" || in == "formData" {
+				result["style"], result["explode"] = "form", false
+			} else {
+				result["style"], result["explode"] = "simple", false
+			}
+		}
+	}
+	return result
+}
+
+// convertSimpleSchema turns the simple schema of a parameter, items or header into a schema
+func convertSimpleSchema(simple map[string]interface{}) map[string]interface{} {
+	schema := make(map[string]interface{})
+	if tpe, ok := simple["type"]; ok {
+		schema["type"] = tpe
+	}
+	for _, k := range simpleSchemaKeys {
+		if v, ok := simple[k]; ok {
+			schema[k] = v
+		}
+	}
+	if simple["type"] == "file" {
+		schema["type"], schema["format"] = "string", "binary"
+	}
+	if items := object(simple["items"]); items != nil {
+		schema["items"] = convertSimpleSchema(items)
+	}
+	return schema
+}
+
+func (c *swaggerConverter) convertPathItem(item map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{})
+	copyExtensions(result, item)
+	if ref, ok := item["$ref"]; ok {
+		result["$ref"] = ref
+	}
+	// the body and form parameters of the path item go to the request body of every operation
+	shared, _ := item["parameters"].([]interface{})
+	var params []interface{}
+	for _, p := range shared {
+		if param, _ := c.lookupParameter(p); param["in"] != "body" && param["in"] != "formData" {
+			params = append(params, c.convertParameter(object(p)))
+		}
+	}
+	if len(params) > 0 {
+		result["parameters"] = params
+	}
+	for _, method := range openAPI3Methods {
+		if op := object(item[method]); op != nil {
+			result[method] = c.convertOperation(op, shared)
+		}
+	}
+	return result
+}
+
+func (c *swaggerConverter) convertOperation(op map[string]interface{}, shared []interface{}) map[string]interface{} {
+	result := make(map[string]interface{})
+	for _, k := range []string{"tags", "summary", "description", "externalDocs", "operationId", "deprecated", "security"} {
+		if v, ok := op[k]; ok {
+			result[k] = v
+		}
+	}
+	copyExtensions(result, op)
+
+	consumes := c.consumes
+	if _, ok := op["consumes"]; ok {
+		consumes = stringList(op["consumes"])
+	}
+	produces := c.produces
+	if _, ok := op["produces"]; ok {
+		produces = stringList(op["produces"])
+	}
+
+	own, _ := op["parameters"].([]interface{})
+	var params, form []interface{}
+	var body interface{}
+	addBody := func(p interface{}) {
+		param, name := c.lookupParameter(p)
+		switch param["in"] {
+		case "body":
+			if name != "" {
+				body = map[string]interface{}{"$ref": "#/components/requestBodies/" + jsonpointer.Escape(name)}
+			} else {
+				body = c.convertBody(param, consumes)
+			}
+		case "formData":
+			form = append(form, param)
+		}
+	}
+	// the parameters of the operation override the ones of the path item with the same name and location
+	overridden := make(map[string]bool)
+	for _, p := range own {
+		param, _ := c.lookupParameter(p)
+		overridden[parameterKey(param)] = true
+	}
+	for _, p := range shared {
+		if param, _ := c.lookupParameter(p); !overridden[parameterKey(param)] {
+			addBody(p)
+		}
+	}
+	for _, p := range own {
+		if param, _ := c.lookupParameter(p); param["in"] == "body" || param["in"] == "formData" {
+			addBody(p)
+		} else {
+			params = append(params, c.convertParameter(object(p)))
+		}
+	}
+	if len(params) > 0 {
+		result["parameters"] = params
+	}
+	if body != nil {
+		result["requestBody"] = body
+	} else if len(form) > 0 {
+		result["requestBody"] = convertForm(form, consumes)
+	}
+
+	responses := make(map[string]interface{})
+	for code, resp := range object(op["responses"]) {
+		if strings.HasPrefix(code, "x-") {
+			responses[code] = resp
+			continue
+		}
+		responses[code] = c.convertResponse(resp, produces)
+	}
+	result["responses"] = responses
+	return result
+}
+
+func parameterKey(param map[string]interface{}) string {
+	in, _ := param["in"].(string)
+	name, _ := param["name"].(string)
+	return in + ":" + name
+}
+
+// convertBody turns a body parameter into a request body with the schema for every media type
+// it consumes, the name of the parameter is kept in x-codegen-request-body-name
+func (c *swaggerConverter) convertBody(param map[string]interface{}, consumes []string) map[string]interface{} {
+	mediaTypes := make([]string, 0, len(consumes))
+	for _, mt := range consumes {
+		if !isFormMediaType(mt) {
+			mediaTypes = append(mediaTypes, mt)
+		}
+	}
+	if len(mediaTypes) == 0 {
+		mediaTypes = []string{defaultMediaType}
+	}
+	schema := convertSwaggerSchema(param["schema"])
+	content := make(map[string]interface{}, len(mediaTypes))
+	for _, mt := range mediaTypes {
+		content[mt] = map[string]interface{}{"schema": schema}
+	}
+
+	result := map[string]interface{}{"content": content}
+	if d, ok := param["description"]; ok {
+		result["description"] = d
+	}
+	if required, _ := param["required"].(bool); required {
+		result["required"] = true
+	}
+	copyExtensions(result, param)
+	if name, _ := param["name"].(string); name != "" && name != "body" {
+		result["x-codegen-request-body-name"] = name
+	}
+	return result
+}
+
+// convertForm turns formData parameters into a request body with an object schema,
+// files make it multipart when the operation doesn't tell which form it consumes
+func convertForm(params []interface{}, consumes []string) map[string]interface{} {
+	properties := make(map[string]interface{}, len(params))
+	var required []interface{}
+	hasFile := false
+	for _, p := range params {
+		param := object(p)
+		name, _ := param["name"].(string)
+		prop := convertSimpleSchema(param)
+		if d, ok := param["description"]; ok {
+			prop["description"] = d
+		}
+		properties[name] = prop
+		if r, _ := param["required"].(bool); r {
+			required = append(required, name)
+		}
+		if param["type"] == "file" {
+			hasFile = true
+		}
+	}
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+
+	var mediaTypes []string
+	for _, mt := range consumes {
+		if isFormMediaType(mt) {
+			mediaTypes = append(mediaTypes, mt)
+		}
+	}
+	if len(mediaTypes) == 0 {
+		mediaTypes = []string{formMediaTypes[0]}
+		if hasFile {
+			mediaTypes = []string{formMediaTypes[1]}
+		}
+	}
+	content := make(map[string]interface{}, len(mediaTypes))
+	for _, mt := range mediaTypes {
+		content[mt] = map[string]interface{}{"schema": schema}
+	}
+	result := map[string]interface{}{"content": content}
+	if len(required) > 0 {
+		result["required"] = true
+	}
+	return result
+}
+
+// convertResponse puts the schema of a response in its content for every media type
+// the operation produces, the examples go to the content of their media type
+func (c *swaggerConverter) convertResponse(v interface{}, produces []string) map[string]interface{} {
+	resp := object(v)
+	if ref, ok := resp["$ref"].(string); ok {
+		return map[string]interface{}{"$ref": swaggerRefPrefix(ref)}
+	}
+	result := map[string]interface{}{"description": resp["description"]}
+	if result["description"] == nil {
+		result["description"] = ""
+	}
+	copyExtensions(result, resp)
+
+	content := make(map[string]interface{})
+	if schema, ok := resp["schema"]; ok {
+		mediaTypes := produces
+		if len(mediaTypes) == 0 {
+			mediaTypes = []string{defaultMediaType}
+		}
+		converted := convertSwaggerSchema(schema)
+		for _, mt := range mediaTypes {
+			content[mt] = map[string]interface{}{"schema": converted}
+		}
+	}
+	examples := object(resp["examples"])
+	for _, mt := range sortedKeys(examples) {
+		media := object(content[mt])
+		if media == nil {
+			media = make(map[string]interface{})
+			content[mt] = media
+		}
+		media["example"] = examples[mt]
+	}
+	if len(content) > 0 {
+		result["content"] = content
+	}
+
+	if headers := object(resp["headers"]); headers != nil {
+		converted := make(map[string]interface{}, len(headers))
+		for name, h := range headers {
+			header := object(h)
+			result := map[string]interface{}{"schema": convertSimpleSchema(header)}
+			if d, ok := header["description"]; ok {
+				result["description"] = d
+			}
+			converted[name] = result
+		}
+		result["headers"] = converted
+	}
+	return result
+}
+
+// convertSecurityDefinition maps a security definition to a security scheme
+func convertSecurityDefinition(def map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{})
+	if d, ok := def["description"]; ok {
+		result["description"] = d
+	}
+	copyExtensions(result, def)
+	switch def["type"] {
+	case "basic":
+		result["type"] = "http"
+		result["scheme"] = "basic"
+	case "apiKey":
+		result["type"] = "apiKey"
+		result["name"] = def["name"]
+		result["in"] = def["in"]
+	case "oauth2":
+		flow := make(map[string]interface{})
+		for _, k := range []string{"authorizationUrl", "tokenUrl"} {
+			if v, ok := def[k]; ok {
+				flow[k] = v
+			}
+		}
+		flow["scopes"] = def["scopes"]
+		if flow["scopes"] == nil {
+			flow["scopes"] = map[string]interface{}{}
+		}
+		name, _ := def["flow"].(string)
+		result["type"] = "oauth2"
+		result["flows"] = map[string]interface{}{oauth2Flows[name]: flow}
+	}
+	return result
+}
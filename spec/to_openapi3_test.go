@@ -0,0 +1,154 @@
+package spec
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const swaggerPets = `{
+  "swagger": "2.0",
+  "info": {"title": "pets", "version": "1.0"},
+  "host": "pets.example.com",
+  "basePath": "/api",
+  "schemes": ["https", "http"],
+  "produces": ["application/json", "application/xml"],
+  "paths": {
+    "/pets": {
+      "post": {
+        "operationId": "addPet",
+        "parameters": [{"$ref": "#/parameters/pet"}],
+        "responses": {
+          "200": {"description": "ok", "schema": {"$ref": "#/definitions/Pet"}, "examples": {"application/json": {"name": "rex"}}},
+          "default": {"$ref": "#/responses/Error"}
+        }
+      }
+    },
+    "/pets/{petId}": {
+      "parameters": [{"name": "petId", "in": "path", "required": true, "type": "integer", "format": "int64"}],
+      "put": {
+        "operationId": "updatePet",
+        "consumes": ["application/json"],
+        "parameters": [
+          {"name": "tags", "in": "query", "type": "array", "items": {"type": "string"}, "collectionFormat": "multi"},
+          {"name": "pet", "in": "body", "required": true, "schema": {"$ref": "#/definitions/Pet"}}
+        ],
+        "responses": {"204": {"description": "updated", "headers": {"X-Rate-Limit": {"type": "integer", "format": "int32"}}}}
+      },
+      "post": {
+        "operationId": "uploadPhoto",
+        "parameters": [
+          {"name": "photo", "in": "formData", "required": true, "type": "file"},
+          {"name": "caption", "in": "formData", "type": "string"}
+        ],
+        "responses": {"200": {"description": "ok"}}
+      }
+    }
+  },
+  "parameters": {
+    "pet": {"name": "body", "in": "body", "required": true, "schema": {"$ref": "#/definitions/Pet"}}
+  },
+  "responses": {
+    "Error": {"description": "error", "schema": {"type": "object"}}
+  },
+  "definitions": {
+    "Pet": {
+      "type": "object",
+      "required": ["name"],
+      "discriminator": "kind",
+      "properties": {
+        "name": {"type": "string"},
+        "kind": {"type": "string"},
+        "tag": {"type": "string", "x-nullable": true},
+        "owner": {"$ref": "#/definitions/Person"}
+      }
+    },
+    "Person": {"type": "object", "properties": {"name": {"type": "string"}}}
+  },
+  "securityDefinitions": {
+    "basic": {"type": "basic"},
+    "oauth": {"type": "oauth2", "flow": "accessCode", "authorizationUrl": "https://example.com/auth", "tokenUrl": "https://example.com/token", "scopes": {"read": "read pets"}}
+  }
+}`
+
+func TestConvertToOpenAPI3(t *testing.T) {
+	doc, err := New(json.RawMessage(swaggerPets), "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	data, err := ConvertToOpenAPI3(doc.Spec())
+	if !assert.NoError(t, err) {
+		return
+	}
+	var converted map[string]interface{}
+	if !assert.NoError(t, json.Unmarshal(data, &converted)) {
+		return
+	}
+	get := func(path ...string) interface{} {
+		var v interface{} = converted
+		for _, p := range path {
+			v = object(v)[p]
+		}
+		return v
+	}
+
+	assert.Equal(t, "3.0.3", converted["openapi"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"url": "https://pets.example.com/api"},
+		map[string]interface{}{"url": "http://pets.example.com/api"},
+	}, converted["servers"])
+
+	assert.Equal(t, "#/components/schemas/Person", get("components", "schemas", "Pet", "properties", "owner", "$ref"))
+	assert.Equal(t, true, get("components", "schemas", "Pet", "properties", "tag", "nullable"))
+	assert.Equal(t, map[string]interface{}{"propertyName": "kind"}, get("components", "schemas", "Pet", "discriminator"))
+	assert.Equal(t, "#/components/schemas/Pet", get("components", "requestBodies", "pet", "content", "application/json", "schema", "$ref"))
+	assert.Equal(t, "object", get("components", "responses", "Error", "content", "application/xml", "schema", "type"))
+
+	assert.Equal(t, map[string]interface{}{"$ref": "#/components/requestBodies/pet"}, get("paths", "/pets", "post", "requestBody"))
+	assert.Equal(t, "#/components/schemas/Pet", get("paths", "/pets", "post", "responses", "200", "content", "application/xml", "schema", "$ref"))
+	assert.Equal(t, map[string]interface{}{"name": "rex"}, get("paths", "/pets", "post", "responses", "200", "content", "application/json", "example"))
+	assert.Equal(t, "#/components/responses/Error", get("paths", "/pets", "post", "responses", "default", "$ref"))
+
+	put := object(get("paths", "/pets/{petId}", "put"))
+	if params, ok := put["parameters"].([]interface{}); assert.True(t, ok) && assert.Len(t, params, 1) {
+		tags := object(params[0])
+		assert.Equal(t, "form", tags["style"])
+		assert.Equal(t, true, tags["explode"])
+		assert.Equal(t, "array", object(tags["schema"])["type"])
+	}
+	body := object(put["requestBody"])
+	assert.Equal(t, true, body["required"])
+	assert.Equal(t, "pet", body["x-codegen-request-body-name"])
+	assert.Equal(t, []string{"application/json"}, sortedKeys(body["content"]))
+	assert.Equal(t, "int32", get("paths", "/pets/{petId}", "put", "responses", "204", "headers", "X-Rate-Limit", "schema", "format"))
+
+	form := object(get("paths", "/pets/{petId}", "post", "requestBody", "content", "multipart/form-data", "schema"))
+	assert.Equal(t, []interface{}{"photo"}, form["required"])
+	assert.Equal(t, map[string]interface{}{"type": "string", "format": "binary"}, object(form["properties"])["photo"])
+	assert.Nil(t, get("paths", "/pets/{petId}", "post", "parameters"))
+	assert.Len(t, get("paths", "/pets/{petId}", "parameters"), 1)
+
+	assert.Equal(t, map[string]interface{}{"type": "http", "scheme": "basic"}, get("components", "securitySchemes", "basic"))
+	assert.Equal(t, "https://example.com/token", get("components", "securitySchemes", "oauth", "flows", "authorizationCode", "tokenUrl"))
+
+	// converting the document back gives the same operations
+	back, err := New(data, "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	sw := back.Spec()
+	assert.Equal(t, "pets.example.com", sw.Host)
+	assert.Equal(t, "/api", sw.BasePath)
+	update := sw.Paths.Paths["/pets/{petId}"].Put
+	if assert.NotNil(t, update) && assert.Len(t, update.Parameters, 2) {
+		assert.Equal(t, "pet", update.Parameters[1].Name)
+		assert.Equal(t, "body", update.Parameters[1].In)
+		assert.Equal(t, "multi", update.Parameters[0].CollectionFormat)
+	}
+	upload := sw.Paths.Paths["/pets/{petId}"].Post
+	if assert.NotNil(t, upload) && assert.Len(t, upload.Parameters, 2) {
+		assert.Equal(t, "caption", upload.Parameters[0].Name)
+		assert.Equal(t, "file", upload.Parameters[1].Type)
+	}
+}
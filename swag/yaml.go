@@ -0,0 +1,168 @@
+package swag
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// YAMLDoc loads a yaml document from either a file or a remote url and converts it to json
+func YAMLDoc(path string) (json.RawMessage, error) {
+	data, err := LoadFromFileOrHTTP(path)
+	if err != nil {
+		return nil, err
+	}
+	return YAMLToJSON(data)
+}
+
+// JSONOrYAMLDoc loads a json or yaml document from either a file or a remote url,
+// yaml documents are converted to json
+func JSONOrYAMLDoc(path string) (json.RawMessage, error) {
+	data, err := LoadFromFileOrHTTP(path)
+	if err != nil {
+		return nil, err
+	}
+	if IsYAML(path, data) {
+		return YAMLToJSON(data)
+	}
+	return json.RawMessage(data), nil
+}
+
+// IsYAML tells if a document is yaml, based on the extension of its path and
+// when that's not conclusive, on its content: json documents start with { or [
+func IsYAML(path string, data []byte) bool {
+	if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path = path[:i]
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yml", ".yaml":
+		return true
+	case ".json":
+		return false
+	}
+	trimmed := bytes.TrimSpace(data)
+	return len(trimmed) > 0 && trimmed[0] != '{' && trimmed[0] != '['
+}
+
+// YAMLToJSON converts a yaml document to json, keeping the order of the keys.
+// Keys that aren't strings, like the status codes of responses, become strings.
+func YAMLToJSON(data []byte) (json.RawMessage, error) {
+	var doc yaml.MapSlice
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		// the document isn't a mapping, it could still be a list or a scalar
+		var any interface{}
+		if err := yaml.Unmarshal(data, &any); err != nil {
+			return nil, err
+		}
+		converted, err := yamlToJSONValue(any)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(converted)
+	}
+	converted, err := yamlToJSONValue(doc)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(converted)
+}
+
+// jsonMapSlice is a json object that keeps its keys in order
+type jsonMapSlice []jsonMapItem
+
+type jsonMapItem struct {
+	Key   string
+	Value interface{}
+}
+
+func (s jsonMapSlice) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, item := range s {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(item.Key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		v, err := json.Marshal(item.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func yamlToJSONValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case yaml.MapSlice:
+		result := make(jsonMapSlice, 0, len(v))
+		for _, item := range v {
+			key, err := yamlKey(item.Key)
+			if err != nil {
+				return nil, err
+			}
+			val, err := yamlToJSONValue(item.Value)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, jsonMapItem{Key: key, Value: val})
+		}
+		return result, nil
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			key, err := yamlKey(k)
+			if err != nil {
+				return nil, err
+			}
+			val, err := yamlToJSONValue(item)
+			if err != nil {
+				return nil, err
+			}
+			result[key] = val
+		}
+		return result, nil
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			val, err := yamlToJSONValue(item)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = val
+		}
+		return result, nil
+	}
+	return value, nil
+}
+
+func yamlKey(key interface{}) (string, error) {
+	switch k := key.(type) {
+	case string:
+		return k, nil
+	case int:
+		return strconv.Itoa(k), nil
+	case int64:
+		return strconv.FormatInt(k, 10), nil
+	case uint64:
+		return strconv.FormatUint(k, 10), nil
+	case float64:
+		return strconv.FormatFloat(k, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(k), nil
+	case nil:
+		return "null", nil
+	}
+	return "", fmt.Errorf("unsupported map key %#v in yaml document", key)
+}
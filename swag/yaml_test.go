@@ -0,0 +1,69 @@
+package swag
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const petstoreYAML = `swagger: "2.0"
+info:
+  title: pets
+  version: "1.0"
+paths:
+  /pets:
+    get:
+      responses:
+        200:
+          description: ok
+        default:
+          description: error
+`
+
+func TestYAMLToJSON(t *testing.T) {
+	data, err := YAMLToJSON([]byte(petstoreYAML))
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{
+  "swagger": "2.0",
+  "info": {"title": "pets", "version": "1.0"},
+  "paths": {"/pets": {"get": {"responses": {
+    "200": {"description": "ok"},
+    "default": {"description": "error"}
+  }}}}
+}`, string(data))
+	}
+
+	data, err = YAMLToJSON([]byte("b: 1\na: [true, 1.5, null]\n"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, `{"b":1,"a":[true,1.5,null]}`, string(data))
+	}
+
+	_, err = YAMLToJSON([]byte("? [1, 2]\n: value\n"))
+	assert.Error(t, err)
+}
+
+func TestIsYAML(t *testing.T) {
+	assert.True(t, IsYAML("swagger.yml", []byte(`{}`)))
+	assert.True(t, IsYAML("http://example.com/swagger.yaml?v=1", nil))
+	assert.False(t, IsYAML("swagger.json", []byte("swagger: '2.0'")))
+	assert.False(t, IsYAML("swagger", []byte("  {\"swagger\": \"2.0\"}")))
+	assert.True(t, IsYAML("swagger", []byte("swagger: '2.0'")))
+}
+
+func TestJSONOrYAMLDoc(t *testing.T) {
+	dir, err := ioutil.TempDir("", "swag-yaml")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "swagger")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(petstoreYAML), 0644))
+	data, err := JSONOrYAMLDoc(path)
+	if assert.NoError(t, err) {
+		assert.Contains(t, string(data), `"200":{"description":"ok"}`)
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/aiyi/swagger-gin/errors"
+	"github.com/aiyi/swagger-gin/spec"
+	"github.com/aiyi/swagger-gin/validate"
+)
+
+// validateCmd checks a spec against the swagger 2.0 meta-schema and the semantic rules,
+// every finding is printed with the json pointer to the offending node
+func validateCmd(args []string) int {
+	flags := flag.NewFlagSet("validate", flag.ExitOnError)
+	opts, err := loadOpts(args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	bindLoaderFlags(flags, &opts)
+	flags.Parse(args)
+
+	doc, err := spec.LoadWithOptions(opts.Spec, spec.LoaderOptions{BaseDir: opts.BaseDir, URLMappings: opts.URLMappings})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	err = validate.Spec(doc)
+	if err == nil {
+		fmt.Printf("%s is a valid swagger 2.0 spec\n", opts.Spec)
+		return 0
+	}
+	findings := errors.Flatten(err)
+	for _, f := range findings {
+		fmt.Println(f.Message)
+	}
+	fmt.Fprintf(os.Stderr, "%d problem(s) found in %s\n", len(findings), opts.Spec)
+	return 1
+}
@@ -0,0 +1,522 @@
+package validate
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aiyi/swagger-gin/errors"
+	"github.com/aiyi/swagger-gin/jsonpointer"
+	"github.com/aiyi/swagger-gin/spec"
+	"github.com/aiyi/swagger-gin/swag"
+	"github.com/asaskevich/govalidator"
+)
+
+// the maximum number of $ref hops followed before a schema is considered circular
+const maxRefChain = 32
+
+// AgainstSchema validates untyped data, as decoded by encoding/json, against a json schema.
+// References in the schema are resolved against the root document, which is usually the
+// *spec.Swagger the schema was taken from. All the failures are collected and returned
+// as a composite validation error.
+func AgainstSchema(path, in string, schema *spec.Schema, root interface{}, data interface{}) error {
+	if schema == nil {
+		return nil
+	}
+	if root == nil {
+		root = schema
+	}
+	v := &schemaValidator{in: in, rootName: in, join: joinPath}
+	if errs := v.validate(path, schema, root, data); len(errs) > 0 {
+		return errors.CompositeValidationError(errs...)
+	}
+	return nil
+}
+
+type schemaValidator struct {
+	in       string
+	rootName string
+	join     func(path, name string) string
+}
+
+// joinPath builds the dotted paths used to report errors in request and response data
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// joinPointer builds json pointers, used to report errors in documents like a spec
+func joinPointer(path, name string) string {
+	return path + "/" + jsonpointer.Escape(name)
+}
+
+func (v *schemaValidator) name(path string) string {
+	if path == "" {
+		return v.rootName
+	}
+	return path
+}
+
+// resolve follows the $ref chain of a schema, when the reference points to
+// another document that document becomes the root for the nested references.
+func (v *schemaValidator) resolve(schema *spec.Schema, root interface{}) (*spec.Schema, interface{}, error) {
+	for i := 0; schema.Ref.String() != "" || schema.Ref.IsRoot(); i++ {
+		if i == maxRefChain {
+			return nil, nil, fmt.Errorf("circular reference %q", schema.Ref.String())
+		}
+		if schema.Ref.IsRoot() && schema.Ref.String() == "" {
+			sch, ok := root.(*spec.Schema)
+			if !ok {
+				return nil, nil, fmt.Errorf("reference to root is only allowed in schema documents")
+			}
+			schema = sch
+			continue
+		}
+
+		u := schema.Ref.GetURL()
+		if u.Host != "" || u.Path != "" {
+			loc := *u
+			loc.Fragment = ""
+			docRef, err := spec.NewRef(loc.String())
+			if err != nil {
+				return nil, nil, err
+			}
+			doc, err := spec.ResolveRef(root, &docRef)
+			if err != nil {
+				return nil, nil, err
+			}
+			root = doc
+			if u.Fragment == "" {
+				schema = doc
+				continue
+			}
+		}
+
+		ref, err := spec.NewRef("#" + u.Fragment)
+		if err != nil {
+			return nil, nil, err
+		}
+		resolved, err := spec.ResolveRef(root, &ref)
+		if err != nil {
+			return nil, nil, err
+		}
+		schema = resolved
+	}
+	return schema, root, nil
+}
+
+func (v *schemaValidator) validate(path string, schema *spec.Schema, root interface{}, data interface{}) []error {
+	schema, root, err := v.resolve(schema, root)
+	if err != nil {
+		return []error{errors.InvalidType(v.name(path), v.in, "schema", err)}
+	}
+	data = normalize(data)
+
+	if data == nil && isNullable(schema) {
+		return nil
+	}
+
+	if len(schema.Type) > 0 && !typeMatches(schema.Type, data) {
+		return []error{errors.InvalidType(v.name(path), v.in, strings.Join(schema.Type, ","), data)}
+	}
+
+	var result []error
+	if len(schema.Enum) > 0 {
+		if err := enumContains(v.name(path), v.in, data, schema.Enum); err != nil {
+			result = append(result, err)
+		}
+	}
+
+	switch value := data.(type) {
+	case float64:
+		result = append(result, v.validateNumber(path, schema, value)...)
+	case string:
+		result = append(result, v.validateString(path, schema, value)...)
+	case []interface{}:
+		result = append(result, v.validateArray(path, schema, root, value)...)
+	case map[string]interface{}:
+		result = append(result, v.validateObject(path, schema, root, value)...)
+	}
+
+	result = append(result, v.validateComposition(path, schema, root, data)...)
+	return result
+}
+
+func (v *schemaValidator) validateNumber(path string, schema *spec.Schema, data float64) []error {
+	var result []error
+	name := v.name(path)
+	if schema.MultipleOf != nil && *schema.MultipleOf != 0 {
+		if err := MultipleOf(name, v.in, data, *schema.MultipleOf); err != nil {
+			result = append(result, err)
+		}
+	}
+	if schema.Maximum != nil {
+		if err := Maximum(name, v.in, data, *schema.Maximum, schema.ExclusiveMaximum); err != nil {
+			result = append(result, err)
+		}
+	}
+	if schema.Minimum != nil {
+		if err := Minimum(name, v.in, data, *schema.Minimum, schema.ExclusiveMinimum); err != nil {
+			result = append(result, err)
+		}
+	}
+	return result
+}
+
+func (v *schemaValidator) validateString(path string, schema *spec.Schema, data string) []error {
+	var result []error
+	name := v.name(path)
+	if schema.MaxLength != nil {
+		if err := MaxLength(name, v.in, data, *schema.MaxLength); err != nil {
+			result = append(result, err)
+		}
+	}
+	if schema.MinLength != nil {
+		if err := MinLength(name, v.in, data, *schema.MinLength); err != nil {
+			result = append(result, err)
+		}
+	}
+	if schema.Pattern != "" {
+		if re, err := compilePattern(schema.Pattern); err == nil && !re.MatchString(data) {
+			result = append(result, errors.FailedPattern(name, v.in, schema.Pattern))
+		}
+	}
+	if schema.Format != "" {
+		if err := FormatOf(name, v.in, schema.Format, data); err != nil {
+			result = append(result, err)
+		}
+	}
+	return result
+}
+
+func (v *schemaValidator) validateArray(path string, schema *spec.Schema, root interface{}, data []interface{}) []error {
+	var result []error
+	name := v.name(path)
+	size := int64(len(data))
+	if schema.MinItems != nil {
+		if err := MinItems(name, v.in, size, *schema.MinItems); err != nil {
+			result = append(result, err)
+		}
+	}
+	if schema.MaxItems != nil {
+		if err := MaxItems(name, v.in, size, *schema.MaxItems); err != nil {
+			result = append(result, err)
+		}
+	}
+	if schema.UniqueItems {
+		if err := UniqueItems(name, v.in, data); err != nil {
+			result = append(result, err)
+		}
+	}
+
+	if schema.Items == nil {
+		return result
+	}
+	if schema.Items.Schema != nil {
+		for i, item := range data {
+			result = append(result, v.validate(v.join(path, strconv.Itoa(i)), schema.Items.Schema, root, item)...)
+		}
+		return result
+	}
+
+	for i, item := range data {
+		ip := v.join(path, strconv.Itoa(i))
+		if i < len(schema.Items.Schemas) {
+			result = append(result, v.validate(ip, &schema.Items.Schemas[i], root, item)...)
+			continue
+		}
+		if schema.AdditionalItems == nil {
+			break
+		}
+		if schema.AdditionalItems.Schema != nil {
+			result = append(result, v.validate(ip, schema.AdditionalItems.Schema, root, item)...)
+			continue
+		}
+		if !schema.AdditionalItems.Allows {
+			result = append(result, errors.AdditionalItemsNotAllowed(name, v.in))
+			break
+		}
+	}
+	return result
+}
+
+func (v *schemaValidator) validateObject(path string, schema *spec.Schema, root interface{}, data map[string]interface{}) []error {
+	var result []error
+	name := v.name(path)
+	size := int64(len(data))
+	if schema.MinProperties != nil && size < *schema.MinProperties {
+		result = append(result, errors.TooFewProperties(name, v.in, *schema.MinProperties))
+	}
+	if schema.MaxProperties != nil && size > *schema.MaxProperties {
+		result = append(result, errors.TooManyProperties(name, v.in, *schema.MaxProperties))
+	}
+
+	for _, req := range schema.Required {
+		if _, ok := data[req]; !ok {
+			result = append(result, errors.Required(v.join(path, req), v.in))
+		}
+	}
+
+	// iterate in a stable order so the errors are reported deterministically
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	patterns := make([]string, 0, len(schema.PatternProperties))
+	for pattern := range schema.PatternProperties {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+
+	for _, k := range keys {
+		value := data[k]
+		kp := v.join(path, k)
+		matched := false
+		if prop, ok := schema.Properties[k]; ok {
+			matched = true
+			result = append(result, v.validate(kp, &prop, root, value)...)
+		}
+		for _, pattern := range patterns {
+			prop := schema.PatternProperties[pattern]
+			re, err := compilePattern(pattern)
+			if err != nil || !re.MatchString(k) {
+				continue
+			}
+			matched = true
+			result = append(result, v.validate(kp, &prop, root, value)...)
+		}
+		if matched || schema.AdditionalProperties == nil {
+			continue
+		}
+		if schema.AdditionalProperties.Schema != nil {
+			result = append(result, v.validate(kp, schema.AdditionalProperties.Schema, root, value)...)
+			continue
+		}
+		if !schema.AdditionalProperties.Allows {
+			// the property is named after the path to it, a property of the root after
+			// itself and not after where the root is
+			err := errors.PropertyNotAllowed("", v.in, kp)
+			err.Value = k
+			result = append(result, err)
+		}
+	}
+
+	deps := make([]string, 0, len(schema.Dependencies))
+	for k := range schema.Dependencies {
+		deps = append(deps, k)
+	}
+	sort.Strings(deps)
+	for _, k := range deps {
+		dep := schema.Dependencies[k]
+		if _, ok := data[k]; !ok {
+			continue
+		}
+		if dep.Schema != nil {
+			result = append(result, v.validate(path, dep.Schema, root, data)...)
+		}
+		for _, req := range dep.Property {
+			if _, ok := data[req]; !ok {
+				result = append(result, errors.Required(v.join(path, req), v.in))
+			}
+		}
+	}
+	return result
+}
+
+func (v *schemaValidator) validateComposition(path string, schema *spec.Schema, root interface{}, data interface{}) []error {
+	var result []error
+	name := v.name(path)
+	for i := range schema.AllOf {
+		result = append(result, v.validate(path, &schema.AllOf[i], root, data)...)
+	}
+
+	if len(schema.AnyOf) > 0 {
+		var valid bool
+		for i := range schema.AnyOf {
+			if len(v.validate(path, &schema.AnyOf[i], root, data)) == 0 {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			result = append(result, errors.AnyOfFailed(name, v.in))
+		}
+	}
+
+	if len(schema.OneOf) > 0 {
+		var valid int
+		for i := range schema.OneOf {
+			if len(v.validate(path, &schema.OneOf[i], root, data)) == 0 {
+				valid++
+			}
+		}
+		if valid != 1 {
+			result = append(result, errors.OneOfFailed(name, v.in))
+		}
+	}
+
+	if schema.Not != nil {
+		if len(v.validate(path, schema.Not, root, data)) == 0 {
+			result = append(result, errors.NotFailed(name, v.in))
+		}
+	}
+	return result
+}
+
+func isNullable(schema *spec.Schema) bool {
+	for _, k := range []string{"x-nullable", "x-isnullable"} {
+		if v, ok := schema.Extensions[k].(bool); ok && v {
+			return true
+		}
+	}
+	return schema.Type.Contains("null")
+}
+
+func typeMatches(types spec.StringOrArray, data interface{}) bool {
+	for _, tpe := range types {
+		switch tpe {
+		case "null":
+			if data == nil {
+				return true
+			}
+		case "boolean":
+			if _, ok := data.(bool); ok {
+				return true
+			}
+		case "string":
+			if _, ok := data.(string); ok {
+				return true
+			}
+		case "number":
+			if _, ok := data.(float64); ok {
+				return true
+			}
+		case "integer":
+			if f, ok := data.(float64); ok && swag.IsFloat64AJSONInteger(f) {
+				return true
+			}
+		case "array":
+			if _, ok := data.([]interface{}); ok {
+				return true
+			}
+		case "object":
+			if _, ok := data.(map[string]interface{}); ok {
+				return true
+			}
+		case "file":
+			return true
+		}
+	}
+	return false
+}
+
+// normalize converts the numeric representations that can show up in decoded
+// documents to float64, so they can be compared with the values from a spec
+func normalize(data interface{}) interface{} {
+	switch value := data.(type) {
+	case nil, bool, string, float64, []interface{}, map[string]interface{}:
+		return data
+	case interface {
+		Float64() (float64, error)
+	}:
+		if f, err := value.Float64(); err == nil {
+			return f
+		}
+		return data
+	}
+
+	rv := reflect.ValueOf(data)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint())
+	case reflect.Float32:
+		return rv.Float()
+	case reflect.Slice:
+		res := make([]interface{}, rv.Len())
+		for i := range res {
+			res[i] = normalize(rv.Index(i).Interface())
+		}
+		return res
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return data
+		}
+		res := make(map[string]interface{}, rv.Len())
+		for _, k := range rv.MapKeys() {
+			res[k.String()] = normalize(rv.MapIndex(k).Interface())
+		}
+		return res
+	}
+	return data
+}
+
+func enumContains(path, in string, data interface{}, enum []interface{}) *errors.Validation {
+	for _, e := range enum {
+		if reflect.DeepEqual(normalize(e), data) {
+			return nil
+		}
+	}
+	return errors.EnumFail(path, in, data, enum)
+}
+
+var patterns = struct {
+	sync.Mutex
+	cache map[string]*regexp.Regexp
+}{cache: make(map[string]*regexp.Regexp)}
+
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	patterns.Lock()
+	defer patterns.Unlock()
+	if re, ok := patterns.cache[pattern]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	patterns.cache[pattern] = re
+	return re, nil
+}
+
+var formatCheckers = map[string]func(string) bool{
+	"date-time": func(s string) bool {
+		_, err := time.Parse(time.RFC3339, s)
+		return err == nil
+	},
+	"date": func(s string) bool {
+		_, err := time.Parse("2006-01-02", s)
+		return err == nil
+	},
+	"email":    govalidator.IsEmail,
+	"uri":      govalidator.IsRequestURI,
+	"hostname": govalidator.IsDNSName,
+	"ipv4":     govalidator.IsIPv4,
+	"ipv6":     govalidator.IsIPv6,
+	"uuid":     govalidator.IsUUID,
+}
+
+// FormatOf validates a string against a known format, unknown formats are accepted
+func FormatOf(path, in, format, data string) *errors.Validation {
+	check, ok := formatCheckers[format]
+	if !ok {
+		fn, ok := govalidator.TagMap[format]
+		if !ok {
+			return nil
+		}
+		check = fn
+	}
+	if !check(data) {
+		return errors.InvalidType(path, in, format, data)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package validate
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aiyi/swagger-gin/errors"
+	"github.com/aiyi/swagger-gin/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+const petSpec = `{
+  "swagger": "2.0",
+  "info": {"title": "pets", "version": "1.0"},
+  "paths": {},
+  "definitions": {
+    "Category": {
+      "type": "object",
+      "required": ["name"],
+      "properties": {"name": {"type": "string", "minLength": 2}}
+    },
+    "Pet": {
+      "type": "object",
+      "required": ["name", "photoUrls"],
+      "additionalProperties": false,
+      "properties": {
+        "id": {"type": "integer", "minimum": 1},
+        "name": {"type": "string"},
+        "category": {"$ref": "#/definitions/Category"},
+        "photoUrls": {"type": "array", "maxItems": 2, "items": {"type": "string", "format": "uri"}},
+        "status": {"type": "string", "enum": ["available", "sold"]}
+      }
+    }
+  }
+}`
+
+func loadPetSpec(t *testing.T) *spec.Swagger {
+	doc, err := spec.New(json.RawMessage(petSpec), "")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return doc.Spec()
+}
+
+func decode(t *testing.T, data string) interface{} {
+	var v interface{}
+	if !assert.NoError(t, json.Unmarshal([]byte(data), &v)) {
+		t.FailNow()
+	}
+	return v
+}
+
+func errorNames(err error) []string {
+	var names []string
+	for _, b := range errors.Flatten(err) {
+		names = append(names, b.Name)
+	}
+	return names
+}
+
+func TestAgainstSchema_Valid(t *testing.T) {
+	sw := loadPetSpec(t)
+	pet := sw.Definitions["Pet"]
+	data := decode(t, `{"id": 1, "name": "kitty", "category": {"name": "cats"}, "photoUrls": ["/a.png"], "status": "sold"}`)
+	assert.NoError(t, AgainstSchema("", "body", &pet, sw, data))
+}
+
+func TestAgainstSchema_CollectsAll(t *testing.T) {
+	sw := loadPetSpec(t)
+	pet := sw.Definitions["Pet"]
+	data := decode(t, `{"id": 0, "category": {"name": "c"}, "photoUrls": ["/a", "/b", "/c"], "status": "lost", "color": "red"}`)
+
+	err := AgainstSchema("", "body", &pet, sw, data)
+	if assert.Error(t, err) {
+		assert.EqualValues(t, 422, errors.StatusCode(err))
+		assert.Equal(t, []string{"name", "category.name", "color", "id", "photoUrls", "status"}, errorNames(err))
+		assert.Contains(t, err.Error(), "color in body is a forbidden property")
+	}
+}
+
+func TestAgainstSchema_PatternPropertiesOrder(t *testing.T) {
+	schema := new(spec.Schema).Typed("object", "")
+	schema.PatternProperties = map[string]spec.Schema{}
+	for _, pattern := range []string{"^a", "^ab", "^abc", "b$", "c$", "^.b", "^..c"} {
+		schema.PatternProperties[pattern] = *new(spec.Schema).Typed("integer", "")
+	}
+	data := decode(t, `{"abc": "x"}`)
+
+	// every pattern matching reports a failure, always in the same order
+	first := AgainstSchema("", "body", schema, nil, data).Error()
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, first, AgainstSchema("", "body", schema, nil, data).Error())
+	}
+}
+
+func TestAgainstSchema_Types(t *testing.T) {
+	schema := new(spec.Schema).Typed("integer", "")
+	assert.NoError(t, AgainstSchema("limit", "query", schema, nil, float64(3)))
+	assert.Error(t, AgainstSchema("limit", "query", schema, nil, 3.5))
+	assert.Error(t, AgainstSchema("limit", "query", schema, nil, "3"))
+
+	nullable := new(spec.Schema).Typed("string", "")
+	nullable.AddExtension("x-nullable", true)
+	assert.NoError(t, AgainstSchema("name", "body", nullable, nil, nil))
+}
+
+func TestAgainstSchema_Composition(t *testing.T) {
+	str := *new(spec.Schema).Typed("string", "")
+	num := *new(spec.Schema).Typed("number", "")
+
+	anyOf := &spec.Schema{SchemaProps: spec.SchemaProps{AnyOf: []spec.Schema{str, num}}}
+	assert.NoError(t, AgainstSchema("v", "body", anyOf, nil, "a"))
+	assert.Error(t, AgainstSchema("v", "body", anyOf, nil, true))
+
+	oneOf := &spec.Schema{SchemaProps: spec.SchemaProps{OneOf: []spec.Schema{str, str}}}
+	assert.Error(t, AgainstSchema("v", "body", oneOf, nil, "a"))
+
+	not := &spec.Schema{SchemaProps: spec.SchemaProps{Not: &str}}
+	assert.NoError(t, AgainstSchema("v", "body", not, nil, 1.0))
+	assert.Error(t, AgainstSchema("v", "body", not, nil, "a"))
+}
+
+func TestFormatOf(t *testing.T) {
+	assert.Nil(t, FormatOf("d", "query", "date", "2016-01-02"))
+	assert.NotNil(t, FormatOf("d", "query", "date", "02/01/2016"))
+	assert.NotNil(t, FormatOf("e", "query", "email", "nope"))
+	assert.Nil(t, FormatOf("x", "query", "unknown-format", "anything"))
+}
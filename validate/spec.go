@@ -0,0 +1,239 @@
+package validate
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aiyi/swagger-gin/errors"
+	"github.com/aiyi/swagger-gin/jsonpointer"
+	"github.com/aiyi/swagger-gin/spec"
+)
+
+var pathParamRx = regexp.MustCompile(`\{([^{}/]+)\}`)
+
+// Spec validates a swagger document, first against the swagger 2.0 meta-schema and
+// then with the semantic checks the meta-schema can't express: unique operationIds,
+// path parameters that are declared and used, resolvable references and parameters
+// that are unique by name and location.
+//
+// Every finding is an *errors.Validation whose name is the json pointer to the
+// offending node, they are returned together in a composite error.
+func Spec(doc *spec.Document) error {
+	var raw interface{}
+	if err := json.Unmarshal(doc.Raw(), &raw); err != nil {
+		return errors.CompositeValidationError(errors.ParseError("/", "", "", err))
+	}
+
+	v := &schemaValidator{rootName: "/", join: joinPointer}
+	result := v.validate("", doc.Schema(), doc.Schema(), raw)
+
+	sv := &specValidator{doc: doc, raw: raw}
+	result = append(result, sv.validateReferences("", raw, false)...)
+	result = append(result, sv.validateOperations()...)
+
+	if len(result) > 0 {
+		return errors.CompositeValidationError(result...)
+	}
+	return nil
+}
+
+type specValidator struct {
+	doc *spec.Document
+	raw interface{}
+}
+
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch"}
+
+func operationOf(item *spec.PathItem, method string) *spec.Operation {
+	switch method {
+	case "get":
+		return item.Get
+	case "put":
+		return item.Put
+	case "post":
+		return item.Post
+	case "delete":
+		return item.Delete
+	case "options":
+		return item.Options
+	case "head":
+		return item.Head
+	case "patch":
+		return item.Patch
+	}
+	return nil
+}
+
+func (s *specValidator) validateOperations() []error {
+	sw := s.doc.Spec()
+	if sw.Paths == nil {
+		return nil
+	}
+	paths := make([]string, 0, len(sw.Paths.Paths))
+	for path := range sw.Paths.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var result []error
+	operationIDs := make(map[string]string)
+	for _, path := range paths {
+		item := sw.Paths.Paths[path]
+		itemPtr := joinPointer("/paths", path)
+		result = append(result, s.uniqueParams(itemPtr, item.Parameters)...)
+
+		for _, method := range methods {
+			op := operationOf(&item, method)
+			if op == nil {
+				continue
+			}
+			opPtr := joinPointer(itemPtr, method)
+			if op.ID != "" {
+				if first, ok := operationIDs[op.ID]; ok {
+					result = append(result, errors.DuplicateOperationID(opPtr+"/operationId", op.ID, first))
+				} else {
+					operationIDs[op.ID] = opPtr
+				}
+			}
+			result = append(result, s.uniqueParams(opPtr, op.Parameters)...)
+			result = append(result, s.pathParams(path, itemPtr, &item, opPtr, op)...)
+		}
+	}
+	return result
+}
+
+func (s *specValidator) uniqueParams(ptr string, params []spec.Parameter) []error {
+	var result []error
+	seen := make(map[string]bool)
+	for i, p := range params {
+		param, ok := s.resolveParam(p)
+		if !ok {
+			continue
+		}
+		key := param.In + "#" + param.Name
+		if seen[key] {
+			result = append(result, errors.DuplicateParam(ptr+"/parameters/"+strconv.Itoa(i), param.Name, param.In))
+		}
+		seen[key] = true
+	}
+	return result
+}
+
+func (s *specValidator) pathParams(path, itemPtr string, item *spec.PathItem, opPtr string, op *spec.Operation) []error {
+	used := make(map[string]bool)
+	for _, m := range pathParamRx.FindAllStringSubmatch(path, -1) {
+		used[m[1]] = true
+	}
+
+	var result []error
+	declared := make(map[string]bool)
+	check := func(ptr string, params []spec.Parameter) {
+		for i, p := range params {
+			param, ok := s.resolveParam(p)
+			if !ok || param.In != "path" {
+				continue
+			}
+			declared[param.Name] = true
+			if !used[param.Name] {
+				result = append(result, errors.UnusedPathParam(ptr+"/parameters/"+strconv.Itoa(i), param.Name))
+			}
+		}
+	}
+	check(itemPtr, item.Parameters)
+	check(opPtr, op.Parameters)
+
+	names := make([]string, 0, len(used))
+	for name := range used {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !declared[name] {
+			result = append(result, errors.UndeclaredPathParam(opPtr, name))
+		}
+	}
+	return result
+}
+
+// resolveParam follows a reference to the parameters section, unresolvable
+// references are reported by validateReferences
+func (s *specValidator) resolveParam(param spec.Parameter) (spec.Parameter, bool) {
+	for i := 0; param.Ref.String() != ""; i++ {
+		frag := param.Ref.GetURL().Fragment
+		if i == maxRefChain || !strings.HasPrefix(frag, "/parameters/") {
+			return param, false
+		}
+		p, ok := s.doc.Spec().Parameters[jsonpointer.Unescape(strings.TrimPrefix(frag, "/parameters/"))]
+		if !ok {
+			return param, false
+		}
+		param = p
+	}
+	return param, true
+}
+
+// nameKeys are the keywords whose values map names to nodes, a name is never a vendor
+// extension or an example
+var nameKeys = map[string]bool{
+	"definitions":         true,
+	"parameters":          true,
+	"securityDefinitions": true,
+	"properties":          true,
+	"patternProperties":   true,
+	"dependencies":        true,
+	"headers":             true,
+}
+
+// validateReferences checks that every $ref in the document can be resolved, the
+// vendor extensions and examples are free form so they are skipped where they are
+// keywords. The keys of a node are names rather than keywords when names is set.
+func (s *specValidator) validateReferences(ptr string, node interface{}, names bool) []error {
+	var result []error
+	switch value := node.(type) {
+	case map[string]interface{}:
+		if ref, ok := value["$ref"].(string); ok && !names {
+			if err := s.resolve(ref); err != nil {
+				result = append(result, errors.UnresolvedRef(ptr, ref, err))
+			}
+		}
+		keys := make([]string, 0, len(value))
+		for k := range value {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if !names && (strings.HasPrefix(k, "x-") || k == "example" || k == "examples") {
+				continue
+			}
+			// the responses of the document are named, the ones of an operation are
+			// keyed by status code and may have extensions
+			childNames := !names && (nameKeys[k] || (ptr == "" && k == "responses"))
+			result = append(result, s.validateReferences(joinPointer(ptr, k), value[k], childNames)...)
+		}
+	case []interface{}:
+		for i, v := range value {
+			result = append(result, s.validateReferences(joinPointer(ptr, strconv.Itoa(i)), v, false)...)
+		}
+	}
+	return result
+}
+
+func (s *specValidator) resolve(ref string) error {
+	if strings.HasPrefix(ref, "#") {
+		ptr, err := jsonpointer.New(strings.TrimPrefix(ref, "#"))
+		if err != nil {
+			return err
+		}
+		_, _, err = ptr.Get(s.raw)
+		return err
+	}
+	r, err := spec.NewRef(ref)
+	if err != nil {
+		return err
+	}
+	_, err = spec.ResolveRef(s.doc.Spec(), &r)
+	return err
+}
@@ -0,0 +1,149 @@
+package validate
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aiyi/swagger-gin/errors"
+	"github.com/aiyi/swagger-gin/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+func loadDoc(t *testing.T, data string) *spec.Document {
+	doc, err := spec.New(json.RawMessage(data), "")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return doc
+}
+
+func TestSpec_Valid(t *testing.T) {
+	doc := loadDoc(t, `{
+  "swagger": "2.0",
+  "info": {"title": "pets", "version": "1.0"},
+  "parameters": {"id": {"name": "id", "in": "path", "required": true, "type": "integer"}},
+  "paths": {
+    "/pets/{id}": {
+      "parameters": [{"$ref": "#/parameters/id"}],
+      "get": {
+        "operationId": "getPet",
+        "responses": {"200": {"description": "ok", "schema": {"$ref": "#/definitions/Pet"}}}
+      }
+    }
+  },
+  "definitions": {"Pet": {"type": "object", "properties": {"name": {"type": "string"}}}}
+}`)
+	assert.NoError(t, Spec(doc))
+}
+
+func TestSpec_Findings(t *testing.T) {
+	doc := loadDoc(t, `{
+  "swagger": "2.0",
+  "info": {"title": "pets"},
+  "paths": {
+    "/pets/{id}": {
+      "get": {
+        "operationId": "getPet",
+        "parameters": [
+          {"name": "q", "in": "query", "type": "string"},
+          {"name": "q", "in": "query", "type": "string"},
+          {"name": "other", "in": "path", "required": true, "type": "string"}
+        ],
+        "responses": {"200": {"description": "ok", "schema": {"$ref": "#/definitions/Missing"}}}
+      },
+      "put": {
+        "operationId": "getPet",
+        "parameters": [{"name": "id", "in": "path", "required": true, "type": "string"}],
+        "responses": {"200": {"description": "ok"}}
+      }
+    }
+  }
+}`)
+
+	err := Spec(doc)
+	if !assert.Error(t, err) {
+		return
+	}
+	var names []string
+	for _, f := range errors.Flatten(err) {
+		names = append(names, f.Name)
+	}
+	assert.Equal(t, []string{
+		"/info/version",
+		"/paths/~1pets~1{id}/get/parameters",
+		"/paths/~1pets~1{id}/get/responses/200/schema",
+		"/paths/~1pets~1{id}/get/parameters/1",
+		"/paths/~1pets~1{id}/get/parameters/2",
+		"/paths/~1pets~1{id}/get",
+		"/paths/~1pets~1{id}/put/operationId",
+	}, names)
+}
+
+func TestSpec_ForbiddenProperty(t *testing.T) {
+	doc := loadDoc(t, `{
+  "swagger": "2.0",
+  "info": {"title": "pets", "version": "1.0", "a/b": 1},
+  "paths": {},
+  "bogus": true
+}`)
+
+	err := Spec(doc)
+	if !assert.Error(t, err) {
+		return
+	}
+	// the properties are named after the json pointer to them
+	flat := errors.Flatten(err)
+	if assert.Len(t, flat, 2) {
+		assert.Equal(t, "/bogus", flat[0].Name)
+		assert.Equal(t, "/bogus is a forbidden property", flat[0].Message)
+		assert.Equal(t, "bogus", flat[0].Value)
+		assert.Equal(t, "/info/a~1b", flat[1].Name)
+		assert.Equal(t, "/info/a~1b is a forbidden property", flat[1].Message)
+	}
+}
+
+func TestSpec_ReferencesInNamedNodes(t *testing.T) {
+	doc := loadDoc(t, `{
+  "swagger": "2.0",
+  "info": {"title": "pets", "version": "1.0"},
+  "paths": {
+    "/pets": {
+      "x-notes": {"$ref": "#/nowhere"},
+      "get": {
+        "responses": {
+          "200": {"description": "ok", "examples": {"application/json": {"$ref": "#/nowhere"}}},
+          "x-extra": {"$ref": "#/nowhere"}
+        }
+      }
+    }
+  },
+  "responses": {"example": {"description": "ok", "schema": {"$ref": "#/definitions/Missing"}}},
+  "definitions": {
+    "Pet": {
+      "type": "object",
+      "example": {"$ref": "#/nowhere"},
+      "properties": {
+        "example": {"$ref": "#/definitions/Missing"},
+        "x-tag": {"$ref": "#/definitions/Missing"},
+        "examples": {"type": "array", "items": {"$ref": "#/definitions/Missing"}}
+      }
+    }
+  }
+}`)
+
+	err := Spec(doc)
+	if !assert.Error(t, err) {
+		return
+	}
+	// the properties named like keywords are checked, the keywords are skipped
+	var names []string
+	for _, f := range errors.Flatten(err) {
+		names = append(names, f.Name)
+	}
+	assert.Equal(t, []string{
+		"/definitions/Pet/properties/example",
+		"/definitions/Pet/properties/examples/items",
+		"/definitions/Pet/properties/x-tag",
+		"/responses/example/schema",
+	}, names)
+}
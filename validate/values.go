@@ -44,6 +44,22 @@ func MaxItems(path, in string, size, max int64) *errors.Validation {
 	return nil
 }
 
+// MinProperties validates that there are at least n properties in an object
+func MinProperties(path, in string, size, min int64) *errors.Validation {
+	if size < min {
+		return errors.TooFewProperties(path, in, min)
+	}
+	return nil
+}
+
+// MaxProperties validates that there are at most n properties in an object
+func MaxProperties(path, in string, size, max int64) *errors.Validation {
+	if size > max {
+		return errors.TooManyProperties(path, in, max)
+	}
+	return nil
+}
+
 // UniqueItems validates that the provided slice has unique elements
 func UniqueItems(path, in string, data interface{}) *errors.Validation {
 	val := reflect.ValueOf(data)
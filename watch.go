@@ -0,0 +1,158 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/aiyi/swagger-gin/generator"
+	"github.com/aiyi/swagger-gin/swag"
+)
+
+// fileState is what's compared between two polls to detect a change
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// watch polls the spec and the local files it references and regenerates the code
+// when any of them changes, problems are printed and the watch goes on
+func watch(opts generator.GenOpts, interval time.Duration) {
+	var last map[string]fileState
+	log.Printf("watching %s", opts.Spec)
+	for {
+		current := snapshot(specFiles(opts.Spec, opts.BaseDir))
+		if !sameState(last, current) {
+			last = current
+			regenerate(&opts)
+		}
+		time.Sleep(interval)
+	}
+}
+
+func regenerate(opts *generator.GenOpts) {
+	if !generate(opts) {
+		log.Printf("generation failed, waiting for the next change")
+		return
+	}
+	written, err := opts.Files.Write()
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	if len(written) == 0 {
+		log.Printf("no changes")
+		return
+	}
+	for _, path := range written {
+		log.Printf("wrote %s", path)
+	}
+}
+
+func snapshot(files []string) map[string]fileState {
+	state := make(map[string]fileState, len(files))
+	for _, file := range files {
+		fi, err := os.Stat(file)
+		if err != nil {
+			// a missing file is a state too, it changes when the file comes back
+			state[file] = fileState{}
+			continue
+		}
+		state[file] = fileState{modTime: fi.ModTime(), size: fi.Size()}
+	}
+	return state
+}
+
+func sameState(a, b map[string]fileState) bool {
+	if a == nil || len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if w, ok := b[k]; !ok || !w.modTime.Equal(v.modTime) || w.size != v.size {
+			return false
+		}
+	}
+	return true
+}
+
+// specFiles returns the spec together with the local files it references
+// through $ref, directly or through other referenced files. The references of the
+// spec are relative to baseDir when it's set.
+func specFiles(spec, baseDir string) []string {
+	seen := map[string]bool{}
+	var files []string
+	pending := []string{filepath.Clean(spec)}
+	for len(pending) > 0 {
+		file := pending[0]
+		pending = pending[1:]
+		if seen[file] {
+			continue
+		}
+		seen[file] = true
+		files = append(files, file)
+
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		if swag.IsYAML(file, b) {
+			if b, err = swag.YAMLToJSON(b); err != nil {
+				continue
+			}
+		}
+		var doc interface{}
+		if err := json.Unmarshal(b, &doc); err != nil {
+			continue
+		}
+		for _, ref := range collectRefs(doc, nil) {
+			dir := filepath.Dir(file)
+			if len(files) == 1 && baseDir != "" {
+				dir = baseDir
+			}
+			if local, ok := localFile(dir, ref); ok {
+				pending = append(pending, local)
+			}
+		}
+	}
+	return files
+}
+
+func collectRefs(node interface{}, refs []string) []string {
+	switch value := node.(type) {
+	case map[string]interface{}:
+		if ref, ok := value["$ref"].(string); ok {
+			refs = append(refs, ref)
+		}
+		for _, v := range value {
+			refs = collectRefs(v, refs)
+		}
+	case []interface{}:
+		for _, v := range value {
+			refs = collectRefs(v, refs)
+		}
+	}
+	return refs
+}
+
+// localFile resolves the document part of a reference to a file on disk,
+// references to remote documents and within the same document are left out
+func localFile(dir, ref string) (string, bool) {
+	u, err := url.Parse(ref)
+	if err != nil || u.Path == "" {
+		return "", false
+	}
+	switch u.Scheme {
+	case "":
+		if filepath.IsAbs(u.Path) {
+			return filepath.Clean(u.Path), true
+		}
+		return filepath.Join(dir, filepath.FromSlash(u.Path)), true
+	case "file":
+		return filepath.Clean(filepath.FromSlash(u.Path)), true
+	}
+	return "", false
+}